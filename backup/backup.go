@@ -0,0 +1,470 @@
+// Package backup creates and restores encrypted single-file backups of the password store.
+// A backup is a tar archive holding a manifest, every .gpg entry, the .checker/init.gpg
+// validation file and the recipients and key files of sub-folders. The archive is then
+// encrypted as a whole with a separate backup passphrase.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// FormatVersion is the current version of the backup archive layout.
+// Version 2 added the folders/ directory holding the encryption settings of sub-folders.
+const FormatVersion = 2
+
+// Archive paths used inside the tar file
+const (
+	manifestPath = "manifest.json"
+	entriesDir   = "entries/"
+	checkerPath  = "checker/init.gpg"
+	foldersDir   = "folders/"
+)
+
+// folderFiles are the files in a sub-folder that decide how its entries are encrypted
+var folderFiles = []string{encryption.RecipientsFilename, encryption.FolderKeyFilename + ".gpg"}
+
+// Resolution describes what to do with a backup entry whose filename already exists in the store
+type Resolution int
+
+const (
+	Skip      Resolution = iota // Keep the existing entry and ignore the backup copy
+	Overwrite                   // Replace the existing entry with the backup copy
+	Rename                      // Restore the backup copy under a new, unused filename
+)
+
+// String returns the display name of the resolution
+func (r Resolution) String() string {
+	switch r {
+	case Overwrite:
+		return "Overwrite"
+	case Rename:
+		return "Rename"
+	default:
+		return "Skip"
+	}
+}
+
+// ManifestFile describes a single file stored in the archive
+type ManifestFile struct {
+	Path   string `json:"path"`   // Path inside the archive
+	Size   int64  `json:"size"`   // Size in bytes
+	SHA256 string `json:"sha256"` // Hex-encoded SHA-256 checksum of the file contents
+}
+
+// Manifest lists every file in the archive together with its checksum
+type Manifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Files     []ManifestFile `json:"files"`
+}
+
+// Bundle is a decrypted and verified backup held in memory
+type Bundle struct {
+	Manifest Manifest
+	Entries  map[string][]byte // Entry filename (without .gpg) to armored contents
+	Init     []byte            // Contents of .checker/init.gpg, nil if the backup has none
+	Folders  map[string][]byte // Store-relative path of a sub-folder's recipients or key file to its contents
+}
+
+// Create packs the password store into an archive, encrypts it with the passphrase and writes it to path.
+// The entries are copied as-is, so they remain encrypted with the store's own key inside the backup.
+// The file is replaced atomically and readable by the owner only, even if it already existed.
+// Returns the number of entries written.
+func Create(pf *fileio.PasswordFolder, passphrase string, path string) (int, error) {
+	filenames, err := pf.ListEntryFilenames()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read password store: %v", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, filename := range filenames {
+		contents, err := pf.ReadFromFile(filename)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read '%s.gpg': %v", filename, err)
+		}
+		files[entriesDir+filename+".gpg"] = contents
+	}
+
+	initContents, err := pf.ReadFromFile(encryption.InitFilename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read validation file: %v", err)
+	}
+	files[checkerPath] = initContents
+
+	folders, err := pf.ListFolders()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read password store: %v", err)
+	}
+	for _, folder := range folders {
+		for _, name := range folderFiles {
+			relative := folder + "/" + name
+			contents, err := os.ReadFile(filepath.Join(pf.FolderLocation, filepath.FromSlash(relative)))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to read '%s': %v", relative, err)
+			}
+			files[foldersDir+relative] = contents
+		}
+	}
+
+	archive, err := buildArchive(files)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build archive: %v", err)
+	}
+
+	encrypted, err := encryption.EncryptBytes(archive, []byte(passphrase))
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt backup: %v", err)
+	}
+
+	err = fileio.WriteFileAtomic(path, encrypted, fileio.FilePerm)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write backup file %s: %v", path, err)
+	}
+	return len(filenames), nil
+}
+
+// Open reads and decrypts the backup at path and verifies every file against the manifest.
+// An error is returned if the passphrase is wrong, a file is missing, or a checksum does not match.
+func Open(path string, passphrase string) (*Bundle, error) {
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file %s: %v", path, err)
+	}
+
+	archive, err := encryption.DecryptBytes(encrypted, []byte(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt backup (wrong passphrase?): %v", err)
+	}
+
+	files, err := readArchive(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %v", err)
+	}
+
+	manifestData, ok := files[manifestPath]
+	if !ok {
+		return nil, fmt.Errorf("backup has no manifest")
+	}
+	delete(files, manifestPath)
+
+	manifest := Manifest{}
+	err = json.Unmarshal(manifestData, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+	if manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than supported version %d", manifest.Version, FormatVersion)
+	}
+
+	err = verify(manifest, files)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBundle(manifest, files)
+}
+
+// newBundle sorts the verified files of an archive into a bundle. Only the files Create writes
+// are accepted: a crafted archive could otherwise name hidden files such as the store's
+// .checker/init.gpg, its history or trash, or the recipients of the store itself.
+func newBundle(manifest Manifest, files map[string][]byte) (*Bundle, error) {
+	bundle := &Bundle{
+		Manifest: manifest,
+		Entries:  make(map[string][]byte),
+		Folders:  make(map[string][]byte),
+	}
+	for path, contents := range files {
+		switch {
+		case path == checkerPath:
+			bundle.Init = contents
+		case strings.HasPrefix(path, entriesDir):
+			name := strings.TrimSuffix(strings.TrimPrefix(path, entriesDir), ".gpg")
+			if !strings.HasSuffix(path, ".gpg") || !validStorePath(name) {
+				return nil, fmt.Errorf("invalid entry '%s' in backup", path)
+			}
+			bundle.Entries[name] = contents
+		case strings.HasPrefix(path, foldersDir):
+			relative := strings.TrimPrefix(path, foldersDir)
+			split := strings.LastIndex(relative, "/")
+			if split <= 0 || !validStorePath(relative[:split]) || !slices.Contains(folderFiles, relative[split+1:]) {
+				return nil, fmt.Errorf("invalid folder setting '%s' in backup", path)
+			}
+			bundle.Folders[relative] = contents
+		default:
+			return nil, fmt.Errorf("unexpected file '%s' in backup", path)
+		}
+	}
+	return bundle, nil
+}
+
+// validStorePath reports whether a store-relative path names a visible file or folder: none of
+// its parts may be empty or start with a dot, which would reach the store's own hidden files
+func validStorePath(relative string) bool {
+	for _, part := range strings.Split(relative, "/") {
+		if part == "" || strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// EntryNames returns the filenames of all entries in the bundle in sorted order
+func (b *Bundle) EntryNames() []string {
+	names := make([]string, 0, len(b.Entries))
+	for name := range b.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Collisions returns the sorted names of bundle entries that already exist in the store
+func (b *Bundle) Collisions(pf *fileio.PasswordFolder) []string {
+	var collisions []string
+	for _, name := range b.EntryNames() {
+		if fileio.FileExists(fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, name)) {
+			collisions = append(collisions, name)
+		}
+	}
+	return collisions
+}
+
+// InFolderScope reports whether the entry is below a sub-folder with its own recipients or
+// passphrase in the backup. Such entries are restored as-is together with the folder's settings.
+func (b *Bundle) InFolderScope(name string) bool {
+	for folder := encryption.ParentFolder(name); folder != ""; folder = encryption.ParentFolder(folder) {
+		for _, file := range folderFiles {
+			if _, ok := b.Folders[folder+"/"+file]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reencrypt re-encrypts every entry in the bundle from the backup's keys the way ef encrypts entries.
+// It is used when restoring into a store that has a different data key. Each entry is tried
+// with every key in fromKeys, so backups taken halfway through a key upgrade still restore.
+// Entries in folders with their own encryption are left untouched, see InFolderScope.
+func (b *Bundle) Reencrypt(fromKeys []string, ef *encryption.EncryptionFunctions) error {
+	for name, contents := range b.Entries {
+		if b.InFolderScope(name) {
+			continue
+		}
+		var data encryption.Data
+		var err error
+		for _, key := range fromKeys {
+			data, err = encryption.DecryptData(contents, []byte(key))
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decrypt '%s.gpg' from backup: %v", name, err)
+		}
+		armored, err := ef.EncryptEntry(name, data)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt '%s.gpg': %v", name, err)
+		}
+		b.Entries[name] = armored
+	}
+	return nil
+}
+
+// Restore writes the bundle entries into the store. Entries without a resolution are written
+// directly; colliding entries follow their resolution. Folder settings are restored only where
+// the store has none of its own. Returns the number of entries written.
+func Restore(pf *fileio.PasswordFolder, b *Bundle, resolutions map[string]Resolution) (int, error) {
+	err := pf.CheckWritable()
+	if err != nil {
+		return 0, err
+	}
+	for relative, contents := range b.Folders {
+		target := filepath.Join(pf.FolderLocation, filepath.FromSlash(relative))
+		if fileio.FileExists(target) || hasFolderSettings(pf, path.Dir(relative)) {
+			continue
+		}
+		err := os.MkdirAll(filepath.Dir(target), fileio.DirPerm)
+		if err == nil {
+			err = fileio.WriteFileAtomic(target, contents, fileio.FilePerm)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to restore '%s': %v", relative, err)
+		}
+	}
+
+	restored := 0
+	for _, name := range b.EntryNames() {
+		target := name
+		if fileio.FileExists(fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, name)) {
+			switch resolutions[name] {
+			case Skip:
+				continue
+			case Overwrite:
+				// The replaced version stays in the entry's history
+				err := pf.SaveRevision(name)
+				if err != nil {
+					return restored, err
+				}
+			case Rename:
+				target = uniqueName(pf, name)
+			}
+		}
+
+		err := pf.WriteToFile(target, b.Entries[name])
+		if err != nil {
+			return restored, fmt.Errorf("failed to restore '%s.gpg': %v", name, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// hasFolderSettings reports whether a sub-folder of the store already has a recipients or key file
+func hasFolderSettings(pf *fileio.PasswordFolder, folder string) bool {
+	for _, file := range folderFiles {
+		if fileio.FileExists(filepath.Join(pf.FolderLocation, filepath.FromSlash(folder), file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueName returns a filename based on name that does not exist in the store
+func uniqueName(pf *fileio.PasswordFolder, name string) string {
+	candidate := name + "_restored"
+	for i := 2; fileio.FileExists(fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, candidate)); i++ {
+		candidate = fmt.Sprintf("%s_restored%d", name, i)
+	}
+	return candidate
+}
+
+// buildArchive writes the manifest followed by every file into an in-memory tar archive
+func buildArchive(files map[string][]byte) ([]byte, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	manifest := Manifest{
+		Version:   FormatVersion,
+		CreatedAt: time.Now(),
+	}
+	for _, path := range paths {
+		sum := sha256.Sum256(files[path])
+		manifest.Files = append(manifest.Files, ManifestFile{
+			Path:   path,
+			Size:   int64(len(files[path])),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+
+	err = writeTarFile(writer, manifestPath, manifestData, manifest.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		err = writeTarFile(writer, path, files[path], manifest.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// writeTarFile adds a single regular file to the tar archive
+func writeTarFile(writer *tar.Writer, path string, contents []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    path,
+		Mode:    0600,
+		Size:    int64(len(contents)),
+		ModTime: modTime,
+	}
+	err := writer.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(contents)
+	return err
+}
+
+// readArchive extracts every regular file of a tar archive into memory
+func readArchive(archive []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Reject paths that could escape the store when restored
+		if strings.Contains(header.Name, "..") || strings.HasPrefix(header.Name, "/") {
+			return nil, fmt.Errorf("invalid path '%s' in archive", header.Name)
+		}
+
+		contents, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = contents
+	}
+	return files, nil
+}
+
+// verify checks that the archive contents match the manifest exactly
+func verify(manifest Manifest, files map[string][]byte) error {
+	if len(manifest.Files) != len(files) {
+		return fmt.Errorf("manifest lists %d files but archive contains %d", len(manifest.Files), len(files))
+	}
+	for _, file := range manifest.Files {
+		contents, ok := files[file.Path]
+		if !ok {
+			return fmt.Errorf("file '%s' listed in manifest is missing", file.Path)
+		}
+		sum := sha256.Sum256(contents)
+		if int64(len(contents)) != file.Size || hex.EncodeToString(sum[:]) != file.SHA256 {
+			return fmt.Errorf("checksum mismatch for '%s'", file.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+const testPassphrase = "backup passphrase"
+
+// newTestStore creates an unlocked store in a temporary directory holding entries, keyed by
+// filename with their passwords as values
+func newTestStore(t *testing.T, entries map[string]string) (*fileio.PasswordFolder, *encryption.EncryptionFunctions) {
+	t.Helper()
+	pf, err := fileio.OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The iterated key derivation keeps the tests quick
+	err = encryption.SaveCryptoConfig(pf.FolderLocation, encryption.DefaultCryptoConfig("rfc4880"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ef := encryption.NewEncryption(pf)
+	err = ef.InitStore("master password")
+	if err != nil {
+		t.Fatalf("InitStore: %v", err)
+	}
+	for name, password := range entries {
+		writeEntry(t, ef, name, password)
+	}
+	return pf, ef
+}
+
+// writeEntry encrypts an entry into the store
+func writeEntry(t *testing.T, ef *encryption.EncryptionFunctions, name, password string) {
+	t.Helper()
+	err := ef.EncryptPasswordAndWriteToFile(name, encryption.Data{Password: password})
+	if err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+// readPassword decrypts an entry of the store, empty if it does not exist
+func readPassword(t *testing.T, ef *encryption.EncryptionFunctions, name string) string {
+	t.Helper()
+	data, err := ef.DecryptPasswordFromFile(name)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+	return data.Password
+}
+
+// createBackup backs up the store to a file in a temporary directory
+func createBackup(t *testing.T, pf *fileio.PasswordFolder) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "vault.backup")
+	_, err := Create(pf, testPassphrase, path)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return path
+}
+
+func TestCreateOpen(t *testing.T) {
+	pf, _ := newTestStore(t, map[string]string{"github": "hunter2", "work/aws": "s3cret"})
+	err := os.WriteFile(filepath.Join(pf.FolderLocation, "work", encryption.RecipientsFilename), []byte("keys"), fileio.FilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An existing file is replaced with owner-only permissions
+	path := filepath.Join(t.TempDir(), "vault.backup")
+	err = os.WriteFile(path, []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := Create(pf, testPassphrase, path)
+	if err != nil || count != 2 {
+		t.Fatalf("Create = %d, %v, want 2 entries", count, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("backup has permissions %o, want 600", perm)
+	}
+
+	bundle, err := Open(path, testPassphrase)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if names := bundle.EntryNames(); !slices.Equal(names, []string{"github", "work/aws"}) {
+		t.Errorf("EntryNames = %v, want github and work/aws", names)
+	}
+	stored, err := pf.ReadFromFile("github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bundle.Entries["github"], stored) {
+		t.Error("the backed up entry differs from the store's")
+	}
+	if bundle.Init == nil || string(bundle.Folders["work/"+encryption.RecipientsFilename]) != "keys" {
+		t.Error("the key file or folder settings are missing from the backup")
+	}
+	if !bundle.InFolderScope("work/aws") || bundle.InFolderScope("github") {
+		t.Error("InFolderScope does not follow the folder settings in the backup")
+	}
+}
+
+func TestOpenWrongPassphrase(t *testing.T) {
+	pf, _ := newTestStore(t, map[string]string{"github": "hunter2"})
+	path := createBackup(t, pf)
+
+	_, err := Open(path, "not the passphrase")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Open with a wrong passphrase = %v", err)
+	}
+}
+
+func TestOpenRejectsTamperedArchive(t *testing.T) {
+	pf, _ := newTestStore(t, map[string]string{"github": "hunter2"})
+	path := createBackup(t, pf)
+
+	tests := []struct {
+		name   string
+		tamper func(files map[string][]byte)
+	}{
+		{"changed entry", func(files map[string][]byte) { files[entriesDir+"github.gpg"] = []byte("changed") }},
+		{"added entry", func(files map[string][]byte) { files[entriesDir+"extra.gpg"] = []byte("extra") }},
+		{"removed entry", func(files map[string][]byte) { delete(files, entriesDir+"github.gpg") }},
+		{"no manifest", func(files map[string][]byte) { delete(files, manifestPath) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files := readBackup(t, path)
+			test.tamper(files)
+			tampered := filepath.Join(t.TempDir(), "tampered.backup")
+			writeBackup(t, tampered, files)
+
+			if _, err := Open(tampered, testPassphrase); err == nil {
+				t.Error("Open accepted a tampered backup")
+			}
+		})
+	}
+}
+
+// readBackup decrypts a backup and returns every file of its archive, manifest included
+func readBackup(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := encryption.DecryptBytes(encrypted, []byte(testPassphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := readArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// writeBackup archives files as they are, keeping whatever manifest they hold, and encrypts them to path
+func writeBackup(t *testing.T, path string, files map[string][]byte) {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for name, contents := range files {
+		err := writeTarFile(writer, name, contents, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := encryption.EncryptBytes(buffer.Bytes(), []byte(testPassphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, encrypted, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreResolutions(t *testing.T) {
+	tests := []struct {
+		resolution Resolution
+		count      int               // Entries Restore reports writing
+		want       map[string]string // Passwords of the entries afterwards, empty for none
+		revisions  int               // Revisions of github afterwards
+	}{
+		{Skip, 1, map[string]string{"github": "changed", "bank": "pin", "github_restored": ""}, 1},
+		{Overwrite, 2, map[string]string{"github": "hunter2", "bank": "pin", "github_restored": ""}, 2},
+		{Rename, 2, map[string]string{"github": "changed", "bank": "pin", "github_restored": "hunter2"}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.resolution.String(), func(t *testing.T) {
+			pf, ef := newTestStore(t, map[string]string{"github": "hunter2", "bank": "pin"})
+			path := createBackup(t, pf)
+
+			// After the backup one entry is edited and the other deleted
+			writeEntry(t, ef, "github", "changed")
+			err := pf.DeleteFile("bank")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			bundle, err := Open(path, testPassphrase)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if collisions := bundle.Collisions(pf); !slices.Equal(collisions, []string{"github"}) {
+				t.Fatalf("Collisions = %v, want github", collisions)
+			}
+			count, err := Restore(pf, bundle, map[string]Resolution{"github": test.resolution})
+			if err != nil || count != test.count {
+				t.Fatalf("Restore = %d, %v, want %d", count, err, test.count)
+			}
+			for name, want := range test.want {
+				if got := readPassword(t, ef, name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			revisions, err := pf.ListRevisions("github")
+			if err != nil || len(revisions) != test.revisions {
+				t.Errorf("github has %d revisions (%v), want %d", len(revisions), err, test.revisions)
+			}
+		})
+	}
+}
+
+func TestRestoreIntoStoreWithAnotherKey(t *testing.T) {
+	source, sourceEF := newTestStore(t, map[string]string{"github": "hunter2"})
+	path := createBackup(t, source)
+	target, targetEF := newTestStore(t, nil)
+
+	bundle, err := Open(path, testPassphrase)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	err = bundle.Reencrypt([]string{"a wrong key"}, targetEF)
+	if err == nil {
+		t.Error("Reencrypt worked without the backup's key")
+	}
+	err = bundle.Reencrypt([]string{"a wrong key", source.Password}, targetEF)
+	if err != nil {
+		t.Fatalf("Reencrypt: %v", err)
+	}
+	_, err = Restore(target, bundle, nil)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := readPassword(t, targetEF, "github"); got != "hunter2" {
+		t.Errorf("restored github = %q, want hunter2", got)
+	}
+	if readPassword(t, sourceEF, "github") != "hunter2" {
+		t.Error("the source store was changed")
+	}
+}
+
+func TestNewBundleSortsFiles(t *testing.T) {
+	files := map[string][]byte{
+		checkerPath:                              []byte("init"),
+		entriesDir + "github.gpg":                []byte("github"),
+		entriesDir + "work/aws.gpg":              []byte("aws"),
+		foldersDir + "work/.recipients":          []byte("recipients"),
+		foldersDir + "work/team/.folder-key.gpg": []byte("key"),
+	}
+
+	bundle, err := newBundle(Manifest{}, files)
+	if err != nil {
+		t.Fatalf("newBundle: %v", err)
+	}
+	if string(bundle.Init) != "init" {
+		t.Errorf("Init = %q, want %q", bundle.Init, "init")
+	}
+	if len(bundle.Entries) != 2 || string(bundle.Entries["work/aws"]) != "aws" {
+		t.Errorf("Entries = %v, want github and work/aws", bundle.Entries)
+	}
+	if len(bundle.Folders) != 2 || string(bundle.Folders["work/.recipients"]) != "recipients" {
+		t.Errorf("Folders = %v, want the settings of work and work/team", bundle.Folders)
+	}
+}
+
+func TestNewBundleRejectsHiddenPaths(t *testing.T) {
+	paths := []string{
+		entriesDir + ".checker/init.gpg",
+		entriesDir + ".history/github/20240101T000000.gpg",
+		entriesDir + ".trash/20240101T000000/github.gpg",
+		entriesDir + "work/.hidden.gpg",
+		entriesDir + "work//github.gpg",
+		entriesDir + "github.txt",
+		foldersDir + ".recipients",
+		foldersDir + "/.recipients",
+		foldersDir + ".checker/.recipients",
+		foldersDir + "work//.recipients",
+		foldersDir + "work/github.gpg",
+		"extra.txt",
+	}
+	for _, path := range paths {
+		_, err := newBundle(Manifest{}, map[string][]byte{path: []byte("x")})
+		if err == nil {
+			t.Errorf("newBundle accepted %q", path)
+		}
+	}
+}
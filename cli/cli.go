@@ -0,0 +1,325 @@
+// Package cli provides non-interactive subcommands for using the password store from scripts.
+// Each command reads the master password from the environment or stdin instead of the TUI,
+// and exits with a code that tells scripts what went wrong.
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/gitstore"
+	"github.com/Fozzyack/password-manager/throttle"
+	"github.com/charmbracelet/x/term"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK            = 0 // The command succeeded
+	ExitError         = 1 // Usage errors and anything not covered below
+	ExitWrongPassword = 2 // The master password was rejected
+	ExitNotFound      = 3 // The requested entry or store does not exist
+	ExitIOError       = 4 // Reading or writing the store or an output file failed
+	ExitLocked        = 5 // Another instance holds the store's lock, so it cannot be changed
+)
+
+// PasswordEnv is the environment variable checked for the master password before stdin
+const PasswordEnv = "PASSWORD_MANAGER_PASSWORD"
+
+// Settings holds the store options resolved from the global flags and config file
+type Settings struct {
+	Location         string // Path of the password store to open
+	MaxLoginAttempts int    // Failed unlocks before the store locks out, 0 for no limit
+	Keyring          string // Private key file used to open recipient vaults
+}
+
+// cliError pairs an error with the exit code it should produce
+type cliError struct {
+	code int
+	err  error
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+// newError creates an error that exits with the given code
+func newError(code int, format string, args ...any) error {
+	return &cliError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// command describes a single subcommand
+type command struct {
+	usage       string
+	description string
+	run         func(ctx *context, args []string) error
+}
+
+// commands lists every subcommand by name
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"ls":       {"ls [--tag t] [--json]", "List all entries", runList},
+		"show":     {"show <name> [--field password|username|email|url|otp|all] [--json]", "Print an entry (the password by default)", runShow},
+		"add":      {"add [folder/]<site> [--username u] [--email e] [--url u] [--otp key] [--tags a,b] [--generate] [--length n]", "Add an entry; the password is read from stdin unless --generate is set", runAdd},
+		"rm":       {"rm <name>", "Move an entry to the trash", runRemove},
+		"generate": {"generate [--length n] [--no-upper] [--no-lower] [--no-numbers] [--no-symbols] [--allow-ambiguous]", "Print a random password", runGenerate},
+		"export":   {"export [--format csv|json] [--output path|-]", "Export all entries in plaintext", runExport},
+	}
+}
+
+// commandOrder is the order commands are listed in the usage text
+var commandOrder = []string{"ls", "show", "add", "rm", "generate", "export"}
+
+// context holds the streams and store shared by a single command invocation
+type context struct {
+	stdin          *bufio.Reader
+	stdinFile      *os.File // Set when stdin is a real file, used to detect a terminal
+	stdout         io.Writer
+	stderr         io.Writer
+	settings       Settings
+	passwordFolder *fileio.PasswordFolder
+	encryption     *encryption.EncryptionFunctions
+}
+
+// Run executes the subcommand in args against the configured store and returns the process exit code
+func Run(args []string, settings Settings, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		PrintUsage(stdout)
+		return ExitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		PrintUsage(stderr)
+		return ExitError
+	}
+
+	ctx := &context{
+		stdin:    bufio.NewReader(stdin),
+		stdout:   stdout,
+		stderr:   stderr,
+		settings: settings,
+	}
+	if file, ok := stdin.(*os.File); ok {
+		ctx.stdinFile = file
+	}
+
+	err := cmd.run(ctx, args[1:])
+	if ctx.passwordFolder != nil {
+		releaseErr := ctx.passwordFolder.ReleaseLock()
+		if releaseErr != nil {
+			fmt.Fprintf(stderr, "warning: failed to release the store lock: %v\n", releaseErr)
+		}
+	}
+	if err == nil {
+		return ExitOK
+	}
+
+	fmt.Fprintf(stderr, "error: %v\n", err)
+	var ce *cliError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return ExitError
+}
+
+// PrintUsage writes the list of commands and exit codes
+func PrintUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: password-manager [--store path | --vault name] [--config path] [command] [flags]\n\n")
+	fmt.Fprintf(w, "Run without a command to start the interactive interface.\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	for _, name := range commandOrder {
+		fmt.Fprintf(w, "  %s\n      %s\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintf(w, "\nThe master password is read from $%s, or from the first line of stdin.\n", PasswordEnv)
+	fmt.Fprintf(w, "\nExit codes: %d ok, %d error, %d wrong password, %d not found, %d I/O error, %d store in use\n",
+		ExitOK, ExitError, ExitWrongPassword, ExitNotFound, ExitIOError, ExitLocked)
+}
+
+// parseFlags parses flags that may appear before, between or after positional arguments
+// and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, &cliError{code: ExitError, err: err}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet creates a flag set that reports errors instead of exiting
+func newFlagSet(ctx *context, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ctx.stderr)
+	return fs
+}
+
+// readLine reads a single line from stdin without the trailing newline
+func (ctx *context) readLine() (string, error) {
+	line, err := ctx.stdin.ReadString('\n')
+	if err != nil && !(err == io.EOF && line != "") {
+		return "", newError(ExitIOError, "failed to read from stdin: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readSecret reads a secret, prompting without echo when stdin is a terminal
+func (ctx *context) readSecret(prompt string) (string, error) {
+	if ctx.stdinFile != nil && term.IsTerminal(ctx.stdinFile.Fd()) {
+		fmt.Fprint(ctx.stderr, prompt)
+		secret, err := term.ReadPassword(ctx.stdinFile.Fd())
+		fmt.Fprintln(ctx.stderr)
+		if err != nil {
+			return "", newError(ExitIOError, "failed to read from terminal: %v", err)
+		}
+		return string(secret), nil
+	}
+	return ctx.readLine()
+}
+
+// unlock opens the password store and unlocks it with the master password. Unlike the
+// interface, commands never create a store, so a mistyped --store or --vault fails instead.
+func (ctx *context) unlock() error {
+	info, err := os.Stat(ctx.settings.Location)
+	if os.IsNotExist(err) {
+		return newError(ExitNotFound, "password store %s does not exist, run the interactive interface to create it", ctx.settings.Location)
+	}
+	if err != nil {
+		return newError(ExitIOError, "could not open password store: %v", err)
+	}
+	if !info.IsDir() {
+		return newError(ExitError, "password store %s is not a directory", ctx.settings.Location)
+	}
+	// Opening the folder would create .checker, so an uninitialised directory is left untouched
+	keyFile := filepath.Join(ctx.settings.Location, filepath.FromSlash(encryption.InitFilename)+".gpg")
+	recipients := filepath.Join(ctx.settings.Location, encryption.RecipientsFilename)
+	if !fileio.FileExists(keyFile) && !fileio.FileExists(recipients) {
+		return newError(ExitError, "password store is not initialised, run the interactive interface first")
+	}
+
+	ctx.passwordFolder, err = fileio.OpenPasswordFolder(ctx.settings.Location)
+	if err != nil {
+		return newError(ExitIOError, "could not open password store: %v", err)
+	}
+	ctx.encryption = encryption.NewEncryption(ctx.passwordFolder)
+	ctx.encryption.KeyringPath = ctx.settings.Keyring
+	issues, err := ctx.passwordFolder.CheckPermissions()
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	if len(issues) > 0 {
+		fmt.Fprintf(ctx.stderr, "warning: %d files or folders in the store can be accessed by other users, open the interface to fix them\n", len(issues))
+	}
+
+	// Scripts are not made to wait: refuse outright while locked out or backing off
+	attempts, err := throttle.Load(ctx.settings.Location, ctx.settings.MaxLoginAttempts)
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	if attempts.LockedOut() {
+		return &cliError{code: ExitWrongPassword, err: attempts.LockoutError()}
+	}
+	if delay := attempts.Delay(); delay > 0 {
+		return newError(ExitWrongPassword, "too many failed attempts, try again in %s", delay.Round(time.Second))
+	}
+
+	masterPassword := os.Getenv(PasswordEnv)
+	if masterPassword == "" {
+		masterPassword, err = ctx.readSecret("Master password: ")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ctx.encryption.Unlock(masterPassword)
+	if err != nil && !errors.Is(err, encryption.ErrWrongPassword) {
+		return newError(ExitError, "%v", err)
+	}
+	if err != nil {
+		recordErr := attempts.RecordFailure()
+		if recordErr != nil {
+			return newError(ExitIOError, "%v", recordErr)
+		}
+		return newError(ExitWrongPassword, "%v", err)
+	}
+
+	if attempts.Failures > 0 {
+		fmt.Fprintf(ctx.stderr, "warning: %d failed login attempt(s) since the last login\n", attempts.Failures)
+	}
+	err = attempts.RecordSuccess()
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	return nil
+}
+
+// lock takes the store's lock for a command that writes to it. Fails with ExitLocked while
+// another instance, such as the interactive interface, holds it.
+func (ctx *context) lock() error {
+	err := ctx.passwordFolder.AcquireLock()
+	var locked *fileio.LockedError
+	if errors.As(err, &locked) {
+		return &cliError{code: ExitLocked, err: err}
+	}
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	return nil
+}
+
+// commit records a change in the store's git repository, if it has one. The change has
+// already been saved, so a failed commit is only a warning.
+func (ctx *context) commit(format string, args ...any) {
+	err := gitstore.Open(ctx.passwordFolder.FolderLocation).Commit(fmt.Sprintf(format, args...))
+	if err != nil {
+		fmt.Fprintf(ctx.stderr, "warning: the change was saved but not committed to git: %v\n", err)
+	}
+}
+
+// unlockFolder asks for the passphrase of a folder whose entries are protected separately:
+// the folder's own passphrase, or the private key passphrase for folders with recipients.
+// It reads the next line of stdin, after the master password.
+func (ctx *context) unlockFolder(folder string) error {
+	scope, err := ctx.encryption.FolderScope(folder)
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	if ctx.encryption.ScopeUnlocked(scope) {
+		return nil
+	}
+
+	prompt := fmt.Sprintf("Passphrase for %s: ", scope.Folder)
+	if scope.Mode == encryption.RecipientScope {
+		prompt = "Private key passphrase: "
+	}
+	secret, err := ctx.readSecret(prompt)
+	if err != nil {
+		return err
+	}
+	err = ctx.encryption.UnlockFolder(folder, secret)
+	if errors.Is(err, encryption.ErrWrongPassword) {
+		return newError(ExitWrongPassword, "incorrect passphrase for %s", scope.Folder)
+	}
+	if err != nil {
+		return newError(ExitError, "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+const testMasterPassword = "correct horse battery"
+
+// newTestStore creates an initialised store in a temporary directory. The faster iterated
+// key derivation keeps the tests quick; the commands do not depend on it.
+func newTestStore(t *testing.T) string {
+	t.Helper()
+	location := t.TempDir()
+	err := os.Chmod(location, fileio.DirPerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pf, err := fileio.OpenPasswordFolder(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = encryption.SaveCryptoConfig(location, encryption.DefaultCryptoConfig("rfc4880"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = encryption.NewEncryption(pf).InitStore(testMasterPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+// run runs a command against the store and returns its exit code, stdout and stderr
+func run(t *testing.T, location string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(args, Settings{Location: location}, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestUsage(t *testing.T) {
+	code, stdout, _ := run(t, t.TempDir(), "", "help")
+	if code != ExitOK || !strings.Contains(stdout, "Commands:") {
+		t.Errorf("help exited %d with %q", code, stdout)
+	}
+
+	code, _, stderr := run(t, t.TempDir(), "", "frobnicate")
+	if code != ExitError || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("unknown command exited %d with %q", code, stderr)
+	}
+}
+
+func TestAddShowRemove(t *testing.T) {
+	location := newTestStore(t)
+	t.Setenv(PasswordEnv, testMasterPassword)
+
+	code, stdout, stderr := run(t, location, "hunter2\n", "add", "work/github", "--username", "octocat", "--tags", "Dev,work")
+	if code != ExitOK {
+		t.Fatalf("add exited %d: %s", code, stderr)
+	}
+	filename := strings.TrimSpace(stdout)
+	if !strings.HasPrefix(filename, "work/github_") {
+		t.Errorf("add printed %q, want the new filename in work/", filename)
+	}
+
+	code, stdout, stderr = run(t, location, "", "show", "work/github")
+	if code != ExitOK || stdout != "hunter2\n" {
+		t.Errorf("show exited %d with %q (%s), want the password", code, stdout, stderr)
+	}
+	code, stdout, _ = run(t, location, "", "show", "github", "--field", "username")
+	if code != ExitOK || stdout != "octocat\n" {
+		t.Errorf("show --field username exited %d with %q", code, stdout)
+	}
+
+	code, stdout, _ = run(t, location, "", "ls", "--tag", "dev")
+	if code != ExitOK || !strings.Contains(stdout, "github") {
+		t.Errorf("ls --tag dev exited %d with %q", code, stdout)
+	}
+	code, stdout, _ = run(t, location, "", "ls", "--tag", "home")
+	if code != ExitOK || strings.Contains(stdout, "github") {
+		t.Errorf("ls --tag home exited %d with %q, want no entries", code, stdout)
+	}
+
+	code, _, stderr = run(t, location, "", "rm", "github")
+	if code != ExitOK {
+		t.Fatalf("rm exited %d: %s", code, stderr)
+	}
+	code, _, _ = run(t, location, "", "show", "github")
+	if code != ExitNotFound {
+		t.Errorf("show after rm exited %d, want %d", code, ExitNotFound)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	location := newTestStore(t)
+	missing := filepath.Join(t.TempDir(), "typo")
+	empty := t.TempDir()
+
+	tests := []struct {
+		name     string
+		location string
+		password string
+		stdin    string
+		args     []string
+		want     int
+	}{
+		{"master password from stdin", location, "", testMasterPassword + "\n", []string{"ls"}, ExitOK},
+		{"missing entry", location, testMasterPassword, "", []string{"show", "nothing-here"}, ExitNotFound},
+		{"missing store", missing, testMasterPassword, "", []string{"ls"}, ExitNotFound},
+		{"uninitialised store", empty, testMasterPassword, "", []string{"ls"}, ExitError},
+		{"no entry name", location, testMasterPassword, "", []string{"show"}, ExitError},
+		{"bad flag", location, testMasterPassword, "", []string{"ls", "--colour"}, ExitError},
+		{"empty password", location, testMasterPassword, "\n", []string{"add", "site"}, ExitError},
+		{"generate", location, "", "", []string{"generate", "--length", "20"}, ExitOK},
+		{"generate too short", location, "", "", []string{"generate", "--length", "3"}, ExitError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(PasswordEnv, test.password)
+			code, _, stderr := run(t, test.location, test.stdin, test.args...)
+			if code != test.want {
+				t.Errorf("exited %d, want %d (%s)", code, test.want, stderr)
+			}
+		})
+	}
+
+	// A mistyped store must not be created by a command
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("the missing store was created: %v", err)
+	}
+	if entries, err := os.ReadDir(empty); err != nil || len(entries) != 0 {
+		t.Errorf("the uninitialised store was changed: %v, %v", entries, err)
+	}
+}
+
+func TestWrongPasswordIsThrottled(t *testing.T) {
+	location := newTestStore(t)
+	t.Setenv(PasswordEnv, "not the password")
+
+	code, _, _ := run(t, location, "", "ls")
+	if code != ExitWrongPassword {
+		t.Fatalf("first attempt exited %d, want %d", code, ExitWrongPassword)
+	}
+
+	// The next attempt is refused before the password is even checked
+	t.Setenv(PasswordEnv, testMasterPassword)
+	code, _, stderr := run(t, location, "", "ls")
+	if code != ExitWrongPassword || !strings.Contains(stderr, "try again") {
+		t.Errorf("attempt during the backoff exited %d with %q", code, stderr)
+	}
+}
+
+func TestStoreInUse(t *testing.T) {
+	location := newTestStore(t)
+	t.Setenv(PasswordEnv, testMasterPassword)
+
+	// Another instance, such as the interface on a machine sharing the store, holds the lock
+	holder, err := json.Marshal(fileio.LockInfo{PID: 1234, Host: "another-machine", CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(location, fileio.LockFilename), holder, fileio.FilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, _ := run(t, location, "hunter2\n", "add", "github")
+	if code != ExitLocked {
+		t.Errorf("add exited %d, want %d", code, ExitLocked)
+	}
+	code, _, _ = run(t, location, "", "ls")
+	if code != ExitOK {
+		t.Errorf("ls exited %d, want reading to work while the store is in use", code)
+	}
+}
+
+func TestGenerateLength(t *testing.T) {
+	code, stdout, _ := run(t, t.TempDir(), "", "generate", "--length", "24", "--no-symbols")
+	password := strings.TrimSpace(stdout)
+	if code != ExitOK || len(password) != 24 {
+		t.Errorf("generate exited %d with %q, want 24 characters", code, password)
+	}
+	if strings.ContainsAny(password, "!@#$%^&*") {
+		t.Errorf("generate --no-symbols printed %q", password)
+	}
+}
+
+func TestExportUnlocksFolders(t *testing.T) {
+	location := newTestStore(t)
+	t.Setenv(PasswordEnv, testMasterPassword)
+	pf, err := fileio.OpenPasswordFolder(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ef := encryption.NewEncryption(pf)
+	err = ef.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Mkdir(filepath.Join(location, "work"), fileio.DirPerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ef.SetFolderPassphrase("work", "folder passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"github", "work/aws"} {
+		err = ef.EncryptPasswordAndWriteToFile(name, encryption.Data{Password: "secret " + name})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	code, _, stderr := run(t, location, "wrong passphrase\n", "export", "--format", "json")
+	if code != ExitWrongPassword {
+		t.Errorf("export with a wrong folder passphrase exited %d: %s", code, stderr)
+	}
+	code, stdout, stderr := run(t, location, "folder passphrase\n", "export", "--format", "json")
+	if code != ExitOK {
+		t.Fatalf("export exited %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "secret github") || !strings.Contains(stdout, "secret work/aws") {
+		t.Errorf("export = %s, want both entries", stdout)
+	}
+}
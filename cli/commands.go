@@ -0,0 +1,490 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/Fozzyack/password-manager/otp"
+	"github.com/Fozzyack/password-manager/templates"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// listedEntry is the JSON representation of an entry in `ls --json` and `show --json`
+type listedEntry struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"` // login, card, note, wifi, ssh, database or api
+	Folder      string             `json:"folder,omitempty"`
+	SiteName    string             `json:"site_name"`
+	Username    string             `json:"username"`
+	Email       string             `json:"email"`
+	URL         string             `json:"url"`
+	Password    string             `json:"password,omitempty"`
+	OTP         string             `json:"otp,omitempty"`
+	Fields      []encryption.Field `json:"fields,omitempty"`
+	Attachments []string           `json:"attachments,omitempty"` // Names only, save them from the interface
+	Tags        []string           `json:"tags,omitempty"`
+	Favourite   bool               `json:"favourite,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// runList prints every entry, one per line as tab separated name, site, username and email,
+// optionally only those with a tag. Entries in folders with their own passphrase or recipients are skipped unless they can be
+// read with the keys already unlocked.
+func runList(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "ls")
+	asJSON := fs.Bool("json", false, "print entries as a JSON array")
+	tag := fs.String("tag", "", "only list entries with this tag")
+	_, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.unlock()
+	if err != nil {
+		return err
+	}
+
+	filenames, err := ctx.passwordFolder.ListEntryFilenames()
+	if err != nil {
+		return newError(ExitIOError, "failed to read password store: %v", err)
+	}
+
+	entries := make([]listedEntry, 0, len(filenames))
+	locked := make(map[string]bool) // Locked folders already warned about
+	for _, filename := range filenames {
+		scope, err := ctx.encryption.ScopeOf(filename)
+		if err != nil {
+			return newError(ExitIOError, "%v", err)
+		}
+		if !ctx.encryption.ScopeUnlocked(scope) {
+			if !locked[scope.Folder] {
+				fmt.Fprintf(ctx.stderr, "warning: skipping locked folder '%s', use show to open its entries\n", scope.Folder)
+				locked[scope.Folder] = true
+			}
+			continue
+		}
+		data, err := ctx.encryption.DecryptPasswordFromFile(filename)
+		if err != nil {
+			fmt.Fprintf(ctx.stderr, "warning: skipping '%s.gpg': %v\n", filename, err)
+			continue
+		}
+		if *tag != "" && !slices.Contains(data.Tags, utils.CleanTag(*tag)) {
+			continue
+		}
+		entry := newListedEntry(filename, data)
+		entry.Password = ""
+		entry.OTP = ""
+		entry.Fields = nil
+		entries = append(entries, entry)
+	}
+
+	if *asJSON {
+		return writeJSON(ctx, entries)
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(ctx.stdout, "%s\t%s\t%s\t%s\n", entry.Name, entry.SiteName, entry.Username, entry.Email)
+	}
+	return nil
+}
+
+// runShow prints one field of an entry, all of its fields, or the entry as JSON
+func runShow(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "show")
+	field := fs.String("field", "password", "field to print: password, username, email, url, otp, all or a custom field's name")
+	asJSON := fs.Bool("json", false, "print the entry as JSON")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return newError(ExitError, "usage: %s", commands["show"].usage)
+	}
+
+	err = ctx.unlock()
+	if err != nil {
+		return err
+	}
+
+	filename, err := ctx.findEntry(positional[0])
+	if err != nil {
+		return err
+	}
+	err = ctx.unlockFolder(encryption.ParentFolder(filename))
+	if err != nil {
+		return err
+	}
+
+	data, err := ctx.encryption.DecryptPasswordFromFile(filename)
+	if err != nil {
+		return newError(ExitIOError, "failed to decrypt '%s.gpg': %v", filename, err)
+	}
+	entry := newListedEntry(filename, data)
+
+	if *asJSON {
+		return writeJSON(ctx, entry)
+	}
+
+	switch *field {
+	case "password":
+		fmt.Fprintln(ctx.stdout, entry.Password)
+	case "username":
+		fmt.Fprintln(ctx.stdout, entry.Username)
+	case "email":
+		fmt.Fprintln(ctx.stdout, entry.Email)
+	case "url":
+		fmt.Fprintln(ctx.stdout, entry.URL)
+	case "otp":
+		code, err := ctx.otpCode(filename, data)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.stdout, code)
+	case "all":
+		fmt.Fprintf(ctx.stdout, "Site: %s\nType: %s\nUsername: %s\nEmail: %s\nURL: %s\nPassword: %s\n",
+			entry.SiteName, templates.For(data.Type).Name, entry.Username, entry.Email, entry.URL, entry.Password)
+		for _, custom := range entry.Fields {
+			fmt.Fprintf(ctx.stdout, "%s: %s\n", custom.Name, custom.Value)
+		}
+		for _, attachment := range entry.Attachments {
+			fmt.Fprintf(ctx.stdout, "Attachment: %s\n", attachment)
+		}
+		fmt.Fprintf(ctx.stdout, "Created: %s\nUpdated: %s\n",
+			entry.CreatedAt.Format(time.RFC3339), entry.UpdatedAt.Format(time.RFC3339))
+	default:
+		custom, ok := findField(entry.Fields, *field)
+		if !ok {
+			return newError(ExitError, "unknown field %q", *field)
+		}
+		fmt.Fprintln(ctx.stdout, custom.Value)
+	}
+	return nil
+}
+
+// findField returns the custom field with the given name, ignoring case
+func findField(fields []encryption.Field, name string) (encryption.Field, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return encryption.Field{}, false
+}
+
+// otpCode returns the current code of an entry's 2FA key. HOTP keys move on to their next
+// code, so the store is locked and the counter saved before the code is returned.
+func (ctx *context) otpCode(filename string, data encryption.Data) (string, error) {
+	if data.OTP == "" {
+		return "", newError(ExitError, "%s.gpg has no 2FA key", filename)
+	}
+	key, err := otp.Parse(data.OTP)
+	if err != nil {
+		return "", newError(ExitError, "%v", err)
+	}
+	if key.Type == otp.TOTP {
+		code, err := key.Code(time.Now())
+		if err != nil {
+			return "", newError(ExitError, "%v", err)
+		}
+		return code, nil
+	}
+
+	err = ctx.lock()
+	if err != nil {
+		return "", err
+	}
+	code, key, err := key.Next()
+	if err != nil {
+		return "", newError(ExitError, "%v", err)
+	}
+	data.OTP = key.URI()
+	err = ctx.encryption.RewriteEntry(filename, data)
+	if err != nil {
+		return "", newError(ExitIOError, "failed to save the HOTP counter: %v", err)
+	}
+	ctx.commit("Advance HOTP counter of %s", filename)
+	return code, nil
+}
+
+// runAdd creates a new entry. The site may be prefixed with a folder, as in work/aws/prod.
+// The password is read from the next line of stdin (after the master password and any
+// folder passphrase) unless --generate is given.
+func runAdd(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "add")
+	username := fs.String("username", "", "username for the entry")
+	email := fs.String("email", "", "email for the entry")
+	url := fs.String("url", "", "URL for the entry")
+	otpKey := fs.String("otp", "", "2FA key as an otpauth:// URI or base32 secret")
+	tags := fs.String("tags", "", "comma separated tags for the entry")
+	generate := fs.Bool("generate", false, "generate a random password instead of reading one from stdin")
+	length := fs.Int("length", utils.DefaultPasswordOptions().Length, "length of the generated password")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || utils.SanitizeInput(positional[0]) == "" {
+		return newError(ExitError, "usage: %s", commands["add"].usage)
+	}
+	siteName := utils.SanitizeInput(positional[0])
+	folder := utils.CleanFolderPath(encryption.ParentFolder(siteName))
+	siteName = siteName[strings.LastIndex(siteName, "/")+1:]
+	if siteName == "" {
+		return newError(ExitError, "usage: %s", commands["add"].usage)
+	}
+
+	otpURI, err := otp.Normalize(*otpKey)
+	if err != nil {
+		return newError(ExitError, "invalid --otp: %v", err)
+	}
+
+	err = ctx.unlock()
+	if err != nil {
+		return err
+	}
+	err = ctx.lock()
+	if err != nil {
+		return err
+	}
+	err = ctx.unlockFolder(folder)
+	if err != nil {
+		return err
+	}
+
+	var password string
+	if *generate {
+		opts := utils.DefaultPasswordOptions()
+		opts.Length = *length
+		password, err = utils.GeneratePassword(opts)
+		if err != nil {
+			return newError(ExitError, "%v", err)
+		}
+	} else {
+		password, err = ctx.readSecret("Entry password: ")
+		if err != nil {
+			return err
+		}
+		if password == "" {
+			return newError(ExitError, "password is required")
+		}
+	}
+
+	now := time.Now()
+	data := encryption.Data{
+		Password:  password,
+		Username:  utils.SanitizeInput(*username),
+		Email:     utils.SanitizeInput(*email),
+		URL:       utils.SanitizeInput(*url),
+		OTP:       otpURI,
+		Tags:      utils.ParseTags(*tags),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	filename := utils.GenerateFilename(siteName)
+	if folder != "" {
+		filename = folder + "/" + filename
+	}
+	filename = ctx.passwordFolder.UniqueFilename(filename)
+	err = ctx.encryption.EncryptPasswordAndWriteToFile(filename, data)
+	if err != nil {
+		return newError(ExitIOError, "failed to save password: %v", err)
+	}
+	ctx.commit("Add %s", filename)
+
+	fmt.Fprintln(ctx.stdout, filename)
+	return nil
+}
+
+// runRemove moves an entry and its history to the trash
+func runRemove(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "rm")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return newError(ExitError, "usage: %s", commands["rm"].usage)
+	}
+
+	err = ctx.unlock()
+	if err != nil {
+		return err
+	}
+	err = ctx.lock()
+	if err != nil {
+		return err
+	}
+
+	filename, err := ctx.findEntry(positional[0])
+	if err != nil {
+		return err
+	}
+
+	err = ctx.passwordFolder.TrashFile(filename)
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	ctx.commit("Delete %s", filename)
+	fmt.Fprintf(ctx.stderr, "Moved %s.gpg to the trash\n", filename)
+	return nil
+}
+
+// runGenerate prints a random password. It does not need the master password.
+func runGenerate(ctx *context, args []string) error {
+	opts := utils.DefaultPasswordOptions()
+
+	fs := newFlagSet(ctx, "generate")
+	fs.IntVar(&opts.Length, "length", opts.Length, "password length (8-64)")
+	noUpper := fs.Bool("no-upper", false, "exclude uppercase letters")
+	noLower := fs.Bool("no-lower", false, "exclude lowercase letters")
+	noNumbers := fs.Bool("no-numbers", false, "exclude numbers")
+	noSymbols := fs.Bool("no-symbols", false, "exclude symbols")
+	allowAmbiguous := fs.Bool("allow-ambiguous", false, "allow ambiguous characters like 0, O, l and I")
+	_, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	opts.IncludeUppercase = !*noUpper
+	opts.IncludeLowercase = !*noLower
+	opts.IncludeNumbers = !*noNumbers
+	opts.IncludeSymbols = !*noSymbols
+	opts.ExcludeAmbiguous = !*allowAmbiguous
+
+	password, err := utils.GeneratePassword(opts)
+	if err != nil {
+		return newError(ExitError, "%v", err)
+	}
+	fmt.Fprintln(ctx.stdout, password)
+	return nil
+}
+
+// runExport writes every entry in plaintext to a file or stdout
+func runExport(ctx *context, args []string) error {
+	fs := newFlagSet(ctx, "export")
+	format := fs.String("format", string(export.FormatCSV), "output format: csv or json")
+	output := fs.String("output", "-", "destination file, or - for stdout")
+	_, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *format != string(export.FormatCSV) && *format != string(export.FormatJSON) {
+		return newError(ExitError, "unsupported format %q", *format)
+	}
+
+	err = ctx.unlock()
+	if err != nil {
+		return err
+	}
+
+	// Protected folders are unlocked one passphrase at a time so none are left out
+	locked, err := export.LockedFolders(ctx.passwordFolder, ctx.encryption)
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	for _, folder := range locked {
+		err = ctx.unlockFolder(folder)
+		if err != nil {
+			return err
+		}
+	}
+
+	records, err := export.CollectRecords(ctx.passwordFolder, ctx.encryption)
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+
+	if *output == "-" {
+		encoded, err := export.Encode(export.Format(*format), records)
+		if err != nil {
+			return newError(ExitError, "%v", err)
+		}
+		_, err = ctx.stdout.Write(encoded)
+		if err != nil {
+			return newError(ExitIOError, "failed to write export: %v", err)
+		}
+		return nil
+	}
+
+	err = export.WriteRecords(*output, export.Format(*format), records)
+	if err != nil {
+		return newError(ExitIOError, "%v", err)
+	}
+	fmt.Fprintf(ctx.stderr, "Exported %d entries to %s\n", len(records), export.ExpandPath(*output))
+	return nil
+}
+
+// findEntry resolves a name to an entry filename. The name may be the exact filename
+// (with or without .gpg) or a site name, optionally prefixed with its folder as in
+// work/aws/prod; a site name matching several entries is an error.
+func (ctx *context) findEntry(name string) (string, error) {
+	filenames, err := ctx.passwordFolder.ListEntryFilenames()
+	if err != nil {
+		return "", newError(ExitIOError, "failed to read password store: %v", err)
+	}
+
+	name = strings.TrimSuffix(name, ".gpg")
+	folder := utils.CleanFolderPath(encryption.ParentFolder(name))
+	site := utils.CleanSiteName(name[strings.LastIndex(name, "/")+1:])
+	var matches []string
+	for _, filename := range filenames {
+		if filename == name {
+			return filename, nil
+		}
+		if strings.Contains(name, "/") && encryption.ParentFolder(filename) != folder {
+			continue
+		}
+		if utils.StripFilenameTimestamp(filename[strings.LastIndex(filename, "/")+1:]) == site {
+			matches = append(matches, filename)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", newError(ExitNotFound, "no entry named %q", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", newError(ExitError, "%q matches several entries, use the full name: %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// newListedEntry converts a decrypted entry into its printable form
+func newListedEntry(filename string, data encryption.Data) listedEntry {
+	entry := listedEntry{
+		Name:      filename,
+		Type:      string(templates.For(data.Type).Type),
+		Folder:    encryption.ParentFolder(filename),
+		SiteName:  utils.ParseFilenameToSiteName(filename),
+		Username:  data.Username,
+		Email:     data.Email,
+		URL:       data.URL,
+		Password:  data.Password,
+		OTP:       data.OTP,
+		Fields:    data.Fields,
+		Tags:      data.Tags,
+		Favourite: data.Favourite,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+	for _, attachment := range data.Attachments {
+		entry.Attachments = append(entry.Attachments, attachment.Name)
+	}
+	return entry
+}
+
+// writeJSON prints the value as indented JSON
+func writeJSON(ctx *context, value any) error {
+	encoder := json.NewEncoder(ctx.stdout)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(value)
+	if err != nil {
+		return newError(ExitIOError, "failed to write output: %v", err)
+	}
+	return nil
+}
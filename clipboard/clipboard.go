@@ -0,0 +1,214 @@
+// Package clipboard copies secrets to the system clipboard and clears them again after a timeout.
+// The native clipboard is used when available; over SSH, or when no clipboard tool is installed,
+// the OSC52 terminal escape sequence is used so the secret reaches the user's local clipboard.
+package clipboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	sysclip "github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// DefaultTimeout is how long copied secrets stay on the clipboard unless configured otherwise
+const DefaultTimeout = 30 * time.Second
+
+// Backend reads and writes a clipboard. Tests can supply an in-memory implementation.
+type Backend interface {
+	// ReadAll returns the current clipboard contents
+	ReadAll() (string, error)
+
+	// WriteAll replaces the clipboard contents
+	WriteAll(text string) error
+}
+
+// SystemBackend uses the native clipboard (pbcopy, xclip, xsel, wl-copy or the Windows API)
+type SystemBackend struct{}
+
+// ReadAll implements the Backend interface
+func (SystemBackend) ReadAll() (string, error) {
+	return sysclip.ReadAll()
+}
+
+// WriteAll implements the Backend interface
+func (SystemBackend) WriteAll(text string) error {
+	return sysclip.WriteAll(text)
+}
+
+// OSC52Backend writes to the terminal's clipboard using the OSC52 escape sequence.
+// Terminals do not reliably allow reading the clipboard back, so ReadAll returns the last
+// value written by this backend.
+type OSC52Backend struct {
+	Out  io.Writer
+	last string
+}
+
+// ReadAll implements the Backend interface
+func (b *OSC52Backend) ReadAll() (string, error) {
+	return b.last, nil
+}
+
+// WriteAll implements the Backend interface
+func (b *OSC52Backend) WriteAll(text string) error {
+	sequence := osc52.New(text)
+	if text == "" {
+		sequence = osc52.Clear()
+	}
+
+	// Wrap the sequence so it passes through terminal multiplexers
+	if os.Getenv("TMUX") != "" {
+		sequence = sequence.Tmux()
+	} else if os.Getenv("STY") != "" {
+		sequence = sequence.Screen()
+	}
+
+	_, err := sequence.WriteTo(b.Out)
+	if err != nil {
+		return err
+	}
+	b.last = text
+	return nil
+}
+
+// writesToTerminal implements terminalWriter
+func (b *OSC52Backend) writesToTerminal() {}
+
+// terminalWriter is implemented by backends that write to the terminal, such as OSC52Backend.
+// Their scheduled clears wait while a program owns the terminal, see Manager.Pause.
+type terminalWriter interface {
+	writesToTerminal()
+}
+
+// DetectBackend picks OSC52 for SSH sessions or when no native clipboard is available,
+// and the native clipboard otherwise
+func DetectBackend() Backend {
+	if os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != "" || sysclip.Unsupported {
+		return &OSC52Backend{Out: os.Stderr}
+	}
+	return SystemBackend{}
+}
+
+// Manager copies values to a clipboard backend and schedules them to be cleared
+type Manager struct {
+	backend Backend
+	timeout time.Duration
+
+	mu      sync.Mutex
+	pending string      // The value we copied and still intend to clear
+	timer   *time.Timer // Fires the scheduled clear
+	paused  bool        // Whether a program owns the terminal, see Pause
+	due     bool        // Whether a clear fell due while paused
+}
+
+// NewManager creates a manager for the given backend. A timeout of zero or less disables clearing.
+func NewManager(backend Backend, timeout time.Duration) *Manager {
+	return &Manager{
+		backend: backend,
+		timeout: timeout,
+	}
+}
+
+// Copy writes the value to the clipboard and schedules it to be cleared after the timeout.
+// Copying again replaces any previously scheduled clear.
+func (m *Manager) Copy(value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := m.backend.WriteAll(value)
+	if err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %v", err)
+	}
+
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.pending = value
+	m.due = false
+	if m.timeout > 0 {
+		m.timer = time.AfterFunc(m.timeout, m.expire)
+	}
+	return nil
+}
+
+// expire clears the clipboard once the timeout has passed. A backend writing to the terminal
+// would corrupt the screen of a running program, so its clear waits for Resume instead.
+func (m *Manager) expire() {
+	m.mu.Lock()
+	_, terminal := m.backend.(terminalWriter)
+	if m.paused && terminal {
+		m.due = true
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+	m.Clear()
+}
+
+// Pause is called while a program owns the terminal. Clears that fall due in the meantime
+// are held back until Resume if the backend writes to the terminal.
+func (m *Manager) Pause() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = true
+}
+
+// Resume is called once the terminal is free again and performs any clear held back by Pause
+func (m *Manager) Resume() error {
+	m.mu.Lock()
+	m.paused = false
+	due := m.due
+	m.due = false
+	m.mu.Unlock()
+
+	if due {
+		return m.Clear()
+	}
+	return nil
+}
+
+// Clear empties the clipboard, but only if it still holds the value we copied.
+// Anything the user copied afterwards is left alone.
+func (m *Manager) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	m.due = false
+	if m.pending == "" {
+		return nil
+	}
+
+	pending := m.pending
+	m.pending = ""
+
+	current, err := m.backend.ReadAll()
+	if err != nil || current != pending {
+		return err
+	}
+	return m.backend.WriteAll("")
+}
+
+// Timeout returns how long copied values stay on the clipboard
+func (m *Manager) Timeout() time.Duration {
+	return m.timeout
+}
+
+// defaultManager is shared by the views so a clear scheduled in one view survives leaving it
+var defaultManager = NewManager(DetectBackend(), DefaultTimeout)
+
+// Default returns the shared clipboard manager
+func Default() *Manager {
+	return defaultManager
+}
+
+// SetDefault replaces the shared clipboard manager, e.g. to change the timeout or backend
+func SetDefault(manager *Manager) {
+	defaultManager = manager
+}
@@ -0,0 +1,202 @@
+package clipboard
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBackend is an in-memory clipboard that records every write
+type fakeBackend struct {
+	mu      sync.Mutex
+	text    string
+	writes  []string
+	written chan string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{written: make(chan string, 10)}
+}
+
+func (b *fakeBackend) ReadAll() (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.text, nil
+}
+
+func (b *fakeBackend) WriteAll(text string) error {
+	b.mu.Lock()
+	b.text = text
+	b.writes = append(b.writes, text)
+	b.mu.Unlock()
+	b.written <- text
+	return nil
+}
+
+// set changes the clipboard the way another application would, without going through a manager
+func (b *fakeBackend) set(text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.text = text
+}
+
+// waitForWrite returns the next value written, failing the test if none arrives in time
+func (b *fakeBackend) waitForWrite(t *testing.T) string {
+	t.Helper()
+	select {
+	case text := <-b.written:
+		return text
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the clipboard to be written")
+		return ""
+	}
+}
+
+// expectNoWrite fails the test if a value is written within wait
+func (b *fakeBackend) expectNoWrite(t *testing.T, wait time.Duration) {
+	t.Helper()
+	select {
+	case text := <-b.written:
+		t.Fatalf("clipboard was unexpectedly set to %q", text)
+	case <-time.After(wait):
+	}
+}
+
+// fakeTerminalBackend is a fake clipboard that writes to the terminal, like OSC52Backend
+type fakeTerminalBackend struct {
+	*fakeBackend
+}
+
+func (fakeTerminalBackend) writesToTerminal() {}
+
+func TestCopyClearsAfterTimeout(t *testing.T) {
+	backend := newFakeBackend()
+	manager := NewManager(backend, 20*time.Millisecond)
+
+	err := manager.Copy("hunter2")
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if text := backend.waitForWrite(t); text != "hunter2" {
+		t.Fatalf("copied %q, want hunter2", text)
+	}
+	if text := backend.waitForWrite(t); text != "" {
+		t.Fatalf("clipboard set to %q, want it cleared", text)
+	}
+}
+
+func TestClearKeepsValueCopiedElsewhere(t *testing.T) {
+	backend := newFakeBackend()
+	manager := NewManager(backend, 20*time.Millisecond)
+
+	err := manager.Copy("hunter2")
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	backend.waitForWrite(t)
+
+	// The user copies something else before the timeout
+	backend.set("a shopping list")
+	backend.expectNoWrite(t, 100*time.Millisecond)
+
+	if text, _ := backend.ReadAll(); text != "a shopping list" {
+		t.Errorf("clipboard holds %q, want the value copied in the meantime", text)
+	}
+}
+
+func TestCopyAgainReplacesScheduledClear(t *testing.T) {
+	backend := newFakeBackend()
+	manager := NewManager(backend, 50*time.Millisecond)
+
+	manager.Copy("first")
+	backend.waitForWrite(t)
+	time.Sleep(30 * time.Millisecond)
+	manager.Copy("second")
+	backend.waitForWrite(t)
+
+	// The first copy's clear would have fired by now
+	backend.expectNoWrite(t, 30*time.Millisecond)
+	if text := backend.waitForWrite(t); text != "" {
+		t.Fatalf("clipboard set to %q, want it cleared", text)
+	}
+}
+
+func TestZeroTimeoutNeverClears(t *testing.T) {
+	backend := newFakeBackend()
+	manager := NewManager(backend, 0)
+
+	manager.Copy("hunter2")
+	backend.waitForWrite(t)
+	backend.expectNoWrite(t, 50*time.Millisecond)
+}
+
+func TestClearNow(t *testing.T) {
+	backend := newFakeBackend()
+	manager := NewManager(backend, time.Hour)
+
+	manager.Copy("hunter2")
+	backend.waitForWrite(t)
+	err := manager.Clear()
+	if err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if text := backend.waitForWrite(t); text != "" {
+		t.Fatalf("clipboard set to %q, want it cleared", text)
+	}
+
+	// Nothing is left to clear the second time
+	manager.Clear()
+	backend.expectNoWrite(t, 10*time.Millisecond)
+}
+
+func TestPauseHoldsBackTerminalClears(t *testing.T) {
+	backend := fakeTerminalBackend{newFakeBackend()}
+	manager := NewManager(backend, 20*time.Millisecond)
+
+	manager.Pause()
+	manager.Copy("hunter2")
+	backend.waitForWrite(t)
+	backend.expectNoWrite(t, 60*time.Millisecond)
+
+	err := manager.Resume()
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if text := backend.waitForWrite(t); text != "" {
+		t.Fatalf("clipboard set to %q, want it cleared on Resume", text)
+	}
+}
+
+func TestPauseDoesNotHoldBackNativeClears(t *testing.T) {
+	backend := newFakeBackend()
+	manager := NewManager(backend, 20*time.Millisecond)
+
+	manager.Pause()
+	defer manager.Resume()
+	manager.Copy("hunter2")
+	backend.waitForWrite(t)
+	if text := backend.waitForWrite(t); text != "" {
+		t.Fatalf("clipboard set to %q, want it cleared while paused", text)
+	}
+}
+
+func TestOSC52Backend(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("STY", "")
+	var out bytes.Buffer
+	backend := &OSC52Backend{Out: &out}
+
+	err := backend.WriteAll("hunter2")
+	if err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+	// The sequence carries the value base64 encoded
+	if !strings.HasPrefix(out.String(), "\x1b]52;c;aHVudGVyMg==") {
+		t.Errorf("wrote %q, want an OSC52 sequence", out.String())
+	}
+	if text, _ := backend.ReadAll(); text != "hunter2" {
+		t.Errorf("ReadAll = %q, want the last value written", text)
+	}
+}
@@ -0,0 +1,211 @@
+// Package config loads the user's configuration file and resolves which password store to open.
+// A store can be chosen with a command line flag, an environment variable, or a named vault
+// listed in the configuration file, falling back to ~/.password-manager-store.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// Environment variables that override the configuration file
+const (
+	ConfigEnv  = "PASSWORD_MANAGER_CONFIG"  // Path of the configuration file
+	StoreEnv   = "PASSWORD_MANAGER_STORE"   // Path of the store to open
+	VaultEnv   = "PASSWORD_MANAGER_VAULT"   // Name of the vault to open
+	KeyringEnv = "PASSWORD_MANAGER_KEYRING" // Path of the private key used for recipient vaults
+)
+
+// DefaultVault is the name of the built-in vault at ~/.password-manager-store.
+// It is always available unless the configuration file points it somewhere else.
+const DefaultVault = "default"
+
+// Config is the contents of the configuration file
+type Config struct {
+	DefaultVault     string            `json:"default_vault,omitempty"`      // Vault opened when none is requested
+	Vaults           map[string]string `json:"vaults,omitempty"`             // Vault name to store location
+	LockTimeout      *int              `json:"lock_timeout,omitempty"`       // Seconds of inactivity before the vault locks, 0 disables
+	MaxLoginAttempts int               `json:"max_login_attempts,omitempty"` // Failed logins before the store locks out, 0 for no limit
+	Keyring          string            `json:"keyring,omitempty"`            // Armored private key file used to open recipient vaults
+	TrashRetention   *int              `json:"trash_retention_days,omitempty"` // Days deleted entries stay in the trash, 0 keeps them
+	ListSort         string            `json:"list_sort,omitempty"`          // Order of the password list last chosen, e.g. "name" or "recent"
+
+	path string // Where the configuration was loaded from and will be saved to
+}
+
+// Store is a resolved password store location
+type Store struct {
+	Name     string // Vault name, empty when the store was given as a path
+	Location string // Absolute path of the store directory
+}
+
+// DefaultPath returns the configuration file location: $PASSWORD_MANAGER_CONFIG if set,
+// otherwise password-manager/config.json in the user's configuration directory
+func DefaultPath() string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return ExpandPath(path)
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, "password-manager", "config.json")
+}
+
+// Load reads the configuration file at path. A missing file is not an error and
+// yields an empty configuration that will be created on the first Save.
+func Load(path string) (*Config, error) {
+	cfg := &Config{
+		Vaults: make(map[string]string),
+		path:   path,
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	if cfg.Vaults == nil {
+		cfg.Vaults = make(map[string]string)
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration back to the file it was loaded from
+func (c *Config) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %v", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	err = fileio.WriteFileAtomic(c.path, append(data, '\n'), fileio.FilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write config file %s: %v", c.path, err)
+	}
+	return nil
+}
+
+// Path returns the file the configuration is saved to
+func (c *Config) Path() string {
+	return c.path
+}
+
+// VaultNames returns every vault name in sorted order, including the built-in default vault
+func (c *Config) VaultNames() []string {
+	names := []string{DefaultVault}
+	for name := range c.Vaults {
+		if name != DefaultVault {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// VaultLocation returns the store location of the named vault
+func (c *Config) VaultLocation(name string) (string, error) {
+	if location, ok := c.Vaults[name]; ok {
+		return ExpandPath(location), nil
+	}
+	if name == DefaultVault {
+		return fileio.DefaultLocation(), nil
+	}
+	return "", fmt.Errorf("unknown vault '%s' (known vaults: %s)", name, strings.Join(c.VaultNames(), ", "))
+}
+
+// AddVault registers a named vault. The name must not already be in use.
+func (c *Config) AddVault(name, location string) error {
+	name = strings.TrimSpace(name)
+	location = strings.TrimSpace(location)
+	if name == "" || location == "" {
+		return fmt.Errorf("vault name and location are required")
+	}
+	if _, err := c.VaultLocation(name); err == nil {
+		return fmt.Errorf("a vault named '%s' already exists", name)
+	}
+	c.Vaults[name] = location
+	return nil
+}
+
+// KeyringPath returns the private key file used to open recipient vaults: $PASSWORD_MANAGER_KEYRING
+// if set, then the configured keyring, otherwise keyring.asc next to the configuration file
+func (c *Config) KeyringPath() string {
+	if path := os.Getenv(KeyringEnv); path != "" {
+		return ExpandPath(path)
+	}
+	if c.Keyring != "" {
+		return ExpandPath(c.Keyring)
+	}
+	return filepath.Join(filepath.Dir(c.path), "keyring.asc")
+}
+
+// TrashRetentionPeriod returns how long deleted entries stay in the trash before they are
+// purged, fileio.DefaultTrashRetention unless configured. 0 keeps them until purged by hand.
+func (c *Config) TrashRetentionPeriod() time.Duration {
+	if c.TrashRetention == nil || *c.TrashRetention < 0 {
+		return fileio.DefaultTrashRetention
+	}
+	return time.Duration(*c.TrashRetention) * 24 * time.Hour
+}
+
+// Resolve decides which store to open. The first of these that is set wins:
+// the store path flag, the vault name flag, $PASSWORD_MANAGER_STORE, $PASSWORD_MANAGER_VAULT,
+// the configured default vault, and finally the built-in default vault.
+func (c *Config) Resolve(storeFlag, vaultFlag string) (Store, error) {
+	if storeFlag != "" {
+		return Store{Location: ExpandPath(storeFlag)}, nil
+	}
+	if vaultFlag != "" {
+		return c.resolveVault(vaultFlag)
+	}
+	if store := os.Getenv(StoreEnv); store != "" {
+		return Store{Location: ExpandPath(store)}, nil
+	}
+	if vault := os.Getenv(VaultEnv); vault != "" {
+		return c.resolveVault(vault)
+	}
+	if c.DefaultVault != "" {
+		return c.resolveVault(c.DefaultVault)
+	}
+	return c.resolveVault(DefaultVault)
+}
+
+// resolveVault looks up a vault by name
+func (c *Config) resolveVault(name string) (Store, error) {
+	location, err := c.VaultLocation(name)
+	if err != nil {
+		return Store{}, err
+	}
+	return Store{Name: name, Location: location}, nil
+}
+
+// ExpandPath replaces a leading ~ with the user's home directory and makes the path absolute
+func ExpandPath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(path, "~"))
+	}
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return absolute
+}
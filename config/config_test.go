@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &Config{
+		DefaultVault: "work",
+		Vaults: map[string]string{
+			"work":     "/stores/work",
+			"personal": "~/personal-store",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		storeFlag string
+		vaultFlag string
+		storeEnv  string
+		vaultEnv  string
+		cfg       *Config
+		want      Store
+	}{
+		{
+			name:      "store flag wins over everything",
+			storeFlag: "/flag/store", vaultFlag: "personal", storeEnv: "/env/store", vaultEnv: "personal",
+			cfg:  cfg,
+			want: Store{Location: "/flag/store"},
+		},
+		{
+			name:      "vault flag wins over the environment",
+			vaultFlag: "personal", storeEnv: "/env/store", vaultEnv: "work",
+			cfg:  cfg,
+			want: Store{Name: "personal", Location: filepath.Join(home, "personal-store")},
+		},
+		{
+			name:     "store environment wins over the vault environment",
+			storeEnv: "/env/store", vaultEnv: "personal",
+			cfg:  cfg,
+			want: Store{Location: "/env/store"},
+		},
+		{
+			name:     "vault environment wins over the config file",
+			vaultEnv: "personal",
+			cfg:      cfg,
+			want:     Store{Name: "personal", Location: filepath.Join(home, "personal-store")},
+		},
+		{
+			name: "config file default vault",
+			cfg:  cfg,
+			want: Store{Name: "work", Location: "/stores/work"},
+		},
+		{
+			name: "built-in default",
+			cfg:  &Config{Vaults: map[string]string{}},
+			want: Store{Name: DefaultVault, Location: filepath.Join(home, ".password-manager-store")},
+		},
+		{
+			name: "default vault moved by the config file",
+			cfg:  &Config{Vaults: map[string]string{DefaultVault: "/elsewhere"}},
+			want: Store{Name: DefaultVault, Location: "/elsewhere"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(StoreEnv, test.storeEnv)
+			t.Setenv(VaultEnv, test.vaultEnv)
+			got, err := test.cfg.Resolve(test.storeFlag, test.vaultFlag)
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Resolve = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveUnknownVault(t *testing.T) {
+	t.Setenv(StoreEnv, "")
+	t.Setenv(VaultEnv, "")
+	cfg := &Config{Vaults: map[string]string{"work": "/stores/work"}}
+
+	if _, err := cfg.Resolve("", "typo"); err == nil {
+		t.Error("Resolve accepted an unknown vault flag")
+	}
+	t.Setenv(VaultEnv, "typo")
+	if _, err := cfg.Resolve("", ""); err == nil {
+		t.Error("Resolve accepted an unknown vault in the environment")
+	}
+}
+
+func TestAddVault(t *testing.T) {
+	cfg := &Config{Vaults: map[string]string{"work": "/stores/work"}}
+
+	err := cfg.AddVault(" personal ", " ~/personal ")
+	if err != nil {
+		t.Fatalf("AddVault: %v", err)
+	}
+	if cfg.Vaults["personal"] != "~/personal" {
+		t.Errorf("Vaults = %v, want personal trimmed", cfg.Vaults)
+	}
+	for _, name := range []string{"work", DefaultVault, ""} {
+		if err := cfg.AddVault(name, "/somewhere"); err == nil {
+			t.Errorf("AddVault accepted %q", name)
+		}
+	}
+
+	want := []string{DefaultVault, "personal", "work"}
+	names := cfg.VaultNames()
+	if len(names) != len(want) {
+		t.Fatalf("VaultNames = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("VaultNames = %v, want %v", names, want)
+		}
+	}
+}
+
+func TestLoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password-manager", "config.json")
+
+	// A missing file gives an empty configuration
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DefaultVault != "" || len(cfg.Vaults) != 0 {
+		t.Errorf("Load of a missing file = %+v, want an empty configuration", cfg)
+	}
+
+	cfg.DefaultVault = "work"
+	cfg.Vaults["work"] = "/stores/work"
+	err = cfg.Save()
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.DefaultVault != "work" || loaded.Vaults["work"] != "/stores/work" {
+		t.Errorf("Load = %+v, want the saved configuration", loaded)
+	}
+
+	err = os.WriteFile(path, []byte("{not json"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load accepted a malformed file")
+	}
+}
@@ -0,0 +1,206 @@
+package encryption
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/ProtonMail/go-crypto/openpgp/s2k"
+	"github.com/ProtonMail/gopenpgp/v3/profile"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// CryptoConfigFilename is the store-relative path of the store's encryption settings.
+// The settings are not secret: every encrypted file records the parameters it was made with,
+// so decryption never needs them and only new encryptions are affected.
+const CryptoConfigFilename = ".checker/crypto.json"
+
+// Supported setting values, in the order they are offered in the settings screen
+var (
+	Profiles    = []string{"rfc9580", "rfc4880", "default"}
+	Ciphers     = []string{"aes128", "aes192", "aes256"}
+	AEADModes   = []string{"ocb", "gcm", "eax", "none"}
+	S2KModes    = []string{"argon2", "iterated"}
+	Argon2Sizes = []uint32{64 * 1024, 256 * 1024, 512 * 1024, 1024 * 1024, 2048 * 1024} // KiB
+)
+
+// CryptoConfig selects the OpenPGP profile, cipher, AEAD mode and password key derivation
+// used when entries are encrypted
+type CryptoConfig struct {
+	Profile           string `json:"profile"`            // Base gopenpgp profile
+	Cipher            string `json:"cipher"`             // Symmetric cipher
+	AEAD              string `json:"aead"`               // AEAD mode, or "none" for the older SEIPD v1 packets
+	S2K               string `json:"s2k"`                // Password key derivation function
+	Argon2Passes      uint8  `json:"argon2_passes"`      // Argon2 time cost
+	Argon2Parallelism uint8  `json:"argon2_parallelism"` // Argon2 lanes
+	Argon2MemoryKiB   uint32 `json:"argon2_memory_kib"`  // Argon2 memory cost, rounded down to a power of two
+}
+
+// DefaultCryptoConfig returns the defaults of the named profile. These match what gopenpgp
+// uses for that profile, so a store without a crypto.json behaves exactly as before.
+func DefaultCryptoConfig(profileName string) CryptoConfig {
+	switch profileName {
+	case "rfc4880", "default":
+		return CryptoConfig{
+			Profile:           profileName,
+			Cipher:            "aes256",
+			AEAD:              "none",
+			S2K:               "iterated",
+			Argon2Passes:      3,
+			Argon2Parallelism: 4,
+			Argon2MemoryKiB:   64 * 1024,
+		}
+	default:
+		return CryptoConfig{
+			Profile:           "rfc9580",
+			Cipher:            "aes256",
+			AEAD:              "ocb",
+			S2K:               "argon2",
+			Argon2Passes:      3,
+			Argon2Parallelism: 4,
+			Argon2MemoryKiB:   64 * 1024,
+		}
+	}
+}
+
+// Validate checks that every setting is supported and that the combination is allowed
+func (c CryptoConfig) Validate() error {
+	if !slices.Contains(Profiles, c.Profile) {
+		return fmt.Errorf("unknown profile '%s'", c.Profile)
+	}
+	if !slices.Contains(Ciphers, c.Cipher) {
+		return fmt.Errorf("unknown cipher '%s'", c.Cipher)
+	}
+	if !slices.Contains(AEADModes, c.AEAD) {
+		return fmt.Errorf("unknown AEAD mode '%s'", c.AEAD)
+	}
+	if !slices.Contains(S2KModes, c.S2K) {
+		return fmt.Errorf("unknown key derivation '%s'", c.S2K)
+	}
+	if c.S2K == "argon2" {
+		// RFC 9580 only allows Argon2 with AEAD protected data
+		if c.AEAD == "none" {
+			return fmt.Errorf("Argon2 key derivation requires an AEAD mode")
+		}
+		if c.Argon2Passes < 1 || c.Argon2Parallelism < 1 {
+			return fmt.Errorf("Argon2 passes and parallelism must be at least 1")
+		}
+		if c.Argon2MemoryKiB < 8*uint32(c.Argon2Parallelism) {
+			return fmt.Errorf("Argon2 memory must be at least 8 KiB per lane")
+		}
+	}
+	return nil
+}
+
+// Describe returns a short human readable summary of the settings
+func (c CryptoConfig) Describe() string {
+	summary := fmt.Sprintf("%s, %s", c.Profile, c.Cipher)
+	if c.AEAD != "none" {
+		summary += "-" + c.AEAD
+	}
+	if c.S2K == "argon2" {
+		return summary + fmt.Sprintf(", Argon2 (t=%d, p=%d, m=%s)", c.Argon2Passes, c.Argon2Parallelism, FormatKiB(c.Argon2MemoryKiB))
+	}
+	return summary + ", iterated S2K"
+}
+
+// FormatKiB formats a size in kibibytes as MiB or GiB
+func FormatKiB(kib uint32) string {
+	if kib >= 1024*1024 && kib%(1024*1024) == 0 {
+		return fmt.Sprintf("%d GiB", kib/(1024*1024))
+	}
+	return fmt.Sprintf("%d MiB", kib/1024)
+}
+
+// profile builds the gopenpgp profile described by the settings
+func (c CryptoConfig) profile() *profile.Custom {
+	var custom *profile.Custom
+	switch c.Profile {
+	case "rfc4880":
+		custom = profile.RFC4880()
+	case "default":
+		custom = profile.Default()
+	default:
+		custom = profile.RFC9580()
+	}
+
+	switch c.Cipher {
+	case "aes128":
+		custom.CipherEncryption = packet.CipherAES128
+	case "aes192":
+		custom.CipherEncryption = packet.CipherAES192
+	default:
+		custom.CipherEncryption = packet.CipherAES256
+	}
+
+	switch c.AEAD {
+	case "none":
+		custom.AeadEncryption = nil
+	case "gcm":
+		custom.AeadEncryption = &packet.AEADConfig{DefaultMode: packet.AEADModeGCM}
+	case "eax":
+		custom.AeadEncryption = &packet.AEADConfig{DefaultMode: packet.AEADModeEAX}
+	default:
+		custom.AeadEncryption = &packet.AEADConfig{DefaultMode: packet.AEADModeOCB}
+	}
+
+	if c.S2K == "argon2" {
+		custom.S2kEncryption = &s2k.Config{
+			S2KMode: s2k.Argon2S2K,
+			Argon2Config: &s2k.Argon2Config{
+				NumberOfPasses:      c.Argon2Passes,
+				DegreeOfParallelism: c.Argon2Parallelism,
+				Memory:              c.Argon2MemoryKiB,
+			},
+		}
+	} else {
+		custom.S2kEncryption = &s2k.Config{S2KMode: s2k.IteratedSaltedS2K}
+	}
+	return custom
+}
+
+// LoadCryptoConfig reads the encryption settings of the store at storeLocation.
+// A store without settings uses the RFC 9580 defaults.
+func LoadCryptoConfig(storeLocation string) (CryptoConfig, error) {
+	path := filepath.Join(storeLocation, CryptoConfigFilename)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultCryptoConfig("rfc9580"), nil
+	}
+	if err != nil {
+		return CryptoConfig{}, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	config := CryptoConfig{}
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return CryptoConfig{}, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	err = config.Validate()
+	if err != nil {
+		return CryptoConfig{}, fmt.Errorf("invalid encryption settings in %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// SaveCryptoConfig validates the settings and writes them to the store at storeLocation
+func SaveCryptoConfig(storeLocation string, config CryptoConfig) error {
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode encryption settings: %v", err)
+	}
+	path := filepath.Join(storeLocation, CryptoConfigFilename)
+	err = fileio.WriteFileAtomic(path, data, fileio.FilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package encryption
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// keyPacketVersion returns the version of the password packet an armored message starts with:
+// 4 for the older RFC 4880 format and 6 for RFC 9580 AEAD messages
+func keyPacketVersion(t *testing.T, armored []byte) int {
+	t.Helper()
+	block, err := armor.Decode(bytes.NewReader(armored))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ske, ok := p.(*packet.SymmetricKeyEncrypted)
+	if !ok {
+		t.Fatalf("message starts with %T, want a password packet", p)
+	}
+	return ske.Version
+}
+
+func TestSetCryptoConfigReencrypt(t *testing.T) {
+	pf, ef := newTestStore(t)
+	err := ef.EncryptPasswordAndWriteToFile("github", Data{Password: "hunter2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The smallest Argon2 settings keep the test quick
+	config := CryptoConfig{
+		Profile:           "rfc9580",
+		Cipher:            "aes128",
+		AEAD:              "gcm",
+		S2K:               "argon2",
+		Argon2Passes:      1,
+		Argon2Parallelism: 1,
+		Argon2MemoryKiB:   8,
+	}
+	err = ef.SetCryptoConfig(config)
+	if err != nil {
+		t.Fatalf("SetCryptoConfig: %v", err)
+	}
+	if loaded, err := LoadCryptoConfig(pf.FolderLocation); err != nil || loaded != config {
+		t.Fatalf("LoadCryptoConfig = %+v, %v, want %+v", loaded, err, config)
+	}
+
+	err = ef.ReencryptFileWithPassword(InitFilename, []byte(testMasterPassword))
+	if err != nil {
+		t.Fatalf("ReencryptFileWithPassword: %v", err)
+	}
+	err = ef.ReencryptFile("github")
+	if err != nil {
+		t.Fatalf("ReencryptFile: %v", err)
+	}
+	for _, name := range []string{InitFilename, "github"} {
+		armored, err := pf.ReadFromFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version := keyPacketVersion(t, armored); version != 6 {
+			t.Errorf("%s was encrypted with a version %d packet, want the new settings", name, version)
+		}
+	}
+
+	// A fresh session still opens the key file and the entry
+	reopened := NewEncryption(pf)
+	err = reopened.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock after re-encrypting: %v", err)
+	}
+	data, err := reopened.DecryptPasswordFromFile("github")
+	if err != nil || data.Password != "hunter2" {
+		t.Errorf("github = %+v, %v, want hunter2", data, err)
+	}
+}
+
+func TestCryptoConfigValidate(t *testing.T) {
+	valid := DefaultCryptoConfig("rfc9580")
+	tests := []struct {
+		name   string
+		change func(c *CryptoConfig)
+	}{
+		{"unknown profile", func(c *CryptoConfig) { c.Profile = "rfc1991" }},
+		{"unknown cipher", func(c *CryptoConfig) { c.Cipher = "des" }},
+		{"unknown AEAD mode", func(c *CryptoConfig) { c.AEAD = "ccm" }},
+		{"unknown key derivation", func(c *CryptoConfig) { c.S2K = "simple" }},
+		{"Argon2 without AEAD", func(c *CryptoConfig) { c.AEAD = "none" }},
+		{"no Argon2 passes", func(c *CryptoConfig) { c.Argon2Passes = 0 }},
+		{"no Argon2 lanes", func(c *CryptoConfig) { c.Argon2Parallelism = 0 }},
+		{"too little Argon2 memory", func(c *CryptoConfig) { c.Argon2Parallelism = 4; c.Argon2MemoryKiB = 16 }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := valid
+			test.change(&config)
+			if config.Validate() == nil {
+				t.Errorf("Validate accepted %+v", config)
+			}
+			location := newTestFolder(t).FolderLocation
+			if SaveCryptoConfig(location, config) == nil {
+				t.Error("SaveCryptoConfig saved invalid settings")
+			}
+			if loaded, err := LoadCryptoConfig(location); err != nil || loaded != fastConfig {
+				t.Errorf("the saved settings changed to %+v, %v", loaded, err)
+			}
+		})
+	}
+
+	for _, name := range Profiles {
+		if err := DefaultCryptoConfig(name).Validate(); err != nil {
+			t.Errorf("the %s defaults are invalid: %v", name, err)
+		}
+	}
+	// The iterated key derivation works without AEAD and ignores the Argon2 settings
+	iterated := DefaultCryptoConfig("rfc4880")
+	iterated.Argon2Passes = 0
+	if err := iterated.Validate(); err != nil {
+		t.Errorf("Validate rejected the iterated key derivation: %v", err)
+	}
+}
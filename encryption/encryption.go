@@ -4,6 +4,8 @@ package encryption
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ProtonMail/gopenpgp/v3/crypto"
@@ -15,19 +17,41 @@ import (
 // Data represents a password entry with associated metadata.
 // All fields are JSON-serialized before encryption for secure storage.
 type Data struct {
-	Password  string    `json:"password"`  // The actual password or secret data
-	Username  string    `json:"username"`  // Associated username (optional)
-	Email     string    `json:"email"`     // Associated email address (optional)
-	URL       string    `json:"url"`       // Associated website URL (optional)
-	CreatedAt time.Time `json:"created_at"` // Timestamp when entry was created
-	UpdatedAt time.Time `json:"updated_at"` // Timestamp when entry was last modified
+	Type        EntryType    `json:"type,omitempty"`        // Template the entry was created from, empty for older logins
+	Password    string       `json:"password"`              // The actual password or secret data
+	Username    string       `json:"username"`              // Associated username (optional)
+	Email       string       `json:"email"`                 // Associated email address (optional)
+	URL         string       `json:"url"`                   // Associated website URL (optional)
+	OTP         string       `json:"otp,omitempty"`         // otpauth:// URI of the 2FA key (optional)
+	Fields      []Field      `json:"fields,omitempty"`      // Custom fields such as recovery codes (optional)
+	Attachments []Attachment `json:"attachments,omitempty"` // Small files stored with the entry (optional)
+	Tags        []string     `json:"tags,omitempty"`        // Lower-case labels for grouping entries (optional)
+	Favourite   bool         `json:"favourite,omitempty"`   // Whether the entry is pinned to the top of the list
+	CreatedAt   time.Time    `json:"created_at"`            // Timestamp when entry was created
+	UpdatedAt   time.Time    `json:"updated_at"`            // Timestamp when entry was last modified
 }
 
+// InitFilename is the store-relative name (without .gpg) of the key file that holds the wrapped data key
+const InitFilename = ".checker/init"
+
+// ErrWrongPassword is returned by Unlock when the master password cannot decrypt the key file
+var ErrWrongPassword = errors.New("incorrect master password")
+
+// ErrStoreLocked is returned when writing an entry after the key has been wiped
+var ErrStoreLocked = errors.New("the vault is locked")
+
 // EncryptionFunctions provides methods for encrypting and decrypting password data
 // using the master password from the password folder.
 type EncryptionFunctions struct {
 	passwordFolder  *fileio.PasswordFolder // Reference to the password store
 	EnteredPassword string                  // Currently unused, may be removed
+	KeyringPath     string                  // Armored private key file used to open recipient vaults
+	cryptoConfig    *CryptoConfig           // Store encryption settings, loaded on first use
+	keyVersion      int                     // Format of the unlocked key file
+	legacyKey       string                  // Version 1 phrase while entries are being migrated
+	identity        *crypto.Key             // Unlocked private key in recipient mode
+	folderKeys      map[string]string       // Unlocked keys of passphrase protected folders
+	spareKeys       []string                // Folder keys replaced since unlocking, for entries not yet re-encrypted
 }
 
 // NewEncryption creates a new EncryptionFunctions instance with the given password folder.
@@ -40,7 +64,8 @@ func NewEncryption(passwordFolder *fileio.PasswordFolder) *EncryptionFunctions {
 
 // EncryptPasswordAndWriteToFile encrypts the given Data struct and writes it to a file.
 // The data is first JSON-serialized, then encrypted using the master password with GPG,
-// and finally written as an armored .gpg file in the password store.
+// and finally written as an armored .gpg file in the password store. An entry that already
+// exists is kept as a revision first, see fileio.SaveRevision.
 //
 // Parameters:
 //   - fileName: Name of the file (without .gpg extension, added automatically)
@@ -48,61 +73,327 @@ func NewEncryption(passwordFolder *fileio.PasswordFolder) *EncryptionFunctions {
 //
 // Returns an error if JSON marshaling, encryption, or file writing fails.
 func (ef *EncryptionFunctions) EncryptPasswordAndWriteToFile(fileName string, data Data) error {
+	armored, err := ef.EncryptEntry(fileName, data)
+	if err != nil {
+		return err
+	}
+
+	// Keep the version being replaced so it can be restored from the entry's history
+	err = ef.passwordFolder.SaveRevision(fileName)
+	if err != nil {
+		return err
+	}
+
+	// Write the encrypted data to file (adds .gpg extension automatically)
+	err = ef.passwordFolder.WriteToFile(fileName, armored)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RewriteEntry encrypts data over the entry fileName without keeping the version it replaces
+// in the history. It is for bookkeeping that is not an edit, such as an HOTP counter moving on.
+func (ef *EncryptionFunctions) RewriteEntry(fileName string, data Data) error {
+	armored, err := ef.EncryptEntry(fileName, data)
+	if err != nil {
+		return err
+	}
+	return ef.passwordFolder.WriteToFile(fileName, armored)
+}
+
+// EncryptEntry JSON-serializes the Data struct and encrypts it the way the store encrypts
+// entries stored as fileName: to the recipients of its folder, with its folder key, or with
+// the data key. Returns the ASCII-armored message exactly as it is stored in a .gpg file.
+func (ef *EncryptionFunctions) EncryptEntry(fileName string, data Data) ([]byte, error) {
 	// Convert the Data struct to JSON for storage
 	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return ef.encrypt(fileName, jsonData)
+}
+
+// DecryptEntry decrypts an ASCII-armored entry stored as fileName with whatever keys the
+// store is unlocked with
+func (ef *EncryptionFunctions) DecryptEntry(fileName string, armored []byte) (Data, error) {
+	data := Data{}
+	decrypted, err := ef.decrypt(fileName, armored)
+	if err != nil {
+		return data, err
+	}
+	json.Unmarshal(decrypted, &data)
+	return data, nil
+}
+
+func (ef *EncryptionFunctions) DecryptPasswordFromFile (fileName string) (Data, error) {
+
+	data := Data{}
+	fileData, err := ef.passwordFolder.ReadFromFile(fileName)
+	if err != nil {
+		return data, err 
+	}
+	return ef.DecryptEntry(fileName, fileData)
+}
+
+// decrypt decrypts the contents of the file stored as fileName with the private key, then with
+// the key of its folder and every other unlocked key, including the legacy phrase for entries
+// that have not been migrated yet. Returns ErrFolderLocked if its folder has not been unlocked.
+func (ef *EncryptionFunctions) decrypt(fileName string, fileData []byte) ([]byte, error) {
+	scope, scopeErr := ef.ScopeOf(fileName)
+	err := fmt.Errorf("no key is unlocked")
+	if ef.identity != nil {
+		var decrypted []byte
+		decrypted, err = ef.decryptWithIdentity(fileData)
+		if err == nil {
+			return decrypted, nil
+		}
+	}
+	for _, key := range ef.candidateKeys(scope) {
+		decrypted, keyErr := DecryptBytes(fileData, []byte(key))
+		if keyErr == nil {
+			return decrypted, nil
+		}
+		err = keyErr
+	}
+	if scopeErr == nil && !ef.ScopeUnlocked(scope) {
+		return nil, fmt.Errorf("%w: %s", ErrFolderLocked, scope.Folder)
+	}
+	return nil, err
+}
+
+// Unlock unwraps the data key in .checker/init.gpg with the master password and, on success,
+// replaces the password held by the password folder with the data key used to encrypt entries.
+// Stores still using the version 1 phrase unlock with the phrase; see NeedsMigration.
+// In recipient mode the password is instead the passphrase of the local private key.
+// Returns ErrWrongPassword if the master password is incorrect.
+func (ef *EncryptionFunctions) Unlock(masterPassword string) error {
+	recipients, err := ef.Recipients("")
 	if err != nil {
 		return err
 	}
+	if len(recipients) > 0 {
+		return ef.unlockRecipientMode(masterPassword, recipients)
+	}
 
-	// Use the master password for encryption
-	password := []byte(ef.passwordFolder.Password)
-	pgp := crypto.PGPWithProfile(profile.RFC9580())
+	keyFile, err := ef.readKeyFile(masterPassword)
+	if err != nil {
+		ef.Lock()
+		return err
+	}
+	ef.passwordFolder.Password = keyFile.DataKey
+	ef.keyVersion = keyFile.Version
+	ef.legacyKey = keyFile.LegacyKey
+	return nil
+}
 
-	// Create encryption handler with password-based encryption
-	encHandle, err := pgp.Encryption().Password(password).New()
+// Lock forgets the data key, any legacy phrase, folder keys and the unlocked private key held in memory
+func (ef *EncryptionFunctions) Lock() {
+	ef.passwordFolder.Password = ""
+	ef.legacyKey = ""
+	ef.folderKeys = nil
+	ef.spareKeys = nil
+	if ef.identity != nil {
+		ef.identity.ClearPrivateParams()
+		ef.identity = nil
+	}
+}
+
+// ReencryptFile decrypts an entry and encrypts it again under the store's current encryption
+// settings, to the current recipients of its folder, with its folder key or with the data key. The contents are re-encrypted
+// byte for byte, which also moves entries still under the legacy phrase onto the data key.
+// The entry's revisions are re-encrypted with it; any that can no longer be read are left as they are.
+func (ef *EncryptionFunctions) ReencryptFile(fileName string) error {
+	err := ef.reencryptAs(fileName, fileName)
+	if err != nil {
+		return err
+	}
+	revisions, err := ef.passwordFolder.ListRevisions(fileName)
 	if err != nil {
 		return err
 	}
+	for _, revision := range revisions {
+		err = ef.reencryptAs(revision.Name, fileName)
+		if err != nil && !errors.Is(err, errUndecryptable) {
+			return err
+		}
+	}
+	return nil
+}
 
-	// Encrypt the JSON data
-	pgpMessage, err := encHandle.Encrypt(jsonData)
+// ReencryptTrash re-encrypts the entries in the trash, and their revisions, the way they
+// would be encrypted if restored, so they stay readable after the store's keys change.
+// Files that cannot be decrypted, such as those in locked folders, are left as they are.
+func (ef *EncryptionFunctions) ReencryptTrash() error {
+	trashed, err := ef.passwordFolder.ListTrash()
 	if err != nil {
 		return err
 	}
+	for _, entry := range trashed {
+		names, err := ef.passwordFolder.TrashedFiles(entry)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			err = ef.reencryptAs(name, entry.Filename)
+			if err != nil && !errors.Is(err, errUndecryptable) {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-	// Convert to ASCII-armored format for storage
-	armored, err := pgpMessage.ArmorBytes()
+// errUndecryptable is returned by reencryptAs when the file cannot be decrypted
+var errUndecryptable = errors.New("cannot be decrypted")
+
+// reencryptAs re-encrypts the store file fileName the way entries stored as entryName are encrypted
+func (ef *EncryptionFunctions) reencryptAs(fileName string, entryName string) error {
+	fileData, err := ef.passwordFolder.ReadFromFile(fileName)
 	if err != nil {
 		return err
 	}
+	plaintext, err := ef.decrypt(entryName, fileData)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt '%s.gpg': %w: %v", fileName, errUndecryptable, err)
+	}
+	armored, err := ef.encrypt(entryName, plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt '%s.gpg': %v", fileName, err)
+	}
+	return ef.passwordFolder.WriteToFile(fileName, armored)
+}
 
-	// Write the encrypted data to file (adds .gpg extension automatically)
-	err = ef.passwordFolder.WriteToFile(fileName, armored)
+// DecryptRevision decrypts an earlier revision of the entry stored as fileName
+func (ef *EncryptionFunctions) DecryptRevision(fileName string, revision fileio.Revision) (Data, error) {
+	fileData, err := ef.passwordFolder.ReadFromFile(revision.Name)
+	if err != nil {
+		return Data{}, err
+	}
+	return ef.DecryptEntry(fileName, fileData)
+}
+
+// RestoreRevision makes an earlier revision the current version of the entry stored as
+// fileName. The version it replaces is kept in the history, so a restore can be undone.
+func (ef *EncryptionFunctions) RestoreRevision(fileName string, revision fileio.Revision) error {
+	data, err := ef.DecryptRevision(fileName, revision)
+	if err != nil {
+		return err
+	}
+	data.UpdatedAt = time.Now()
+	return ef.EncryptPasswordAndWriteToFile(fileName, data)
+}
+
+// ReencryptFileWithPassword is ReencryptFile for files protected by a different password,
+// such as the key file which is encrypted with the master password
+func (ef *EncryptionFunctions) ReencryptFileWithPassword(fileName string, password []byte) error {
+	config, err := ef.CryptoConfig()
 	if err != nil {
 		return err
 	}
+	fileData, err := ef.passwordFolder.ReadFromFile(fileName)
+	if err != nil {
+		return err
+	}
+	plaintext, err := DecryptBytes(fileData, password)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt '%s.gpg': %v", fileName, err)
+	}
+	armored, err := EncryptBytesWithConfig(plaintext, password, config)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt '%s.gpg': %v", fileName, err)
+	}
+	return ef.passwordFolder.WriteToFile(fileName, armored)
+}
 
+// CryptoConfig returns the store's encryption settings
+func (ef *EncryptionFunctions) CryptoConfig() (CryptoConfig, error) {
+	if ef.cryptoConfig == nil {
+		config, err := LoadCryptoConfig(ef.passwordFolder.FolderLocation)
+		if err != nil {
+			return CryptoConfig{}, err
+		}
+		ef.cryptoConfig = &config
+	}
+	return *ef.cryptoConfig, nil
+}
+
+// SetCryptoConfig saves new encryption settings for the store. Existing files keep their
+// old settings until they are re-encrypted with ReencryptFile.
+func (ef *EncryptionFunctions) SetCryptoConfig(config CryptoConfig) error {
+	err := ef.passwordFolder.CheckWritable()
+	if err != nil {
+		return err
+	}
+	err = SaveCryptoConfig(ef.passwordFolder.FolderLocation, config)
+	if err != nil {
+		return err
+	}
+	ef.cryptoConfig = &config
 	return nil
 }
 
-func (ef *EncryptionFunctions) DecryptPasswordFromFile (fileName string) (Data, error) {
+// EncryptData JSON-serializes the given Data struct and encrypts it with the password.
+// Returns the ASCII-armored message exactly as it is stored in a .gpg file.
+func EncryptData(data Data, password []byte) ([]byte, error) {
+	// Convert the Data struct to JSON for storage
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptBytes(jsonData, password)
+}
 
+// DecryptData decrypts an ASCII-armored entry with the password and parses the JSON payload.
+func DecryptData(armored []byte, password []byte) (Data, error) {
 	data := Data{}
-	fileData, err := ef.passwordFolder.ReadFromFile(fileName)
+	decrypted, err := DecryptBytes(armored, password)
 	if err != nil {
-		return data, err 
+		return data, err
+	}
+	json.Unmarshal(decrypted, &data)
+	return data, nil
+}
+
+// EncryptBytes encrypts arbitrary bytes with password-based encryption using the RFC9580
+// profile and returns the ASCII-armored PGP message.
+func EncryptBytes(plaintext []byte, password []byte) ([]byte, error) {
+	return EncryptBytesWithConfig(plaintext, password, DefaultCryptoConfig("rfc9580"))
+}
+
+// EncryptBytesWithConfig encrypts arbitrary bytes with password-based encryption using the
+// given encryption settings and returns the ASCII-armored PGP message.
+func EncryptBytesWithConfig(plaintext []byte, password []byte, config CryptoConfig) ([]byte, error) {
+	pgp := crypto.PGPWithProfile(config.profile())
+
+	// Create encryption handler with password-based encryption
+	encHandle, err := pgp.Encryption().Password(password).New()
+	if err != nil {
+		return nil, err
 	}
-	password := []byte(ef.passwordFolder.Password)
+
+	// Encrypt the data
+	pgpMessage, err := encHandle.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to ASCII-armored format for storage
+	return pgpMessage.ArmorBytes()
+}
+
+// DecryptBytes decrypts an ASCII-armored PGP message that was encrypted with the password.
+func DecryptBytes(armored []byte, password []byte) ([]byte, error) {
 	pgp := crypto.PGPWithProfile(profile.RFC9580())
 
 	decHandler, err := pgp.Decryption().Password(password).New()
 	if err != nil {
-		return data, err
+		return nil, err
 	}
-	decrypted, err := decHandler.Decrypt(fileData, crypto.Armor)
+	decrypted, err := decHandler.Decrypt(armored, crypto.Armor)
 	if err != nil {
-		return data, err
+		return nil, err
 	}
-	json.Unmarshal(decrypted.Bytes(), &data)
-	return data, nil
+	return decrypted.Bytes(), nil
 }
@@ -0,0 +1,82 @@
+package encryption
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EntryType is the template an entry was created from, which decides its fields and how it is shown
+type EntryType string
+
+const (
+	EntryLogin    EntryType = "login"    // Website or application login, also entries saved before types existed
+	EntryCard     EntryType = "card"     // Credit or debit card
+	EntryNote     EntryType = "note"     // Secure note
+	EntryWiFi     EntryType = "wifi"     // Wi-Fi network
+	EntrySSH      EntryType = "ssh"      // SSH key
+	EntryDatabase EntryType = "database" // Database connection
+	EntryAPI      EntryType = "api"      // API token
+)
+
+// FieldType is the kind of value a custom field holds, which decides how it is entered and shown
+type FieldType string
+
+const (
+	FieldText   FieldType = "text"   // Plain single-line text, shown as is
+	FieldHidden FieldType = "hidden" // A secret such as a recovery code, hidden until revealed
+	FieldNotes  FieldType = "notes"  // Multi-line text such as an SSH key or security answers
+	FieldURL    FieldType = "url"    // A link
+)
+
+// FieldTypes lists the custom field types in the order they are offered
+var FieldTypes = []FieldType{FieldText, FieldHidden, FieldNotes, FieldURL}
+
+// MaxAttachmentSize is the largest file that can be attached to an entry. Attachments are
+// stored inside the encrypted entry, so they are meant for key files and recovery sheets.
+const MaxAttachmentSize = 256 * 1024
+
+// Field is a named value stored with an entry in addition to the standard fields
+type Field struct {
+	Name  string    `json:"name"`
+	Type  FieldType `json:"type"`
+	Value string    `json:"value"`
+}
+
+// Attachment is a small file stored inside an entry, encrypted with it
+type Attachment struct {
+	Name string `json:"name"` // File name it was attached as and is saved as
+	Data []byte `json:"data"` // Contents, base64 encoded in the entry's JSON
+}
+
+// Label returns the display name of a field type
+func (t FieldType) Label() string {
+	switch t {
+	case FieldHidden:
+		return "Hidden"
+	case FieldNotes:
+		return "Notes"
+	case FieldURL:
+		return "URL"
+	}
+	return "Text"
+}
+
+// ReadAttachment reads the file at path as an attachment named after the file
+func ReadAttachment(path string) (Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to read attachment: %v", err)
+	}
+	if info.IsDir() {
+		return Attachment{}, fmt.Errorf("'%s' is a directory, not a file", path)
+	}
+	if info.Size() > MaxAttachmentSize {
+		return Attachment{}, fmt.Errorf("'%s' is %d KB, attachments are limited to %d KB", filepath.Base(path), (info.Size()+1023)/1024, MaxAttachmentSize/1024)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to read attachment: %v", err)
+	}
+	return Attachment{Name: filepath.Base(path), Data: data}, nil
+}
@@ -0,0 +1,270 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// FolderKeyFilename is the name (without .gpg) of the key file that protects a sub-folder with
+// its own passphrase. Like the store's key file it wraps a random key that encrypts the entries.
+const FolderKeyFilename = ".folder-key"
+
+// ErrFolderLocked is returned when an entry is in a folder whose key has not been unlocked
+var ErrFolderLocked = errors.New("folder is locked")
+
+// ScopeMode is how the entries of a folder are encrypted
+type ScopeMode int
+
+const (
+	DataKeyScope    ScopeMode = iota // Encrypted with the store's data key
+	RecipientScope                   // Encrypted to the public keys in the folder's .recipients
+	PassphraseScope                  // Encrypted with a key wrapped by the folder's own passphrase
+)
+
+// Scope is the folder that decides how an entry is encrypted. It is the nearest folder above
+// the entry with a .recipients or folder key file, or the store root when there is none.
+type Scope struct {
+	Folder string    // Store-relative folder holding the settings, empty for the root
+	Mode   ScopeMode // How entries in the scope are encrypted
+}
+
+// ParentFolder returns the store-relative folder of an entry or sub-folder, empty for the root
+func ParentFolder(name string) string {
+	parent := path.Dir(name)
+	if parent == "." || parent == "/" {
+		return ""
+	}
+	return parent
+}
+
+// folderKeyName returns the store-relative name (without .gpg) of a folder's key file
+func folderKeyName(folder string) string {
+	return folder + "/" + FolderKeyFilename
+}
+
+// folderPath returns the filesystem path of a store-relative folder
+func (ef *EncryptionFunctions) folderPath(folder string) string {
+	return filepath.Join(ef.passwordFolder.FolderLocation, filepath.FromSlash(folder))
+}
+
+// FolderScope returns the scope entries directly inside folder are encrypted with.
+// A .recipients file takes precedence over a folder key in the same folder.
+func (ef *EncryptionFunctions) FolderScope(folder string) (Scope, error) {
+	for {
+		recipients, err := LoadRecipients(ef.folderPath(folder))
+		if err != nil {
+			return Scope{}, err
+		}
+		if len(recipients) > 0 {
+			return Scope{Folder: folder, Mode: RecipientScope}, nil
+		}
+		if folder == "" {
+			return Scope{Mode: DataKeyScope}, nil
+		}
+		if fileio.FileExists(filepath.Join(ef.passwordFolder.FolderLocation, filepath.FromSlash(folderKeyName(folder))+".gpg")) {
+			return Scope{Folder: folder, Mode: PassphraseScope}, nil
+		}
+		folder = ParentFolder(folder)
+	}
+}
+
+// ScopeOf returns the scope of the entry stored as fileName
+func (ef *EncryptionFunctions) ScopeOf(fileName string) (Scope, error) {
+	return ef.FolderScope(ParentFolder(fileName))
+}
+
+// ScopeUnlocked reports whether entries in the scope can be read: the folder key has been
+// unlocked, or the local private key is unlocked and is one of the scope's recipients
+func (ef *EncryptionFunctions) ScopeUnlocked(scope Scope) bool {
+	switch scope.Mode {
+	case PassphraseScope:
+		return ef.folderKeys[scope.Folder] != ""
+	case RecipientScope:
+		if ef.identity == nil {
+			return false
+		}
+		recipients, err := ef.Recipients(scope.Folder)
+		if err != nil {
+			return false
+		}
+		fingerprint := ef.identity.GetFingerprint()
+		for _, recipient := range recipients {
+			if recipient.Fingerprint == fingerprint {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// UnlockFolder unlocks the scope of folder with its secret: the folder passphrase, or the
+// private key passphrase for folders shared with recipients. Keys stay in memory until Lock.
+// Returns ErrWrongPassword if the secret is incorrect.
+func (ef *EncryptionFunctions) UnlockFolder(folder string, secret string) error {
+	scope, err := ef.FolderScope(folder)
+	if err != nil {
+		return err
+	}
+
+	switch scope.Mode {
+	case PassphraseScope:
+		fileData, err := ef.passwordFolder.ReadFromFile(folderKeyName(scope.Folder))
+		if err != nil {
+			return fmt.Errorf("failed to read key file of %s: %v", scope.Folder, err)
+		}
+		keyFile, err := DecryptKeyFile(fileData, []byte(secret))
+		if err != nil {
+			return err
+		}
+		if ef.folderKeys == nil {
+			ef.folderKeys = make(map[string]string)
+		}
+		ef.folderKeys[scope.Folder] = keyFile.DataKey
+
+	case RecipientScope:
+		err = ef.UnlockIdentity(secret)
+		if err != nil {
+			return err
+		}
+		if !ef.ScopeUnlocked(scope) {
+			return fmt.Errorf("%w: your key %s is not a recipient of %s", ErrNoIdentity, ef.identity.GetFingerprint(), scope.Folder)
+		}
+	}
+	return nil
+}
+
+// SetFolderPassphrase protects a sub-folder with its own passphrase. A new random key is
+// generated and wrapped by the passphrase, replacing any recipients or previous passphrase of
+// the folder. Entries keep their old encryption until they are re-encrypted with ReencryptFile;
+// the keys they use stay in memory until Lock so they can still be read.
+func (ef *EncryptionFunctions) SetFolderPassphrase(folder string, passphrase string) error {
+	if folder == "" {
+		return fmt.Errorf("the store root is protected by the master password")
+	}
+	dataKey, err := GenerateDataKey()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	keyFile := KeyFile{
+		Version:   KeyFileVersion,
+		DataKey:   dataKey,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err = ef.writeKeyFile(folderKeyName(folder), keyFile, passphrase)
+	if err != nil {
+		return err
+	}
+	err = ef.removeRecipientsFile(folder)
+	if err != nil {
+		return err
+	}
+
+	if ef.folderKeys == nil {
+		ef.folderKeys = make(map[string]string)
+	}
+	ef.retireFolderKey(folder)
+	ef.folderKeys[folder] = dataKey
+	return nil
+}
+
+// ClearFolderEncryption removes the recipients and passphrase of a sub-folder, so its entries
+// use the encryption of the parent folder once they are re-encrypted with ReencryptFile
+func (ef *EncryptionFunctions) ClearFolderEncryption(folder string) error {
+	if folder == "" {
+		return fmt.Errorf("the store root cannot inherit encryption")
+	}
+	err := ef.passwordFolder.CheckWritable()
+	if err != nil {
+		return err
+	}
+	err = ef.removeRecipientsFile(folder)
+	if err != nil {
+		return err
+	}
+	keyPath := filepath.Join(ef.passwordFolder.FolderLocation, filepath.FromSlash(folderKeyName(folder))+".gpg")
+	if fileio.FileExists(keyPath) {
+		err = ef.passwordFolder.DeleteFile(folderKeyName(folder))
+		if err != nil {
+			return err
+		}
+	}
+	ef.retireFolderKey(folder)
+	return nil
+}
+
+// ScopeFilenames returns every entry at or below folder that is encrypted with the folder's
+// scope, leaving out entries in deeper folders that carry settings of their own
+func (ef *EncryptionFunctions) ScopeFilenames(folder string) ([]string, error) {
+	filenames, err := ef.passwordFolder.ListEntryFilenames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password store: %v", err)
+	}
+
+	var inScope []string
+	for _, filename := range filenames {
+		if folder != "" && !strings.HasPrefix(filename, folder+"/") {
+			continue
+		}
+		scope, err := ef.ScopeOf(filename)
+		if err != nil {
+			return nil, err
+		}
+		if len(scope.Folder) > len(folder) {
+			continue
+		}
+		inScope = append(inScope, filename)
+	}
+	return inScope, nil
+}
+
+// removeRecipientsFile deletes the .recipients file of a sub-folder, if it has one
+func (ef *EncryptionFunctions) removeRecipientsFile(folder string) error {
+	err := os.Remove(filepath.Join(ef.folderPath(folder), RecipientsFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove recipients of %s: %v", folder, err)
+	}
+	return nil
+}
+
+// retireFolderKey moves the unlocked key of a folder to the spare keys, which decrypt tries
+// for entries that have not been re-encrypted since the folder's settings changed
+func (ef *EncryptionFunctions) retireFolderKey(folder string) {
+	if key := ef.folderKeys[folder]; key != "" {
+		ef.spareKeys = append(ef.spareKeys, key)
+		delete(ef.folderKeys, folder)
+	}
+}
+
+// candidateKeys returns every unlocked password an entry in the scope may be encrypted with,
+// the scope's own key first
+func (ef *EncryptionFunctions) candidateKeys(scope Scope) []string {
+	var keys []string
+	if scope.Mode == PassphraseScope {
+		keys = append(keys, ef.folderKeys[scope.Folder])
+	}
+	keys = append(keys, ef.passwordFolder.Password, ef.legacyKey)
+	for _, key := range ef.folderKeys {
+		keys = append(keys, key)
+	}
+	keys = append(keys, ef.spareKeys...)
+
+	var unique []string
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
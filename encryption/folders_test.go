@@ -0,0 +1,309 @@
+package encryption
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v3/crypto"
+)
+
+// writeEntry encrypts an entry the way the store does and fails the test on error
+func writeEntry(t *testing.T, ef *EncryptionFunctions, fileName string, password string) {
+	t.Helper()
+	err := ef.EncryptPasswordAndWriteToFile(fileName, Data{Password: password})
+	if err != nil {
+		t.Fatalf("writing %s: %v", fileName, err)
+	}
+}
+
+// readEntry decrypts an entry and returns its password
+func readEntry(ef *EncryptionFunctions, fileName string) (string, error) {
+	data, err := ef.DecryptPasswordFromFile(fileName)
+	return data.Password, err
+}
+
+// generateKey creates a new private key for name
+func generateKey(t *testing.T, name string) *crypto.Key {
+	t.Helper()
+	key, err := crypto.PGP().KeyGeneration().AddUserId(name, name+"@example.com").New().GenerateKey()
+	if err != nil {
+		t.Fatalf("generating a key: %v", err)
+	}
+	return key
+}
+
+// writeKeyring saves key locked with passphrase as the keyring of ef
+func writeKeyring(t *testing.T, ef *EncryptionFunctions, key *crypto.Key, passphrase string) {
+	t.Helper()
+	locked, err := crypto.PGP().LockKey(key, []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	armored, err := locked.Armor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ef.KeyringPath = filepath.Join(t.TempDir(), "keyring.asc")
+	err = os.WriteFile(ef.KeyringPath, []byte(armored), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFolderScope(t *testing.T) {
+	_, ef := newTestStore(t)
+
+	err := ef.SetFolderPassphrase("", "folder passphrase")
+	if err == nil {
+		t.Error("SetFolderPassphrase accepted the store root")
+	}
+	err = ef.SetFolderPassphrase("work", "folder passphrase")
+	if err != nil {
+		t.Fatalf("SetFolderPassphrase: %v", err)
+	}
+
+	tests := []struct {
+		folder string
+		want   Scope
+	}{
+		{"", Scope{Mode: DataKeyScope}},
+		{"home", Scope{Mode: DataKeyScope}},
+		{"work", Scope{Folder: "work", Mode: PassphraseScope}},
+		{"work/team/ops", Scope{Folder: "work", Mode: PassphraseScope}},
+	}
+	for _, test := range tests {
+		got, err := ef.FolderScope(test.folder)
+		if err != nil || got != test.want {
+			t.Errorf("FolderScope(%q) = %+v, %v, want %+v", test.folder, got, err, test.want)
+		}
+	}
+	if scope, _ := ef.ScopeOf("work/team/github"); scope.Folder != "work" {
+		t.Errorf("ScopeOf(work/team/github) = %+v, want the work scope", scope)
+	}
+}
+
+func TestFolderPassphrase(t *testing.T) {
+	pf, ef := newTestStore(t)
+	err := ef.SetFolderPassphrase("work", "folder passphrase")
+	if err != nil {
+		t.Fatalf("SetFolderPassphrase: %v", err)
+	}
+	writeEntry(t, ef, "github", "root secret")
+	writeEntry(t, ef, "work/github", "work secret")
+
+	// The folder's entries do not open with the data key
+	armored, err := pf.ReadFromFile("work/github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecryptBytes(armored, []byte(pf.Password)); err == nil {
+		t.Error("an entry in the protected folder opens with the data key")
+	}
+
+	ef.Lock()
+	err = ef.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if password, err := readEntry(ef, "github"); err != nil || password != "root secret" {
+		t.Errorf("reading the root entry = %q, %v", password, err)
+	}
+	if _, err := readEntry(ef, "work/github"); !errors.Is(err, ErrFolderLocked) {
+		t.Errorf("reading a locked folder = %v, want ErrFolderLocked", err)
+	}
+	if err := ef.UnlockFolder("work", "wrong passphrase"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("UnlockFolder with a wrong passphrase = %v, want ErrWrongPassword", err)
+	}
+	err = ef.UnlockFolder("work/team", "folder passphrase")
+	if err != nil {
+		t.Fatalf("UnlockFolder: %v", err)
+	}
+	if password, err := readEntry(ef, "work/github"); err != nil || password != "work secret" {
+		t.Errorf("reading the unlocked folder = %q, %v", password, err)
+	}
+}
+
+func TestScopeFilenames(t *testing.T) {
+	_, ef := newTestStore(t)
+	for _, folder := range []string{"work", "work/team"} {
+		err := ef.SetFolderPassphrase(folder, "passphrase of "+folder)
+		if err != nil {
+			t.Fatalf("SetFolderPassphrase: %v", err)
+		}
+	}
+	for _, name := range []string{"github", "home/bank", "work/github", "work/ci/token", "work/team/aws"} {
+		writeEntry(t, ef, name, "secret")
+	}
+
+	tests := map[string][]string{
+		"":          {"github", "home/bank"},
+		"home":      {"home/bank"},
+		"work":      {"work/ci/token", "work/github"},
+		"work/team": {"work/team/aws"},
+	}
+	for folder, want := range tests {
+		got, err := ef.ScopeFilenames(folder)
+		if err != nil {
+			t.Fatalf("ScopeFilenames(%q): %v", folder, err)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("ScopeFilenames(%q) = %v, want %v", folder, got, want)
+		}
+	}
+}
+
+func TestClearFolderEncryption(t *testing.T) {
+	_, ef := newTestStore(t)
+	err := ef.SetFolderPassphrase("work", "folder passphrase")
+	if err != nil {
+		t.Fatalf("SetFolderPassphrase: %v", err)
+	}
+	writeEntry(t, ef, "work/github", "work secret")
+
+	err = ef.ClearFolderEncryption("work")
+	if err != nil {
+		t.Fatalf("ClearFolderEncryption: %v", err)
+	}
+	if scope, _ := ef.FolderScope("work"); scope.Mode != DataKeyScope {
+		t.Errorf("FolderScope after clearing = %+v, want the data key", scope)
+	}
+	// The retired folder key still reads the entry until it is re-encrypted
+	err = ef.ReencryptFile("work/github")
+	if err != nil {
+		t.Fatalf("ReencryptFile: %v", err)
+	}
+
+	ef.Lock()
+	err = ef.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if password, err := readEntry(ef, "work/github"); err != nil || password != "work secret" {
+		t.Errorf("reading the re-encrypted entry = %q, %v", password, err)
+	}
+}
+
+func TestRecipientFolder(t *testing.T) {
+	pf, ef := newTestStore(t)
+	// Folders are shared once they exist
+	err := os.Mkdir(filepath.Join(pf.FolderLocation, "shared"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	own := generateKey(t, "alice")
+	writeKeyring(t, ef, own, "alice passphrase")
+	teammate, err := newRecipient(generateKey(t, "bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ef.AddRecipient("shared", teammate)
+	if err == nil {
+		t.Error("AddRecipient worked before the private key was unlocked")
+	}
+	err = ef.UnlockIdentity("alice passphrase")
+	if err != nil {
+		t.Fatalf("UnlockIdentity: %v", err)
+	}
+	err = ef.AddRecipient("shared", teammate)
+	if err != nil {
+		t.Fatalf("AddRecipient: %v", err)
+	}
+
+	// The local key is added along with the first recipient
+	recipients, err := ef.Recipients("shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fingerprints []string
+	for _, recipient := range recipients {
+		fingerprints = append(fingerprints, recipient.Fingerprint)
+	}
+	if !slices.Equal(fingerprints, []string{own.GetFingerprint(), teammate.Fingerprint}) {
+		t.Errorf("recipients = %v, want alice and bob", fingerprints)
+	}
+	if scope, _ := ef.FolderScope("shared/team"); scope != (Scope{Folder: "shared", Mode: RecipientScope}) {
+		t.Errorf("FolderScope(shared/team) = %+v, want the shared recipients", scope)
+	}
+	if err := ef.RemoveRecipient("shared", own.GetFingerprint()); err == nil {
+		t.Error("RemoveRecipient removed the local key")
+	}
+
+	writeEntry(t, ef, "shared/wifi", "shared secret")
+	ef.Lock()
+	err = ef.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if _, err := readEntry(ef, "shared/wifi"); !errors.Is(err, ErrFolderLocked) {
+		t.Errorf("reading before the private key is unlocked = %v, want ErrFolderLocked", err)
+	}
+	err = ef.UnlockFolder("shared", "alice passphrase")
+	if err != nil {
+		t.Fatalf("UnlockFolder: %v", err)
+	}
+	if password, err := readEntry(ef, "shared/wifi"); err != nil || password != "shared secret" {
+		t.Errorf("reading the shared entry = %q, %v", password, err)
+	}
+
+	err = ef.RemoveRecipient("shared", teammate.Fingerprint)
+	if err != nil {
+		t.Fatalf("RemoveRecipient: %v", err)
+	}
+	if recipients, _ := ef.Recipients("shared"); len(recipients) != 1 {
+		t.Errorf("%d recipients left, want only the local key", len(recipients))
+	}
+}
+
+func TestUnshareVault(t *testing.T) {
+	pf, ef := newTestStore(t)
+	writeEntry(t, ef, "github", "hunter2")
+	writeEntry(t, ef, "bank", "pin")
+	writeKeyring(t, ef, generateKey(t, "alice"), "alice passphrase")
+	teammate, err := newRecipient(generateKey(t, "bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ef.UnlockIdentity("alice passphrase")
+	if err != nil {
+		t.Fatalf("UnlockIdentity: %v", err)
+	}
+	err = ef.AddRecipient("", teammate)
+	if err != nil {
+		t.Fatalf("AddRecipient: %v", err)
+	}
+
+	// The switch stops after one entry, leaving the other on the data key
+	err = ef.ReencryptFile("github")
+	if err != nil {
+		t.Fatalf("ReencryptFile: %v", err)
+	}
+	err = ef.UnshareVault([]string{"github"})
+	if err != nil {
+		t.Fatalf("UnshareVault: %v", err)
+	}
+	if shared, err := ef.RecipientMode(); err != nil || shared {
+		t.Errorf("RecipientMode = %v, %v after undoing the share", shared, err)
+	}
+
+	// Both entries open with the master password again
+	reopened := NewEncryption(pf)
+	err = reopened.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	for name, want := range map[string]string{"github": "hunter2", "bank": "pin"} {
+		if password, err := readEntry(reopened, name); err != nil || password != want {
+			t.Errorf("%s = %q, %v, want %q", name, password, err, want)
+		}
+	}
+
+	reopened.Lock()
+	if err := reopened.UnshareVault(nil); !errors.Is(err, ErrStoreLocked) {
+		t.Errorf("UnshareVault without the data key = %v, want ErrStoreLocked", err)
+	}
+}
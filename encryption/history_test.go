@@ -0,0 +1,36 @@
+package encryption
+
+import "testing"
+
+func TestRestoreRevision(t *testing.T) {
+	pf, ef := newTestStore(t)
+	writeEntry(t, ef, "github", "first")
+	writeEntry(t, ef, "github", "second")
+
+	revisions, err := pf.ListRevisions("github")
+	if err != nil || len(revisions) != 1 {
+		t.Fatalf("ListRevisions = %v, %v, want the first version", revisions, err)
+	}
+	data, err := ef.DecryptRevision("github", revisions[0])
+	if err != nil || data.Password != "first" {
+		t.Fatalf("DecryptRevision = %q, %v, want first", data.Password, err)
+	}
+
+	err = ef.RestoreRevision("github", revisions[0])
+	if err != nil {
+		t.Fatalf("RestoreRevision: %v", err)
+	}
+	if password, err := readEntry(ef, "github"); err != nil || password != "first" {
+		t.Errorf("github = %q, %v after restoring, want first", password, err)
+	}
+
+	// The version that was replaced is kept, so the restore can be undone
+	revisions, err = pf.ListRevisions("github")
+	if err != nil || len(revisions) != 2 {
+		t.Fatalf("ListRevisions = %v, %v, want two revisions", revisions, err)
+	}
+	data, err = ef.DecryptRevision("github", revisions[0])
+	if err != nil || data.Password != "second" {
+		t.Errorf("newest revision = %q, %v, want the replaced version", data.Password, err)
+	}
+}
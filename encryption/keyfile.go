@@ -0,0 +1,247 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// KeyFileVersion is the current format of the key file at .checker/init.gpg.
+// Version 1 stored a user-typed phrase that was used directly as the entry password;
+// version 2 stores a randomly generated data key wrapped by the master password.
+const KeyFileVersion = 2
+
+// dataKeySize is the number of random bytes in a data key
+const dataKeySize = 32
+
+// KeyFile is the decrypted contents of .checker/init.gpg. It is encrypted with the master
+// password, so changing the master password only re-wraps this file and never the entries.
+type KeyFile struct {
+	Version   int       `json:"version"`              // Key file format, see KeyFileVersion
+	DataKey   string    `json:"data_key"`             // Base64 encoded random key that encrypts every entry
+	LegacyKey string    `json:"legacy_key,omitempty"` // Version 1 phrase, kept until every entry is migrated
+	CreatedAt time.Time `json:"created_at"`           // When the data key was generated
+	UpdatedAt time.Time `json:"updated_at"`           // When the key file was last re-wrapped
+}
+
+// GenerateDataKey returns a new random data key
+func GenerateDataKey() (string, error) {
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate data key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// DecryptKeyFile decrypts the contents of a key file with the master password.
+// A version 1 file is returned as a KeyFile with Version 1 and the phrase as its DataKey.
+// Returns ErrWrongPassword if the master password is incorrect.
+func DecryptKeyFile(armored []byte, masterPassword []byte) (KeyFile, error) {
+	plaintext, err := DecryptBytes(armored, masterPassword)
+	if err != nil {
+		return KeyFile{}, ErrWrongPassword
+	}
+
+	keyFile := KeyFile{}
+	err = json.Unmarshal(plaintext, &keyFile)
+	if err != nil {
+		return KeyFile{}, fmt.Errorf("failed to parse key file: %v", err)
+	}
+	if keyFile.Version >= 2 {
+		if keyFile.DataKey == "" {
+			return KeyFile{}, fmt.Errorf("key file has no data key")
+		}
+		return keyFile, nil
+	}
+
+	// Version 1 files are a Data entry whose password field holds the phrase
+	legacy := Data{}
+	err = json.Unmarshal(plaintext, &legacy)
+	if err != nil || legacy.Password == "" {
+		return KeyFile{}, fmt.Errorf("key file has no data key")
+	}
+	return KeyFile{
+		Version:   1,
+		DataKey:   legacy.Password,
+		CreatedAt: legacy.CreatedAt,
+		UpdatedAt: legacy.UpdatedAt,
+	}, nil
+}
+
+// Keys returns every key entries may be encrypted with, the data key first
+func (k KeyFile) Keys() []string {
+	if k.LegacyKey == "" {
+		return []string{k.DataKey}
+	}
+	return []string{k.DataKey, k.LegacyKey}
+}
+
+// readKeyFile decrypts the store's key file with the master password
+func (ef *EncryptionFunctions) readKeyFile(masterPassword string) (KeyFile, error) {
+	fileData, err := ef.passwordFolder.ReadFromFile(InitFilename)
+	if err != nil {
+		return KeyFile{}, fmt.Errorf("failed to read key file: %v", err)
+	}
+	return DecryptKeyFile(fileData, []byte(masterPassword))
+}
+
+// writeKeyFile encrypts a key file with the password that protects it and saves it as fileName
+func (ef *EncryptionFunctions) writeKeyFile(fileName string, keyFile KeyFile, password string) error {
+	config, err := ef.CryptoConfig()
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.Marshal(keyFile)
+	if err != nil {
+		return err
+	}
+	armored, err := EncryptBytesWithConfig(jsonData, []byte(password), config)
+	if err != nil {
+		return err
+	}
+	return ef.passwordFolder.WriteToFile(fileName, armored)
+}
+
+// InitStore sets up a new store: it generates a data key, wraps it with the master password
+// and unlocks the store with it
+func (ef *EncryptionFunctions) InitStore(masterPassword string) error {
+	dataKey, err := GenerateDataKey()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	keyFile := KeyFile{
+		Version:   KeyFileVersion,
+		DataKey:   dataKey,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err = ef.writeKeyFile(InitFilename, keyFile, masterPassword)
+	if err != nil {
+		return err
+	}
+	ef.passwordFolder.InitCheck = true
+	ef.passwordFolder.Password = dataKey
+	ef.keyVersion = KeyFileVersion
+	ef.legacyKey = ""
+	return nil
+}
+
+// VerifyMasterPassword checks the master password against the key file without unlocking,
+// or the private key passphrase in recipient mode. Returns ErrWrongPassword if it is incorrect.
+func (ef *EncryptionFunctions) VerifyMasterPassword(masterPassword string) error {
+	recipientMode, err := ef.RecipientMode()
+	if err != nil {
+		return err
+	}
+	if recipientMode {
+		key, err := ef.readIdentity()
+		if err != nil {
+			return err
+		}
+		unlocked, err := key.Unlock([]byte(masterPassword))
+		if err != nil {
+			return ErrWrongPassword
+		}
+		unlocked.ClearPrivateParams()
+		return nil
+	}
+	_, err = ef.readKeyFile(masterPassword)
+	return err
+}
+
+// ChangeMasterPassword re-wraps the data key with a new master password. Entries are untouched.
+// Returns ErrWrongPassword if the current master password is incorrect.
+func (ef *EncryptionFunctions) ChangeMasterPassword(currentPassword, newPassword string) error {
+	recipientMode, err := ef.RecipientMode()
+	if err != nil {
+		return err
+	}
+	if recipientMode {
+		return fmt.Errorf("this vault is opened with your private key, change its passphrase with your OpenPGP tool instead")
+	}
+
+	keyFile, err := ef.readKeyFile(currentPassword)
+	if err != nil {
+		return err
+	}
+	if keyFile.Version < KeyFileVersion {
+		return fmt.Errorf("the store still uses the old key scheme, log in again to upgrade it first")
+	}
+
+	keyFile.UpdatedAt = time.Now()
+	err = ef.writeKeyFile(InitFilename, keyFile, newPassword)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the new key file opens before reporting success
+	check, err := ef.readKeyFile(newPassword)
+	if err != nil || check.DataKey != keyFile.DataKey {
+		return fmt.Errorf("key file verification failed after changing the master password")
+	}
+	return nil
+}
+
+// NeedsMigration reports whether the unlocked store still has entries under the version 1 phrase
+func (ef *EncryptionFunctions) NeedsMigration() bool {
+	return ef.keyVersion < KeyFileVersion || ef.legacyKey != ""
+}
+
+// StartMigration moves a version 1 store to a generated data key. The new key file keeps the
+// old phrase as its legacy key so entries stay readable until each has been re-encrypted
+// with ReencryptFile; FinishMigration then drops it. Starting an interrupted migration
+// again is a no-op.
+func (ef *EncryptionFunctions) StartMigration(masterPassword string) error {
+	if ef.keyVersion >= KeyFileVersion {
+		return nil
+	}
+	keyFile, err := ef.readKeyFile(masterPassword)
+	if err != nil {
+		return err
+	}
+
+	dataKey, err := GenerateDataKey()
+	if err != nil {
+		return err
+	}
+	upgraded := KeyFile{
+		Version:   KeyFileVersion,
+		DataKey:   dataKey,
+		LegacyKey: keyFile.DataKey,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err = ef.writeKeyFile(InitFilename, upgraded, masterPassword)
+	if err != nil {
+		return err
+	}
+	ef.passwordFolder.Password = dataKey
+	ef.keyVersion = KeyFileVersion
+	ef.legacyKey = keyFile.DataKey
+	return nil
+}
+
+// FinishMigration removes the legacy phrase from the key file once every entry uses the data key
+func (ef *EncryptionFunctions) FinishMigration(masterPassword string) error {
+	keyFile, err := ef.readKeyFile(masterPassword)
+	if err != nil {
+		return err
+	}
+	if keyFile.LegacyKey == "" {
+		ef.legacyKey = ""
+		return nil
+	}
+
+	keyFile.LegacyKey = ""
+	keyFile.UpdatedAt = time.Now()
+	err = ef.writeKeyFile(InitFilename, keyFile, masterPassword)
+	if err != nil {
+		return err
+	}
+	ef.legacyKey = ""
+	return nil
+}
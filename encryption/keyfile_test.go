@@ -0,0 +1,217 @@
+package encryption
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+const testMasterPassword = "correct horse battery"
+
+// fastConfig uses the iterated key derivation, which keeps the tests quick
+var fastConfig = DefaultCryptoConfig("rfc4880")
+
+// newTestFolder creates an empty store in a temporary directory without a key file
+func newTestFolder(t *testing.T) *fileio.PasswordFolder {
+	t.Helper()
+	location := t.TempDir()
+	pf, err := fileio.OpenPasswordFolder(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveCryptoConfig(location, fastConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pf
+}
+
+// newTestStore creates a store initialised with testMasterPassword and unlocked
+func newTestStore(t *testing.T) (*fileio.PasswordFolder, *EncryptionFunctions) {
+	t.Helper()
+	pf := newTestFolder(t)
+	ef := NewEncryption(pf)
+	err := ef.InitStore(testMasterPassword)
+	if err != nil {
+		t.Fatalf("InitStore: %v", err)
+	}
+	return pf, ef
+}
+
+// writeEncrypted stores data as fileName encrypted directly with password
+func writeEncrypted(t *testing.T, pf *fileio.PasswordFolder, fileName string, data any, password string) {
+	t.Helper()
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	armored, err := EncryptBytesWithConfig(plaintext, []byte(password), fastConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.WriteToFile(fileName, armored)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readKeyFile decrypts the store's key file the way Unlock does
+func readKeyFile(t *testing.T, pf *fileio.PasswordFolder, masterPassword string) KeyFile {
+	t.Helper()
+	armored, err := pf.ReadFromFile(InitFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile, err := DecryptKeyFile(armored, []byte(masterPassword))
+	if err != nil {
+		t.Fatalf("DecryptKeyFile: %v", err)
+	}
+	return keyFile
+}
+
+func TestInitStoreUnlock(t *testing.T) {
+	pf, ef := newTestStore(t)
+	dataKey := pf.Password
+
+	keyFile := readKeyFile(t, pf, testMasterPassword)
+	if keyFile.Version != KeyFileVersion || keyFile.DataKey != dataKey || keyFile.LegacyKey != "" {
+		t.Errorf("key file = %+v, want version %d with the data key", keyFile, KeyFileVersion)
+	}
+
+	ef.Lock()
+	err := ef.Unlock("wrong password")
+	if !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Unlock with a wrong password = %v, want ErrWrongPassword", err)
+	}
+	err = ef.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if pf.Password != dataKey || ef.NeedsMigration() {
+		t.Errorf("after Unlock: data key restored = %v, NeedsMigration = %v", pf.Password == dataKey, ef.NeedsMigration())
+	}
+}
+
+func TestChangeMasterPasswordKeepsDataKey(t *testing.T) {
+	pf, ef := newTestStore(t)
+	dataKey := pf.Password
+
+	err := ef.ChangeMasterPassword("wrong password", "new password")
+	if !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("ChangeMasterPassword with a wrong password = %v, want ErrWrongPassword", err)
+	}
+	err = ef.ChangeMasterPassword(testMasterPassword, "new password")
+	if err != nil {
+		t.Fatalf("ChangeMasterPassword: %v", err)
+	}
+	if keyFile := readKeyFile(t, pf, "new password"); keyFile.DataKey != dataKey {
+		t.Error("changing the master password replaced the data key")
+	}
+	if err := ef.VerifyMasterPassword(testMasterPassword); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("the old master password still opens the key file: %v", err)
+	}
+}
+
+func TestDecryptKeyFileVersion1(t *testing.T) {
+	pf := newTestFolder(t)
+	writeEncrypted(t, pf, InitFilename, Data{Password: "a typed phrase"}, testMasterPassword)
+
+	keyFile := readKeyFile(t, pf, testMasterPassword)
+	if keyFile.Version != 1 || keyFile.DataKey != "a typed phrase" {
+		t.Errorf("key file = %+v, want version 1 with the phrase as the data key", keyFile)
+	}
+	if keys := keyFile.Keys(); len(keys) != 1 || keys[0] != "a typed phrase" {
+		t.Errorf("Keys = %v, want only the phrase", keys)
+	}
+}
+
+func TestDecryptKeyFileWithoutKey(t *testing.T) {
+	pf := newTestFolder(t)
+	writeEncrypted(t, pf, InitFilename, KeyFile{Version: 2}, testMasterPassword)
+
+	armored, err := pf.ReadFromFile(InitFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = DecryptKeyFile(armored, []byte(testMasterPassword))
+	if err == nil || errors.Is(err, ErrWrongPassword) {
+		t.Errorf("DecryptKeyFile = %v, want an error about the missing data key", err)
+	}
+}
+
+func TestMigrateVersion1Store(t *testing.T) {
+	const phrase = "a typed phrase"
+	pf := newTestFolder(t)
+	writeEncrypted(t, pf, InitFilename, Data{Password: phrase}, testMasterPassword)
+	writeEncrypted(t, pf, "github", Data{Password: "hunter2"}, phrase)
+
+	ef := NewEncryption(pf)
+	err := ef.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if !ef.NeedsMigration() || pf.Password != phrase {
+		t.Fatalf("a version 1 store unlocked with NeedsMigration = %v", ef.NeedsMigration())
+	}
+	err = ef.ChangeMasterPassword(testMasterPassword, "new password")
+	if err == nil {
+		t.Error("ChangeMasterPassword was allowed before the store was migrated")
+	}
+
+	err = ef.StartMigration(testMasterPassword)
+	if err != nil {
+		t.Fatalf("StartMigration: %v", err)
+	}
+	keyFile := readKeyFile(t, pf, testMasterPassword)
+	if keyFile.Version != KeyFileVersion || keyFile.LegacyKey != phrase || keyFile.DataKey == phrase {
+		t.Fatalf("key file after StartMigration = %+v, want a new data key keeping the phrase", keyFile)
+	}
+	// Starting an interrupted migration again must not replace the new data key
+	err = ef.StartMigration(testMasterPassword)
+	if err != nil {
+		t.Fatalf("StartMigration again: %v", err)
+	}
+	if readKeyFile(t, pf, testMasterPassword).DataKey != keyFile.DataKey {
+		t.Fatal("StartMigration generated a second data key")
+	}
+
+	// Entries not yet migrated stay readable with the legacy phrase
+	data, err := ef.DecryptPasswordFromFile("github")
+	if err != nil || data.Password != "hunter2" {
+		t.Fatalf("reading an unmigrated entry = %q, %v", data.Password, err)
+	}
+	err = ef.ReencryptFile("github")
+	if err != nil {
+		t.Fatalf("ReencryptFile: %v", err)
+	}
+	err = ef.FinishMigration(testMasterPassword)
+	if err != nil {
+		t.Fatalf("FinishMigration: %v", err)
+	}
+	if ef.NeedsMigration() {
+		t.Error("NeedsMigration after FinishMigration")
+	}
+	if keyFile := readKeyFile(t, pf, testMasterPassword); keyFile.LegacyKey != "" {
+		t.Error("FinishMigration kept the legacy phrase")
+	}
+
+	// The entry now opens with the data key alone
+	armored, err := pf.ReadFromFile("github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecryptBytes(armored, []byte(phrase)); err == nil {
+		t.Error("the migrated entry still opens with the legacy phrase")
+	}
+	fresh := NewEncryption(pf)
+	err = fresh.Unlock(testMasterPassword)
+	if err != nil {
+		t.Fatalf("Unlock after migrating: %v", err)
+	}
+	data, err = fresh.DecryptPasswordFromFile("github")
+	if err != nil || data.Password != "hunter2" {
+		t.Errorf("reading the migrated entry = %q, %v", data.Password, err)
+	}
+}
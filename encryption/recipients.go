@@ -0,0 +1,371 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/gopenpgp/v3/crypto"
+	"github.com/ProtonMail/gopenpgp/v3/profile"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// RecipientsFilename is the name of the recipients file. When the one at the store root lists
+// any public keys the store is in recipient mode: entries are encrypted to every listed key
+// instead of the data key, and each teammate decrypts with their own private key.
+// A sub-folder with its own recipients file shares just the entries below it.
+const RecipientsFilename = ".recipients"
+
+// publicKeyHeader starts every armored public key in the recipients file
+const publicKeyHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
+// ErrNoIdentity is returned when a recipient vault cannot be unlocked with the local private key
+var ErrNoIdentity = errors.New("cannot open recipient vault")
+
+// Recipient is a public key entries are encrypted to
+type Recipient struct {
+	Fingerprint string      // Hex fingerprint of the primary key
+	Name        string      // Primary user ID, e.g. "Jane Doe <jane@example.com>"
+	key         *crypto.Key // Public key
+}
+
+// newRecipient describes a public key, converting private keys to their public part
+func newRecipient(key *crypto.Key) (Recipient, error) {
+	if key.IsPrivate() {
+		public, err := key.ToPublic()
+		if err != nil {
+			return Recipient{}, err
+		}
+		key = public
+	}
+	if !key.CanEncrypt(time.Now().Unix()) {
+		return Recipient{}, fmt.Errorf("key %s cannot be used for encryption (expired or revoked?)", key.GetFingerprint())
+	}
+
+	name := key.GetHexKeyID()
+	if _, identity := key.GetEntity().PrimaryIdentity(time.Now(), nil); identity != nil && identity.Name != "" {
+		name = identity.Name
+	}
+	return Recipient{
+		Fingerprint: key.GetFingerprint(),
+		Name:        name,
+		key:         key,
+	}, nil
+}
+
+// ReadPublicKey reads an armored OpenPGP key from a file to use as a recipient
+func ReadPublicKey(path string) (Recipient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Recipient{}, fmt.Errorf("failed to read key file: %v", err)
+	}
+	key, err := crypto.NewKeyFromArmored(string(data))
+	if err != nil {
+		return Recipient{}, fmt.Errorf("failed to parse key file %s: %v", path, err)
+	}
+	return newRecipient(key)
+}
+
+// LoadRecipients reads the recipients of the store or folder at storeLocation.
+// A folder without a recipients file has none and inherits the encryption of its parent.
+func LoadRecipients(storeLocation string) ([]Recipient, error) {
+	path := filepath.Join(storeLocation, RecipientsFilename)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	var recipients []Recipient
+	for _, block := range strings.Split(string(data), publicKeyHeader)[1:] {
+		key, err := crypto.NewKeyFromArmored(publicKeyHeader + block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		recipient, err := newRecipient(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in %s: %v", path, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+// SaveRecipients writes the armored public keys of the recipients to the store or folder at storeLocation
+func SaveRecipients(storeLocation string, recipients []Recipient) error {
+	var content strings.Builder
+	for _, recipient := range recipients {
+		armored, err := recipient.key.Armor()
+		if err != nil {
+			return fmt.Errorf("failed to encode key %s: %v", recipient.Fingerprint, err)
+		}
+		content.WriteString(strings.TrimSpace(armored) + "\n")
+	}
+
+	path := filepath.Join(storeLocation, RecipientsFilename)
+	err := fileio.WriteFileAtomic(path, []byte(content.String()), fileio.FilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// recipientKeyRing builds the key ring entries are encrypted to
+func recipientKeyRing(recipients []Recipient) (*crypto.KeyRing, error) {
+	keyRing, err := crypto.NewKeyRing(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, recipient := range recipients {
+		err = keyRing.AddKey(recipient.key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keyRing, nil
+}
+
+// RecipientMode reports whether the store root encrypts entries to recipients, in which case
+// the store is opened with the local private key
+func (ef *EncryptionFunctions) RecipientMode() (bool, error) {
+	recipients, err := ef.Recipients("")
+	return len(recipients) > 0, err
+}
+
+// Recipients returns the public keys listed in the recipients file of a folder, empty for the
+// store root. A folder that inherits its encryption has none.
+func (ef *EncryptionFunctions) Recipients(folder string) ([]Recipient, error) {
+	return LoadRecipients(ef.folderPath(folder))
+}
+
+// IdentityUnlocked reports whether the local private key has been unlocked
+func (ef *EncryptionFunctions) IdentityUnlocked() bool {
+	return ef.identity != nil
+}
+
+// readIdentity reads the private key from the local keyring file
+func (ef *EncryptionFunctions) readIdentity() (*crypto.Key, error) {
+	if ef.KeyringPath == "" {
+		return nil, fmt.Errorf("%w: no keyring file is configured", ErrNoIdentity)
+	}
+	data, err := os.ReadFile(ef.KeyringPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read keyring %s: %v", ErrNoIdentity, ef.KeyringPath, err)
+	}
+	key, err := crypto.NewKeyFromArmored(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse keyring %s: %v", ErrNoIdentity, ef.KeyringPath, err)
+	}
+	if !key.IsPrivate() {
+		return nil, fmt.Errorf("%w: %s holds a public key, a private key is needed", ErrNoIdentity, ef.KeyringPath)
+	}
+	locked, err := key.IsLocked()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoIdentity, err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("%w: the private key in %s has no passphrase, protect it before using it", ErrNoIdentity, ef.KeyringPath)
+	}
+	return key, nil
+}
+
+// Identity returns the public part of the local private key, without unlocking it
+func (ef *EncryptionFunctions) Identity() (Recipient, error) {
+	key, err := ef.readIdentity()
+	if err != nil {
+		return Recipient{}, err
+	}
+	return newRecipient(key)
+}
+
+// UnlockIdentity unlocks the local private key with its passphrase so entries encrypted to it
+// can be decrypted. Returns ErrWrongPassword if the passphrase is incorrect.
+func (ef *EncryptionFunctions) UnlockIdentity(passphrase string) error {
+	key, err := ef.readIdentity()
+	if err != nil {
+		return err
+	}
+	unlocked, err := key.Unlock([]byte(passphrase))
+	if err != nil {
+		return ErrWrongPassword
+	}
+	if ef.identity != nil {
+		ef.identity.ClearPrivateParams()
+	}
+	ef.identity = unlocked
+	return nil
+}
+
+// unlockRecipientMode unlocks a recipient vault with the local private key and checks that
+// the key is one of the store's recipients
+func (ef *EncryptionFunctions) unlockRecipientMode(passphrase string, recipients []Recipient) error {
+	// Entries are not encrypted with a data key, so the typed passphrase must not be kept as one
+	ef.passwordFolder.Password = ""
+	err := ef.UnlockIdentity(passphrase)
+	if err != nil {
+		return err
+	}
+	fingerprint := ef.identity.GetFingerprint()
+	for _, recipient := range recipients {
+		if recipient.Fingerprint == fingerprint {
+			ef.keyVersion = KeyFileVersion
+			return nil
+		}
+	}
+	ef.Lock()
+	return fmt.Errorf("%w: your key %s is not a recipient of this vault, ask a member to add it", ErrNoIdentity, fingerprint)
+}
+
+// AddRecipient adds a public key to the recipients of a folder, empty for the store root.
+// Adding the first recipient switches the folder to recipients, so the local key is added as
+// well and must already be unlocked; a sub-folder's passphrase is dropped at the same time.
+// Entries keep their old encryption until they are re-encrypted with ReencryptFile.
+func (ef *EncryptionFunctions) AddRecipient(folder string, recipient Recipient) error {
+	err := ef.passwordFolder.CheckWritable()
+	if err != nil {
+		return err
+	}
+	recipients, err := ef.Recipients(folder)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		if ef.identity == nil {
+			return fmt.Errorf("unlock your private key before adding recipients")
+		}
+		own, err := newRecipient(ef.identity)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, own)
+	}
+	for _, existing := range recipients {
+		if existing.Fingerprint == recipient.Fingerprint {
+			return fmt.Errorf("%s is already a recipient", recipient.Name)
+		}
+	}
+	err = SaveRecipients(ef.folderPath(folder), append(recipients, recipient))
+	if err != nil || folder == "" {
+		return err
+	}
+
+	// The recipients take over from any passphrase the folder had
+	keyPath := filepath.Join(ef.passwordFolder.FolderLocation, filepath.FromSlash(folderKeyName(folder))+".gpg")
+	if fileio.FileExists(keyPath) {
+		ef.retireFolderKey(folder)
+		return ef.passwordFolder.DeleteFile(folderKeyName(folder))
+	}
+	return nil
+}
+
+// UnshareVault undoes adding the first recipient to the store root when its entries could not
+// all be re-encrypted to the recipients. A recipient vault is opened without the data key, so
+// entries left on it would be lost; instead the recipients file is removed and the entries in
+// filenames, already re-encrypted, go back to the data key along with the trash.
+// The data key must still be unlocked, as it is in the session that added the recipient.
+func (ef *EncryptionFunctions) UnshareVault(filenames []string) error {
+	if ef.passwordFolder.Password == "" {
+		return ErrStoreLocked
+	}
+	path := filepath.Join(ef.passwordFolder.FolderLocation, RecipientsFilename)
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %v", path, err)
+	}
+	for _, fileName := range filenames {
+		err = ef.ReencryptFile(fileName)
+		if err != nil {
+			return err
+		}
+	}
+	return ef.ReencryptTrash()
+}
+
+// RemoveRecipient removes a public key from the recipients of a folder, empty for the store root.
+// The local key cannot be removed, since the folder could no longer be opened. Entries remain
+// readable by the removed key until they are re-encrypted with ReencryptFile.
+func (ef *EncryptionFunctions) RemoveRecipient(folder string, fingerprint string) error {
+	if ef.identity != nil && ef.identity.GetFingerprint() == fingerprint {
+		return fmt.Errorf("you cannot remove your own key")
+	}
+	err := ef.passwordFolder.CheckWritable()
+	if err != nil {
+		return err
+	}
+	recipients, err := ef.Recipients(folder)
+	if err != nil {
+		return err
+	}
+	for i, recipient := range recipients {
+		if recipient.Fingerprint == fingerprint {
+			return SaveRecipients(ef.folderPath(folder), append(recipients[:i], recipients[i+1:]...))
+		}
+	}
+	return fmt.Errorf("no recipient with fingerprint %s", fingerprint)
+}
+
+// encrypt encrypts the entry stored as fileName for its scope: to the recipients of its folder,
+// with its folder key, or with the data key
+func (ef *EncryptionFunctions) encrypt(fileName string, plaintext []byte) ([]byte, error) {
+	config, err := ef.CryptoConfig()
+	if err != nil {
+		return nil, err
+	}
+	scope, err := ef.ScopeOf(fileName)
+	if err != nil {
+		return nil, err
+	}
+	switch scope.Mode {
+	case DataKeyScope:
+		// Never fall back to an empty key once the session has locked
+		if ef.passwordFolder.Password == "" {
+			return nil, ErrStoreLocked
+		}
+		return EncryptBytesWithConfig(plaintext, []byte(ef.passwordFolder.Password), config)
+	case PassphraseScope:
+		key := ef.folderKeys[scope.Folder]
+		if key == "" {
+			return nil, fmt.Errorf("%w: %s", ErrFolderLocked, scope.Folder)
+		}
+		return EncryptBytesWithConfig(plaintext, []byte(key), config)
+	}
+
+	recipients, err := ef.Recipients(scope.Folder)
+	if err != nil {
+		return nil, err
+	}
+	keyRing, err := recipientKeyRing(recipients)
+	if err != nil {
+		return nil, err
+	}
+	pgp := crypto.PGPWithProfile(config.profile())
+	encHandle, err := pgp.Encryption().Recipients(keyRing).New()
+	if err != nil {
+		return nil, err
+	}
+	pgpMessage, err := encHandle.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return pgpMessage.ArmorBytes()
+}
+
+// decryptWithIdentity decrypts an entry encrypted to the local private key
+func (ef *EncryptionFunctions) decryptWithIdentity(fileData []byte) ([]byte, error) {
+	pgp := crypto.PGPWithProfile(profile.RFC9580())
+	decHandle, err := pgp.Decryption().DecryptionKey(ef.identity).New()
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := decHandle.Decrypt(fileData, crypto.Armor)
+	if err != nil {
+		return nil, err
+	}
+	return decrypted.Bytes(), nil
+}
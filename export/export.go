@@ -0,0 +1,198 @@
+// Package export provides plaintext export of the password store to CSV and JSON files.
+// Every entry is decrypted with the current master key and written out in a portable format
+// so credentials can be handed off or migrated to another tool.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// Format identifies the output format of an export
+type Format string
+
+const (
+	FormatCSV  Format = "csv"  // Comma separated values with a header row
+	FormatJSON Format = "json" // Indented JSON array of records
+)
+
+// Record is a single decrypted password entry as it appears in an export file
+type Record struct {
+	SiteName    string                  `json:"site_name"`
+	Type        encryption.EntryType    `json:"type,omitempty"` // JSON exports only, empty for older logins
+	Username    string                  `json:"username"`
+	Email       string                  `json:"email"`
+	URL         string                  `json:"url"`
+	Password    string                  `json:"password"`
+	OTP         string                  `json:"otp,omitempty"`
+	Fields      []encryption.Field      `json:"fields,omitempty"`      // A JSON array in CSV exports
+	Attachments []encryption.Attachment `json:"attachments,omitempty"` // JSON exports only, base64 encoded
+	Tags        []string                `json:"tags,omitempty"`        // JSON exports only
+	Favourite   bool                    `json:"favourite,omitempty"`   // JSON exports only
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// csvHeader is the header row written at the top of CSV exports
+var csvHeader = []string{"site_name", "username", "email", "url", "password", "otp", "fields", "created_at", "updated_at"}
+
+// CollectRecords decrypts every entry in the password store and returns them as export records.
+// Unlike the list view, a file that cannot be decrypted is treated as an error so an export
+// never silently leaves entries behind.
+func CollectRecords(pf *fileio.PasswordFolder, ef *encryption.EncryptionFunctions) ([]Record, error) {
+	filenames, err := pf.ListEntryFilenames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password store: %v", err)
+	}
+
+	records := make([]Record, 0, len(filenames))
+	for _, filename := range filenames {
+		data, err := ef.DecryptPasswordFromFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt '%s.gpg': %w", filename, err)
+		}
+
+		records = append(records, Record{
+			SiteName:    utils.ParseFilenameToSiteName(filename),
+			Type:        data.Type,
+			Username:    data.Username,
+			Email:       data.Email,
+			URL:         data.URL,
+			Password:    data.Password,
+			OTP:         data.OTP,
+			Fields:      data.Fields,
+			Attachments: data.Attachments,
+			Tags:        data.Tags,
+			Favourite:   data.Favourite,
+			CreatedAt:   data.CreatedAt,
+			UpdatedAt:   data.UpdatedAt,
+		})
+	}
+	return records, nil
+}
+
+// LockedFolders returns the folders holding entries that cannot be decrypted until the folder's
+// own passphrase, or the private key of its recipients, is unlocked. CollectRecords fails on
+// such entries, so callers unlock each folder first. Folders are listed once, in store order.
+func LockedFolders(pf *fileio.PasswordFolder, ef *encryption.EncryptionFunctions) ([]string, error) {
+	filenames, err := pf.ListEntryFilenames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password store: %v", err)
+	}
+
+	var folders []string
+	for _, filename := range filenames {
+		scope, err := ef.ScopeOf(filename)
+		if err != nil {
+			return nil, err
+		}
+		if !ef.ScopeUnlocked(scope) && !slices.Contains(folders, scope.Folder) {
+			folders = append(folders, scope.Folder)
+		}
+	}
+	return folders, nil
+}
+
+// WriteRecords writes the records to the given path in the requested format.
+// A leading ~ in the path is expanded to the user's home directory and the file is
+// given 0600 permissions because its contents are plaintext secrets, replacing any
+// existing file rather than keeping its permissions.
+func WriteRecords(path string, format Format, records []Record) error {
+	path = ExpandPath(path)
+
+	output, err := Encode(format, records)
+	if err != nil {
+		return err
+	}
+
+	err = fileio.WriteFileAtomic(path, output, fileio.FilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write export file %s: %v", path, err)
+	}
+	return nil
+}
+
+// Encode renders the records in the requested format without writing them anywhere
+func Encode(format Format, records []Record) ([]byte, error) {
+	var output []byte
+	var err error
+	switch format {
+	case FormatCSV:
+		output, err = encodeCSV(records)
+	case FormatJSON:
+		output, err = json.MarshalIndent(records, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s'", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export: %v", err)
+	}
+	return output, nil
+}
+
+// ExpandPath replaces a leading ~ with the user's home directory and cleans the result
+func ExpandPath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(path, "~"))
+	}
+	return filepath.Clean(path)
+}
+
+// DefaultPath returns the suggested export destination for the given format
+func DefaultPath(format Format) string {
+	return filepath.Join(os.Getenv("HOME"), fmt.Sprintf("password-export.%s", format))
+}
+
+// encodeCSV renders the records as CSV with a header row and RFC3339 timestamps. Custom fields,
+// which hold the details of cards, notes and other entry types, are written as a JSON array.
+func encodeCSV(records []Record) ([]byte, error) {
+	var builder strings.Builder
+	writer := csv.NewWriter(&builder)
+
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		fields := ""
+		if len(record.Fields) > 0 {
+			encoded, err := json.Marshal(record.Fields)
+			if err != nil {
+				return nil, err
+			}
+			fields = string(encoded)
+		}
+		err = writer.Write([]string{
+			record.SiteName,
+			record.Username,
+			record.Email,
+			record.URL,
+			record.Password,
+			record.OTP,
+			fields,
+			record.CreatedAt.Format(time.RFC3339),
+			record.UpdatedAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return []byte(builder.String()), nil
+}
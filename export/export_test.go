@@ -0,0 +1,191 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+var testRecords = []Record{
+	{
+		SiteName:  "github",
+		Username:  "octocat",
+		Email:     "octo@example.com",
+		URL:       "https://github.com",
+		Password:  `pa,ss"word`,
+		OTP:       "JBSWY3DPEHPK3PXP",
+		Tags:      []string{"work"},
+		Favourite: true,
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+	},
+	{
+		SiteName: "Secure note",
+		Type:     encryption.EntryNote,
+		Fields:   []encryption.Field{{Name: "Note", Value: "line one\nline two", Type: encryption.FieldNotes}},
+	},
+}
+
+func TestEncodeCSV(t *testing.T) {
+	output, err := Encode(FormatCSV, testRecords)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(string(output))).ReadAll()
+	if err != nil {
+		t.Fatalf("reading the CSV back: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want a header and 2 records", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], csvHeader) {
+		t.Errorf("header = %q, want %q", rows[0], csvHeader)
+	}
+	want := []string{"github", "octocat", "octo@example.com", "https://github.com", `pa,ss"word`, "JBSWY3DPEHPK3PXP", "", "2024-01-02T03:04:05Z", "2024-02-03T04:05:06Z"}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("record = %q, want %q", rows[1], want)
+	}
+
+	// Custom fields, such as the text of a note, survive as JSON
+	var fields []encryption.Field
+	err = json.Unmarshal([]byte(rows[2][slices.Index(csvHeader, "fields")]), &fields)
+	if err != nil {
+		t.Fatalf("reading the fields column back: %v", err)
+	}
+	if !reflect.DeepEqual(fields, testRecords[1].Fields) {
+		t.Errorf("fields = %+v, want %+v", fields, testRecords[1].Fields)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	output, err := Encode(FormatJSON, testRecords)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var decoded []Record
+	err = json.Unmarshal(output, &decoded)
+	if err != nil {
+		t.Fatalf("reading the JSON back: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, testRecords) {
+		t.Errorf("records changed in a round trip:\ngot  %+v\nwant %+v", decoded, testRecords)
+	}
+}
+
+func TestEncodeUnsupportedFormat(t *testing.T) {
+	_, err := Encode(Format("xml"), testRecords)
+	if err == nil {
+		t.Fatal("Encode accepted an unsupported format")
+	}
+}
+
+func TestWriteRecordsRestrictsExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	err := os.WriteFile(path, []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteRecords(path, FormatJSON, testRecords)
+	if err != nil {
+		t.Fatalf("WriteRecords: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("export file has permissions %o, want 600", perm)
+	}
+	contents, _ := os.ReadFile(path)
+	if !strings.Contains(string(contents), "octocat") {
+		t.Errorf("export file was not replaced: %q", contents)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", "/home/alice"},
+		{"~/exports/out.csv", "/home/alice/exports/out.csv"},
+		{"  /tmp/../tmp/out.csv ", "/tmp/out.csv"},
+		{"~bob/out.csv", "~bob/out.csv"},
+	}
+	for _, test := range tests {
+		if got := ExpandPath(test.path); got != test.want {
+			t.Errorf("ExpandPath(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestCollectRecordsLockedFolder(t *testing.T) {
+	pf, err := fileio.OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = encryption.SaveCryptoConfig(pf.FolderLocation, encryption.DefaultCryptoConfig("rfc4880"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ef := encryption.NewEncryption(pf)
+	err = ef.InitStore("master password")
+	if err != nil {
+		t.Fatalf("InitStore: %v", err)
+	}
+	err = os.Mkdir(filepath.Join(pf.FolderLocation, "work"), fileio.DirPerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ef.SetFolderPassphrase("work", "folder passphrase")
+	if err != nil {
+		t.Fatalf("SetFolderPassphrase: %v", err)
+	}
+	for _, name := range []string{"github", "work/aws", "work/gcp"} {
+		err = ef.EncryptPasswordAndWriteToFile(name, encryption.Data{Password: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A new session has the folder locked until its passphrase is entered
+	ef = encryption.NewEncryption(pf)
+	err = ef.Unlock("master password")
+	if err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	locked, err := LockedFolders(pf, ef)
+	if err != nil || !reflect.DeepEqual(locked, []string{"work"}) {
+		t.Fatalf("LockedFolders = %v, %v, want work", locked, err)
+	}
+	if _, err := CollectRecords(pf, ef); !errors.Is(err, encryption.ErrFolderLocked) {
+		t.Errorf("CollectRecords with a locked folder = %v, want ErrFolderLocked", err)
+	}
+
+	err = ef.UnlockFolder("work", "folder passphrase")
+	if err != nil {
+		t.Fatalf("UnlockFolder: %v", err)
+	}
+	if locked, err := LockedFolders(pf, ef); err != nil || len(locked) != 0 {
+		t.Errorf("LockedFolders after unlocking = %v, %v", locked, err)
+	}
+	records, err := CollectRecords(pf, ef)
+	if err != nil || len(records) != 3 {
+		t.Errorf("CollectRecords = %d records, %v, want all 3", len(records), err)
+	}
+}
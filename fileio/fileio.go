@@ -5,33 +5,64 @@ package fileio
 
 import (
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultFolderName is the name of the password store directory in the user's home directory
+const DefaultFolderName = ".password-manager-store"
+
+// Permissions of everything in the store: only the owner may list directories or read files
+const (
+	DirPerm  os.FileMode = 0700
+	FilePerm os.FileMode = 0600
 )
 
 // PasswordFolder represents the password store directory and its current state.
 // It tracks the store location, directory contents, initialization status, and master password.
 type PasswordFolder struct {
-	FolderLocation string        // Absolute path to ~/.password-manager-store/
+	FolderLocation string        // Absolute path of the store, ~/.password-manager-store/ by default
 	Dirs           []os.DirEntry // Contents of the password store directory
 	InitCheck      bool          // Whether the store has been properly initialized
 	Password       string        // The master password (stored in memory only)
+	ReadOnly       bool          // Whether writes are refused because another instance holds the lock
+	lockHeld       bool          // Whether this instance holds the store's lock file
+}
+
+// DefaultLocation returns the path of the default password store, ~/.password-manager-store
+func DefaultLocation() string {
+	return fmt.Sprintf("%s/%s", os.Getenv("HOME"), DefaultFolderName)
 }
 
-// InitPasswordFolder creates or accesses the password store directory and initializes
+// InitPasswordFolder creates or accesses the default password store directory and initializes
 // the PasswordFolder struct. It creates ~/.password-manager-store/ with secure permissions
 // and sets up the .checker subdirectory for validation files.
 //
 // Returns a fully initialized PasswordFolder or terminates the program on fatal errors.
 func InitPasswordFolder() *PasswordFolder {
+	passwordFolder, err := OpenPasswordFolder(DefaultLocation())
+	if err != nil {
+		log.Fatal("Could not Open or Create Password Store File: ", err)
+	}
+	return passwordFolder
+}
+
+// OpenPasswordFolder creates or accesses the password store at location, which may be any
+// directory (a named vault, a team store or a test fixture). Missing directories are created
+// with the same permissions as the default store.
+func OpenPasswordFolder(location string) (*PasswordFolder, error) {
 	passwordFolder := &PasswordFolder{
 		InitCheck: true,
 	}
-	err := getDir(passwordFolder)
+	err := getDir(passwordFolder, location)
 	if err != nil {
-		log.Fatal("Could not Open or Create Password Store File: ", err)
+		return nil, err
 	}
-	return passwordFolder
+	return passwordFolder, nil
 }
 
 // FileExists checks if a file exists at the given path.
@@ -44,25 +75,29 @@ func FileExists(fileName string) bool {
 	return true
 }
 
-func getDir(passwordFolder *PasswordFolder) (error) {
-	passwordEncFolder := fmt.Sprintf("%s/.password-manager-store", os.Getenv("HOME"))
+func getDir(passwordFolder *PasswordFolder, passwordEncFolder string) (error) {
 	dirs, err := os.ReadDir(passwordEncFolder)
 	if os.IsNotExist(err) {
-		log.Println(".password-manager-store not found\nCreating new encrypted passwords folder")
-		os.Mkdir(passwordEncFolder, 0750)
+		log.Printf("%s not found\nCreating new encrypted passwords folder", passwordEncFolder)
+		err = os.MkdirAll(passwordEncFolder, DirPerm)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %v", passwordEncFolder, err)
+		}
 		dirs, err = os.ReadDir(passwordEncFolder)
 	} 
 	if err != nil {
-		log.Fatal("There was an error opening the file\n", err)
-		return err
+		return fmt.Errorf("failed to open %s: %v", passwordEncFolder, err)
 	}
 
 	_, err = os.ReadDir(fmt.Sprintf("%s/.checker", passwordEncFolder)) 
 	if os.IsNotExist(err) {
 		log.Println("Initialising Checker")
-		os.Mkdir(fmt.Sprintf("%s/.checker", passwordEncFolder), 0750)
+		err = os.Mkdir(fmt.Sprintf("%s/.checker", passwordEncFolder), DirPerm)
+		if err != nil {
+			return fmt.Errorf("failed to create %s/.checker: %v", passwordEncFolder, err)
+		}
 	} else if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if !FileExists(fmt.Sprintf("%s/.checker/init.gpg", passwordEncFolder)) {
@@ -76,8 +111,22 @@ func getDir(passwordFolder *PasswordFolder) (error) {
 }
 
 
+// WriteToFile atomically replaces the store file fileName (without .gpg) with input, readable
+// by the owner only. A crash part way through leaves the previous contents in place.
 func (pf *PasswordFolder) WriteToFile (fileName string, input []byte) error {
-	err := os.WriteFile(fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, fileName), input, 0666)
+	err := pf.CheckWritable()
+	if err != nil {
+		return err
+	}
+
+	// Entries may live in sub-folders, which are created on first use
+	filePath := fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, fileName)
+	err = os.MkdirAll(filepath.Dir(filePath), DirPerm)
+	if err != nil {
+		log.Printf("ERROR: Creating folder: %s", err)
+		return err
+	}
+	err = WriteFileAtomic(filePath, input, FilePerm)
 	if err != nil {
 		log.Printf("ERROR: Writing to file: %s", err)
 		return err
@@ -85,6 +134,55 @@ func (pf *PasswordFolder) WriteToFile (fileName string, input []byte) error {
 	return nil
 }
 
+// WriteFileAtomic writes data to path so that readers only ever see the old or the new
+// contents: the data goes to a temporary file in the same directory, is synced to disk and
+// is then renamed over path. The file is given perm regardless of the umask.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+	renamed := false
+	defer func() {
+		// Never leave a partial file behind
+		if !renamed {
+			temp.Close()
+			os.Remove(tempPath)
+		}
+	}()
+
+	err = temp.Chmod(perm)
+	if err != nil {
+		return err
+	}
+	_, err = temp.Write(data)
+	if err != nil {
+		return err
+	}
+	err = temp.Sync()
+	if err != nil {
+		return err
+	}
+	err = temp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return err
+	}
+	renamed = true
+
+	// Persist the rename itself; not every platform can sync a directory, so this is best effort
+	if dirFile, err := os.Open(dir); err == nil {
+		dirFile.Sync()
+		dirFile.Close()
+	}
+	return nil
+}
+
 func (pf *PasswordFolder) ReadFromFile (fileName string) ([]byte, error) {
 	data, err := os.ReadFile(fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, fileName))
 	if err != nil {
@@ -108,6 +206,10 @@ func (pf *PasswordFolder) RefreshDirectoryListing() error {
 // The filename should not include the .gpg extension as it will be added automatically.
 // Returns an error if the file doesn't exist or if deletion fails.
 func (pf *PasswordFolder) DeleteFile(fileName string) error {
+	err := pf.CheckWritable()
+	if err != nil {
+		return err
+	}
 	filePath := fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, fileName)
 	
 	// Check if file exists before attempting deletion
@@ -116,7 +218,7 @@ func (pf *PasswordFolder) DeleteFile(fileName string) error {
 	}
 	
 	// Attempt to delete the file
-	err := os.Remove(filePath)
+	err = os.Remove(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to delete password file '%s.gpg': %v", fileName, err)
 	}
@@ -129,3 +231,79 @@ func (pf *PasswordFolder) DeleteFile(fileName string) error {
 
 
 
+
+// ListEntryFilenames returns the store-relative names (without the .gpg extension) of every
+// password entry, including those in sub-folders, e.g. "work/aws/prod_20240101_120000".
+// Hidden files and directories such as .checker are skipped.
+func (pf *PasswordFolder) ListEntryFilenames() ([]string, error) {
+	err := pf.RefreshDirectoryListing()
+	if err != nil {
+		return nil, err
+	}
+
+	var filenames []string
+	err = pf.walk(func(relative string, dirEntry fs.DirEntry) {
+		if !dirEntry.IsDir() && strings.HasSuffix(relative, ".gpg") {
+			filenames = append(filenames, strings.TrimSuffix(relative, ".gpg"))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filenames, nil
+}
+
+// ListFolders returns the store-relative path of every sub-folder in sorted order.
+// Hidden directories such as .checker are skipped.
+func (pf *PasswordFolder) ListFolders() ([]string, error) {
+	var folders []string
+	err := pf.walk(func(relative string, dirEntry fs.DirEntry) {
+		if dirEntry.IsDir() {
+			folders = append(folders, relative)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// walk calls visit with the slash separated store-relative path of every visible file and
+// sub-folder in the store, skipping hidden names
+func (pf *PasswordFolder) walk(visit func(relative string, dirEntry fs.DirEntry)) error {
+	err := filepath.WalkDir(pf.FolderLocation, func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == pf.FolderLocation {
+			return nil
+		}
+		if strings.HasPrefix(dirEntry.Name(), ".") {
+			if dirEntry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relative, err := filepath.Rel(pf.FolderLocation, path)
+		if err != nil {
+			return err
+		}
+		visit(filepath.ToSlash(relative), dirEntry)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %v", pf.FolderLocation, err)
+	}
+	return nil
+}
+
+// UniqueFilename appends a counter to filename until it does not exist in the store.
+// Filenames are timestamped to the second, so entries saved in quick succession can collide.
+func (pf *PasswordFolder) UniqueFilename(filename string) string {
+	candidate := filename
+	for i := 2; FileExists(fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, candidate)); i++ {
+		candidate = fmt.Sprintf("%s_%d", filename, i)
+	}
+	return candidate
+}
@@ -0,0 +1,91 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tempFiles returns the leftover temporary files of WriteFileAtomic in dir
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestWriteFileAtomicReplacesPermissions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "entry.gpg")
+	err := os.WriteFile(path, []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteFileAtomic(path, []byte("new"), FilePerm)
+	if err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("file holds %q, %v, want the new contents", data, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != FilePerm {
+		t.Errorf("file has permissions %o, want %o", perm, FilePerm)
+	}
+	if leftover := tempFiles(t, dir); len(leftover) != 0 {
+		t.Errorf("temporary files left behind: %v", leftover)
+	}
+}
+
+func TestWriteFileAtomicCleansUpOnError(t *testing.T) {
+	dir := t.TempDir()
+	// A directory in the way makes the final rename fail
+	path := filepath.Join(dir, "entry.gpg")
+	err := os.MkdirAll(filepath.Join(path, "child"), DirPerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteFileAtomic(path, []byte("new"), FilePerm)
+	if err == nil {
+		t.Fatal("WriteFileAtomic replaced a directory")
+	}
+	if leftover := tempFiles(t, dir); len(leftover) != 0 {
+		t.Errorf("temporary files left behind: %v", leftover)
+	}
+
+	// A missing directory fails before anything is written
+	err = WriteFileAtomic(filepath.Join(dir, "missing", "entry.gpg"), []byte("new"), FilePerm)
+	if err == nil {
+		t.Error("WriteFileAtomic wrote into a missing directory")
+	}
+}
+
+func TestWriteToFileCreatesFolders(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.WriteToFile("work/cloud/aws", []byte("encrypted"))
+	if err != nil {
+		t.Fatalf("WriteToFile: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(pf.FolderLocation, "work", "cloud"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != DirPerm {
+		t.Errorf("folder has permissions %o, want %o", perm, DirPerm)
+	}
+	data, err := pf.ReadFromFile("work/cloud/aws")
+	if err != nil || string(data) != "encrypted" {
+		t.Errorf("ReadFromFile = %q, %v", data, err)
+	}
+}
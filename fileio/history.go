@@ -0,0 +1,97 @@
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryDir is the hidden folder at the top of the store that keeps earlier revisions of
+// entries. The revisions of an entry are in .history/<filename>/, one encrypted file each,
+// named after the time they were replaced.
+const HistoryDir = ".history"
+
+// MaxRevisions is how many earlier revisions are kept per entry; older ones are removed
+const MaxRevisions = 20
+
+// revisionTimeFormat names revision files so they sort by the time they were saved
+const revisionTimeFormat = "20060102T150405.000000000Z"
+
+// Revision is an earlier version of an entry, still encrypted exactly as it was stored
+type Revision struct {
+	Name    string    // Store-relative name without .gpg, readable with ReadFromFile
+	SavedAt time.Time // When the entry was overwritten and this revision kept
+}
+
+// historyName returns the store-relative folder holding the revisions of an entry
+func historyName(fileName string) string {
+	return path.Join(HistoryDir, fileName)
+}
+
+// SaveRevision keeps the current contents of the entry fileName as a revision before it is
+// overwritten. It does nothing if the entry does not exist yet. Only the newest
+// MaxRevisions revisions are kept.
+func (pf *PasswordFolder) SaveRevision(fileName string) error {
+	current, err := pf.ReadFromFile(fileName)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	name := path.Join(historyName(fileName), time.Now().UTC().Format(revisionTimeFormat))
+	err = pf.WriteToFile(name, current)
+	if err != nil {
+		return fmt.Errorf("failed to save revision of '%s.gpg': %v", fileName, err)
+	}
+
+	revisions, err := pf.ListRevisions(fileName)
+	if err != nil {
+		return err
+	}
+	for _, revision := range revisions[min(len(revisions), MaxRevisions):] {
+		err = pf.DeleteFile(revision.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListRevisions returns the kept revisions of the entry fileName, newest first
+func (pf *PasswordFolder) ListRevisions(fileName string) ([]Revision, error) {
+	dir := filepath.Join(pf.FolderLocation, filepath.FromSlash(historyName(fileName)))
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of '%s.gpg': %v", fileName, err)
+	}
+
+	var revisions []Revision
+	for _, file := range files {
+		// Sub-folders hold the history of entries in the folder of the same name
+		stamp, isRevision := strings.CutSuffix(file.Name(), ".gpg")
+		if file.IsDir() || !isRevision {
+			continue
+		}
+		savedAt, err := time.Parse(revisionTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, Revision{
+			Name:    path.Join(historyName(fileName), stamp),
+			SavedAt: savedAt.Local(),
+		})
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].SavedAt.After(revisions[j].SavedAt)
+	})
+	return revisions, nil
+}
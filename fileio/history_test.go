@@ -0,0 +1,115 @@
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeVersion replaces the entry with the given contents, keeping the old ones as a revision
+func writeVersion(t *testing.T, pf *PasswordFolder, fileName, contents string) {
+	t.Helper()
+	err := pf.SaveRevision(fileName)
+	if err != nil {
+		t.Fatalf("SaveRevision: %v", err)
+	}
+	err = pf.WriteToFile(fileName, []byte(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// revisionContents reads every revision of an entry, in the order ListRevisions returns them
+func revisionContents(t *testing.T, pf *PasswordFolder, fileName string) []string {
+	t.Helper()
+	revisions, err := pf.ListRevisions(fileName)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	var contents []string
+	for i, revision := range revisions {
+		if i > 0 && revision.SavedAt.After(revisions[i-1].SavedAt) {
+			t.Errorf("revision %s is newer than the one listed before it", revision.Name)
+		}
+		data, err := pf.ReadFromFile(revision.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents
+}
+
+func TestSaveRevisionOfNewEntry(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.SaveRevision("github")
+	if err != nil {
+		t.Fatalf("SaveRevision: %v", err)
+	}
+	if FileExists(filepath.Join(pf.FolderLocation, HistoryDir)) {
+		t.Error("a revision was saved for an entry that does not exist")
+	}
+}
+
+func TestSaveRevisionPrunes(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const versions = MaxRevisions + 5
+	for i := range versions {
+		writeVersion(t, pf, "github", fmt.Sprintf("v%d", i))
+	}
+
+	// The newest revisions are kept, newest first, and the oldest are removed
+	contents := revisionContents(t, pf, "github")
+	if len(contents) != MaxRevisions {
+		t.Fatalf("%d revisions kept, want %d", len(contents), MaxRevisions)
+	}
+	if newest, oldest := contents[0], contents[len(contents)-1]; newest != fmt.Sprintf("v%d", versions-2) || oldest != fmt.Sprintf("v%d", versions-MaxRevisions-1) {
+		t.Errorf("revisions run from %s to %s", newest, oldest)
+	}
+	current, err := pf.ReadFromFile("github")
+	if err != nil || string(current) != fmt.Sprintf("v%d", versions-1) {
+		t.Errorf("current version = %q, %v", current, err)
+	}
+}
+
+func TestListRevisionsSkipsFolderHistory(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An entry named work sits next to the folder work/ in the store and in the history
+	writeVersion(t, pf, "work", "work v1")
+	writeVersion(t, pf, "work", "work v2")
+	writeVersion(t, pf, "work/aws", "aws v1")
+	writeVersion(t, pf, "work/aws", "aws v2")
+	dir := filepath.Join(pf.FolderLocation, HistoryDir, "work")
+	for _, name := range []string{"notes.txt", "not-a-time.gpg"} {
+		err = os.WriteFile(filepath.Join(dir, name), []byte("stray"), FilePerm)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if contents := revisionContents(t, pf, "work"); len(contents) != 1 || contents[0] != "work v1" {
+		t.Errorf("revisions of work = %q, want only its own", contents)
+	}
+	if contents := revisionContents(t, pf, "work/aws"); len(contents) != 1 || contents[0] != "aws v1" {
+		t.Errorf("revisions of work/aws = %q", contents)
+	}
+	// Revisions are never listed as entries
+	filenames, err := pf.ListEntryFilenames()
+	if err != nil || !slices.Equal(filenames, []string{"work/aws", "work"}) {
+		t.Errorf("ListEntryFilenames = %v, %v, want only the current entries", filenames, err)
+	}
+	if revisions, err := pf.ListRevisions("never-edited"); err != nil || len(revisions) != 0 {
+		t.Errorf("ListRevisions of an entry without history = %v, %v", revisions, err)
+	}
+}
@@ -0,0 +1,146 @@
+package fileio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// LockFilename is the name of the advisory lock file at the top of the store. It is held by
+// the instance allowed to write to the store; every other instance opens it read-only.
+const LockFilename = ".lock"
+
+// lockSettleTime is how long a lock file may stay unreadable before it is treated as stale.
+// The holder writes its details straight after creating the file.
+const lockSettleTime = 10 * time.Second
+
+// ErrReadOnly is returned by writes to a store that was opened read-only
+var ErrReadOnly = errors.New("password store is open read-only while another instance holds its lock")
+
+// LockInfo describes the process holding a store's lock
+type LockInfo struct {
+	PID       int       `json:"pid"`        // Process ID of the holder
+	Host      string    `json:"host"`       // Hostname of the machine the holder runs on
+	CreatedAt time.Time `json:"created_at"` // When the lock was acquired
+}
+
+// LockedError is returned by AcquireLock when another running instance holds the lock
+type LockedError struct {
+	Holder LockInfo
+}
+
+func (e *LockedError) Error() string {
+	if e.Holder.PID == 0 {
+		return "password store is in use by another instance"
+	}
+	return fmt.Sprintf("password store is in use by another instance (PID %d on %s since %s)",
+		e.Holder.PID, e.Holder.Host, e.Holder.CreatedAt.Format("Jan 2 at 3:04 PM"))
+}
+
+// AcquireLock takes the store's lock so this instance may write to it. A lock left behind by
+// a process that is no longer running on this machine is removed first. Returns a
+// *LockedError if another instance holds the lock; the store should then be opened read-only.
+func (pf *PasswordFolder) AcquireLock() error {
+	path := filepath.Join(pf.FolderLocation, LockFilename)
+	hostname, _ := os.Hostname()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, FilePerm)
+		if err == nil {
+			info := LockInfo{PID: os.Getpid(), Host: hostname, CreatedAt: time.Now()}
+			err = json.NewEncoder(file).Encode(info)
+			if err == nil {
+				err = file.Sync()
+			}
+			file.Close()
+			if err != nil {
+				os.Remove(path)
+				return fmt.Errorf("failed to write %s: %v", path, err)
+			}
+			pf.lockHeld = true
+			pf.ReadOnly = false
+			return nil
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to create %s: %v", path, err)
+		}
+
+		holder, err := readLockInfo(path)
+		if err == nil && holder.Host == hostname && holder.PID == os.Getpid() {
+			// Already ours, e.g. after switching back to this vault
+			pf.lockHeld = true
+			pf.ReadOnly = false
+			return nil
+		}
+		if err == nil && !holder.stale(hostname) {
+			return &LockedError{Holder: holder}
+		}
+		if err != nil {
+			stat, statErr := os.Stat(path)
+			if statErr == nil && time.Since(stat.ModTime()) < lockSettleTime {
+				return &LockedError{}
+			}
+		}
+
+		// The holder is gone, clear its lock and try again
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale lock %s: %v", path, err)
+		}
+	}
+	return fmt.Errorf("could not acquire %s", path)
+}
+
+// ReleaseLock removes the store's lock if this instance holds it
+func (pf *PasswordFolder) ReleaseLock() error {
+	if !pf.lockHeld {
+		return nil
+	}
+	pf.lockHeld = false
+	err := os.Remove(filepath.Join(pf.FolderLocation, LockFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// CheckWritable returns ErrReadOnly if the store was opened read-only
+func (pf *PasswordFolder) CheckWritable() error {
+	if pf.ReadOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// readLockInfo reads the details of the process holding a lock file
+func readLockInfo(path string) (LockInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LockInfo{}, err
+	}
+	info := LockInfo{}
+	err = json.Unmarshal(data, &info)
+	if err != nil || info.PID <= 0 {
+		return LockInfo{}, fmt.Errorf("invalid lock file %s", path)
+	}
+	return info, nil
+}
+
+// stale reports whether the holder of a lock is no longer running. Only locks taken on this
+// machine can be checked; a lock from another host is always treated as live.
+func (info LockInfo) stale(hostname string) bool {
+	if info.Host != hostname {
+		return false
+	}
+	process, err := os.FindProcess(info.PID)
+	if err != nil {
+		return true
+	}
+	// Signal 0 checks that the process exists without affecting it
+	err = process.Signal(syscall.Signal(0))
+	return err != nil && !errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,153 @@
+package fileio
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeLock leaves a lock file in the store as if another process held it
+func writeLock(t *testing.T, pf *PasswordFolder, info LockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(pf.FolderLocation, LockFilename), data, FilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readLock returns the details in the store's lock file
+func readLock(t *testing.T, pf *PasswordFolder) LockInfo {
+	t.Helper()
+	info, err := readLockInfo(filepath.Join(pf.FolderLocation, LockFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+// exitedPID returns the process ID of a process that has finished
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	err := cmd.Run()
+	if err != nil {
+		t.Skipf("cannot start a process: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestAcquireReleaseLock(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.AcquireLock()
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	if info := readLock(t, pf); info.PID != os.Getpid() {
+		t.Errorf("lock held by PID %d, want %d", info.PID, os.Getpid())
+	}
+	// Taking our own lock again, e.g. after switching vaults, works
+	err = pf.AcquireLock()
+	if err != nil {
+		t.Fatalf("AcquireLock of a lock already held: %v", err)
+	}
+
+	err = pf.ReleaseLock()
+	if err != nil {
+		t.Fatalf("ReleaseLock: %v", err)
+	}
+	if FileExists(filepath.Join(pf.FolderLocation, LockFilename)) {
+		t.Error("ReleaseLock left the lock file behind")
+	}
+}
+
+func TestAcquireLockTakesOverStaleLock(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostname, _ := os.Hostname()
+	writeLock(t, pf, LockInfo{PID: exitedPID(t), Host: hostname, CreatedAt: time.Now()})
+
+	err = pf.AcquireLock()
+	if err != nil {
+		t.Fatalf("AcquireLock with a stale lock: %v", err)
+	}
+	if info := readLock(t, pf); info.PID != os.Getpid() {
+		t.Errorf("lock held by PID %d, want it taken over by %d", info.PID, os.Getpid())
+	}
+}
+
+func TestAcquireLockUnreadableLock(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(pf.FolderLocation, LockFilename)
+	err = os.WriteFile(path, nil, FilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A lock that was only just created may still be getting its details written
+	var locked *LockedError
+	if err := pf.AcquireLock(); !errors.As(err, &locked) {
+		t.Fatalf("AcquireLock with a new empty lock = %v, want LockedError", err)
+	}
+
+	old := time.Now().Add(-2 * lockSettleTime)
+	err = os.Chtimes(path, old, old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.AcquireLock()
+	if err != nil {
+		t.Fatalf("AcquireLock with an old empty lock: %v", err)
+	}
+}
+
+func TestAcquireLockHeldElsewhere(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostname, _ := os.Hostname()
+	holders := []LockInfo{
+		{PID: os.Getppid(), Host: hostname, CreatedAt: time.Now()},          // A running process here
+		{PID: exitedPID(t), Host: "another-machine", CreatedAt: time.Now()}, // Cannot be checked
+	}
+	for _, holder := range holders {
+		writeLock(t, pf, holder)
+
+		var locked *LockedError
+		err = pf.AcquireLock()
+		if !errors.As(err, &locked) || locked.Holder != readLock(t, pf) {
+			t.Fatalf("AcquireLock held by %+v = %v, want LockedError", holder, err)
+		}
+
+		// The store is then opened read-only and refuses writes
+		pf.ReadOnly = true
+		err = pf.WriteToFile("github", []byte("encrypted"))
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("WriteToFile on a read-only store = %v, want ErrReadOnly", err)
+		}
+		if FileExists(filepath.Join(pf.FolderLocation, "github.gpg")) {
+			t.Error("a read-only store was written to")
+		}
+		// Releasing a lock we never held leaves the holder's in place
+		err = pf.ReleaseLock()
+		if err != nil || !FileExists(filepath.Join(pf.FolderLocation, LockFilename)) {
+			t.Errorf("ReleaseLock removed another instance's lock: %v", err)
+		}
+	}
+}
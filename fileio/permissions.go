@@ -0,0 +1,55 @@
+package fileio
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// PermissionIssue is a file or directory in the store that other users can access
+type PermissionIssue struct {
+	Path string      // Absolute path of the file or directory
+	Mode os.FileMode // Current permission bits
+	Want os.FileMode // Permission bits it should have
+}
+
+// CheckPermissions walks the whole store, including hidden files such as .checker, and
+// returns every file or directory that grants any access to the group or other users
+func (pf *PasswordFolder) CheckPermissions() ([]PermissionIssue, error) {
+	var issues []PermissionIssue
+	err := filepath.WalkDir(pf.FolderLocation, func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !dirEntry.IsDir() && !dirEntry.Type().IsRegular() {
+			return nil // Leave symlinks and other special files alone
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+
+		// Only access by others matters, the owner's bits are left as they are (e.g. git hooks)
+		mode := info.Mode().Perm()
+		if mode&0077 != 0 {
+			issues = append(issues, PermissionIssue{Path: path, Mode: mode, Want: mode &^ 0077})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permissions of %s: %v", pf.FolderLocation, err)
+	}
+	return issues, nil
+}
+
+// FixPermissions restricts every file and directory in issues to its wanted permissions
+func FixPermissions(issues []PermissionIssue) error {
+	for _, issue := range issues {
+		err := os.Chmod(issue.Path, issue.Want)
+		if err != nil {
+			return fmt.Errorf("failed to change permissions of %s: %v", issue.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckAndFixPermissions(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := pf.FolderLocation
+	err = os.Chmod(location, DirPerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.WriteToFile("github", []byte("encrypted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.WriteToFile("work/aws", []byte("encrypted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := pf.CheckPermissions()
+	if err != nil || len(issues) != 0 {
+		t.Fatalf("CheckPermissions of a private store = %v, %v", issues, err)
+	}
+
+	// Loosen a file, a folder and a hidden file; owner bits such as execute are kept
+	loosened := map[string]os.FileMode{
+		filepath.Join(location, "github.gpg"):          0644,
+		filepath.Join(location, "work"):                0755,
+		filepath.Join(location, ".checker", "hook.sh"): 0750,
+	}
+	err = os.WriteFile(filepath.Join(location, ".checker", "hook.sh"), nil, FilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink(filepath.Join(location, "github.gpg"), filepath.Join(location, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, mode := range loosened {
+		err = os.Chmod(path, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues, err = pf.CheckPermissions()
+	if err != nil {
+		t.Fatalf("CheckPermissions: %v", err)
+	}
+	if len(issues) != len(loosened) {
+		t.Fatalf("CheckPermissions = %v, want %d issues", issues, len(loosened))
+	}
+	for _, issue := range issues {
+		mode, ok := loosened[issue.Path]
+		if !ok || issue.Mode != mode || issue.Want != mode&0700 {
+			t.Errorf("unexpected issue %+v", issue)
+		}
+	}
+
+	err = FixPermissions(issues)
+	if err != nil {
+		t.Fatalf("FixPermissions: %v", err)
+	}
+	for path, mode := range loosened {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != mode&0700 {
+			t.Errorf("%s has permissions %o after the fix, want %o", path, perm, mode&0700)
+		}
+	}
+	issues, err = pf.CheckPermissions()
+	if err != nil || len(issues) != 0 {
+		t.Errorf("CheckPermissions after the fix = %v, %v", issues, err)
+	}
+}
@@ -0,0 +1,194 @@
+package fileio
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrashDir is the hidden folder at the top of the store that deleted entries are moved to.
+// Each deletion gets its own folder, .trash/<time>/, named after when it happened and holding
+// the entry at its original path along with its history in .history/.
+const TrashDir = ".trash"
+
+// DefaultTrashRetention is how long deleted entries stay in the trash unless configured otherwise
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// TrashedEntry is a deleted entry waiting in the trash
+type TrashedEntry struct {
+	ID        string    // Name of the entry's folder in .trash
+	Filename  string    // Store-relative name the entry had, without .gpg
+	DeletedAt time.Time // When the entry was moved to the trash
+}
+
+// trashPath returns the filesystem path of a file inside a trashed entry's folder
+func (pf *PasswordFolder) trashPath(id string, name string) string {
+	return filepath.Join(pf.FolderLocation, TrashDir, id, filepath.FromSlash(name))
+}
+
+// TrashFile moves the entry fileName (without .gpg) and its history to the trash, where it
+// can be restored with RestoreFromTrash until it is purged
+func (pf *PasswordFolder) TrashFile(fileName string) error {
+	err := pf.CheckWritable()
+	if err != nil {
+		return err
+	}
+	source := filepath.Join(pf.FolderLocation, filepath.FromSlash(fileName)+".gpg")
+	if !FileExists(source) {
+		return fmt.Errorf("password file '%s.gpg' does not exist", fileName)
+	}
+
+	id := time.Now().UTC().Format(revisionTimeFormat)
+	err = moveFile(source, pf.trashPath(id, fileName+".gpg"))
+	if err != nil {
+		return fmt.Errorf("failed to move '%s.gpg' to the trash: %v", fileName, err)
+	}
+
+	revisions, err := pf.ListRevisions(fileName)
+	if err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		err = moveFile(filepath.Join(pf.FolderLocation, filepath.FromSlash(revision.Name)+".gpg"), pf.trashPath(id, revision.Name+".gpg"))
+		if err != nil {
+			return fmt.Errorf("failed to move the history of '%s.gpg' to the trash: %v", fileName, err)
+		}
+	}
+
+	// Leave the folder if it also holds the history of entries in a sub-folder
+	os.Remove(filepath.Join(pf.FolderLocation, filepath.FromSlash(historyName(fileName))))
+	return nil
+}
+
+// ListTrash returns the entries in the trash, most recently deleted first
+func (pf *PasswordFolder) ListTrash() ([]TrashedEntry, error) {
+	dirs, err := os.ReadDir(filepath.Join(pf.FolderLocation, TrashDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the trash: %v", err)
+	}
+
+	var trashed []TrashedEntry
+	for _, dir := range dirs {
+		deletedAt, err := time.Parse(revisionTimeFormat, dir.Name())
+		if !dir.IsDir() || err != nil {
+			continue
+		}
+		// The entry is the only visible file in its folder; its history is hidden in .history
+		item := &PasswordFolder{FolderLocation: filepath.Join(pf.FolderLocation, TrashDir, dir.Name())}
+		err = item.walk(func(relative string, dirEntry fs.DirEntry) {
+			if !dirEntry.IsDir() && strings.HasSuffix(relative, ".gpg") {
+				trashed = append(trashed, TrashedEntry{
+					ID:        dir.Name(),
+					Filename:  strings.TrimSuffix(relative, ".gpg"),
+					DeletedAt: deletedAt.Local(),
+				})
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.After(trashed[j].DeletedAt)
+	})
+	return trashed, nil
+}
+
+// RestoreFromTrash moves a trashed entry and its history back into the store. If another
+// entry has taken its name in the meantime, it is restored under a new one.
+// Returns the store-relative name it was restored as.
+func (pf *PasswordFolder) RestoreFromTrash(entry TrashedEntry) (string, error) {
+	err := pf.CheckWritable()
+	if err != nil {
+		return "", err
+	}
+	target := pf.UniqueFilename(entry.Filename)
+	err = moveFile(pf.trashPath(entry.ID, entry.Filename+".gpg"), filepath.Join(pf.FolderLocation, filepath.FromSlash(target)+".gpg"))
+	if err != nil {
+		return "", fmt.Errorf("failed to restore '%s.gpg': %v", entry.Filename, err)
+	}
+
+	historyDir := pf.trashPath(entry.ID, historyName(entry.Filename))
+	revisions, err := os.ReadDir(historyDir)
+	if err != nil && !os.IsNotExist(err) {
+		return target, err
+	}
+	for _, revision := range revisions {
+		name := path.Join(historyName(target), revision.Name())
+		err = moveFile(filepath.Join(historyDir, revision.Name()), filepath.Join(pf.FolderLocation, filepath.FromSlash(name)))
+		if err != nil {
+			return target, fmt.Errorf("failed to restore the history of '%s.gpg': %v", entry.Filename, err)
+		}
+	}
+	return target, os.RemoveAll(filepath.Join(pf.FolderLocation, TrashDir, entry.ID))
+}
+
+// TrashedFiles returns the store-relative names (without .gpg) of a trashed entry and its
+// revisions, for reading or re-encrypting them where they are
+func (pf *PasswordFolder) TrashedFiles(entry TrashedEntry) ([]string, error) {
+	names := []string{path.Join(TrashDir, entry.ID, entry.Filename)}
+	item := &PasswordFolder{FolderLocation: filepath.Join(pf.FolderLocation, TrashDir, entry.ID)}
+	revisions, err := item.ListRevisions(entry.Filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		names = append(names, path.Join(TrashDir, entry.ID, revision.Name))
+	}
+	return names, nil
+}
+
+// PurgeFromTrash permanently deletes a trashed entry and its history
+func (pf *PasswordFolder) PurgeFromTrash(entry TrashedEntry) error {
+	err := pf.CheckWritable()
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(filepath.Join(pf.FolderLocation, TrashDir, entry.ID))
+	if err != nil {
+		return fmt.Errorf("failed to purge '%s.gpg': %v", entry.Filename, err)
+	}
+	return nil
+}
+
+// PurgeExpiredTrash permanently deletes entries that have been in the trash for longer than
+// retention. A retention of 0 keeps them until they are purged by hand.
+// Returns how many entries were purged.
+func (pf *PasswordFolder) PurgeExpiredTrash(retention time.Duration) (int, error) {
+	if retention <= 0 || pf.ReadOnly {
+		return 0, nil
+	}
+	trashed, err := pf.ListTrash()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, entry := range trashed {
+		if time.Since(entry.DeletedAt) < retention {
+			continue
+		}
+		err = pf.PurgeFromTrash(entry)
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// moveFile renames source to target, creating the folders target needs
+func moveFile(source, target string) error {
+	err := os.MkdirAll(filepath.Dir(target), DirPerm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(source, target)
+}
@@ -0,0 +1,140 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTrashAndRestore(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, contents := range []string{"v1", "v2", "v3"} {
+		writeVersion(t, pf, "work/github", contents)
+	}
+
+	err = pf.TrashFile("work/github")
+	if err != nil {
+		t.Fatalf("TrashFile: %v", err)
+	}
+	if FileExists(filepath.Join(pf.FolderLocation, "work", "github.gpg")) {
+		t.Error("the entry is still in the store")
+	}
+	if revisions, _ := pf.ListRevisions("work/github"); len(revisions) != 0 {
+		t.Errorf("%d revisions left in the store, want them moved to the trash", len(revisions))
+	}
+	if err := pf.TrashFile("work/github"); err == nil {
+		t.Error("TrashFile accepted an entry that does not exist")
+	}
+
+	trashed, err := pf.ListTrash()
+	if err != nil || len(trashed) != 1 || trashed[0].Filename != "work/github" {
+		t.Fatalf("ListTrash = %+v, %v, want work/github", trashed, err)
+	}
+	names, err := pf.TrashedFiles(trashed[0])
+	if err != nil || len(names) != 3 {
+		t.Errorf("TrashedFiles = %v, %v, want the entry and 2 revisions", names, err)
+	}
+
+	// A new entry took the name while the old one was in the trash
+	err = pf.WriteToFile("work/github", []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := pf.RestoreFromTrash(trashed[0])
+	if err != nil {
+		t.Fatalf("RestoreFromTrash: %v", err)
+	}
+	if restored != "work/github_2" {
+		t.Errorf("restored as %q, want work/github_2", restored)
+	}
+	if data, err := pf.ReadFromFile(restored); err != nil || string(data) != "v3" {
+		t.Errorf("restored entry = %q, %v, want v3", data, err)
+	}
+	if data, err := pf.ReadFromFile("work/github"); err != nil || string(data) != "new" {
+		t.Errorf("the new entry changed to %q, %v", data, err)
+	}
+	if contents := revisionContents(t, pf, restored); !slices.Equal(contents, []string{"v2", "v1"}) {
+		t.Errorf("history of the restored entry = %q, want v2 and v1", contents)
+	}
+	if revisions, _ := pf.ListRevisions("work/github"); len(revisions) != 0 {
+		t.Errorf("the new entry was given %d revisions of the restored one", len(revisions))
+	}
+	if trashed, err := pf.ListTrash(); err != nil || len(trashed) != 0 {
+		t.Errorf("ListTrash after restoring = %+v, %v", trashed, err)
+	}
+}
+
+func TestTrashKeepsFolderHistory(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"work", "work/aws"} {
+		writeVersion(t, pf, name, "v1")
+		writeVersion(t, pf, name, "v2")
+	}
+
+	err = pf.TrashFile("work")
+	if err != nil {
+		t.Fatalf("TrashFile: %v", err)
+	}
+	if contents := revisionContents(t, pf, "work/aws"); !slices.Equal(contents, []string{"v1"}) {
+		t.Errorf("history of work/aws = %q, want it left in place", contents)
+	}
+	trashed, err := pf.ListTrash()
+	if err != nil || len(trashed) != 1 {
+		t.Fatalf("ListTrash = %+v, %v", trashed, err)
+	}
+	if names, err := pf.TrashedFiles(trashed[0]); err != nil || len(names) != 2 {
+		t.Errorf("TrashedFiles = %v, %v, want work and its revision only", names, err)
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"old", "recent"} {
+		writeVersion(t, pf, name, name)
+		err = pf.TrashFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Back-date the deletion of old by renaming its folder in the trash
+	trashed, err := pf.ListTrash()
+	if err != nil || len(trashed) != 2 {
+		t.Fatalf("ListTrash = %+v, %v", trashed, err)
+	}
+	old := trashed[slices.IndexFunc(trashed, func(entry TrashedEntry) bool { return entry.Filename == "old" })]
+	deletedAt := time.Now().Add(-40 * 24 * time.Hour).UTC().Format(revisionTimeFormat)
+	err = os.Rename(filepath.Join(pf.FolderLocation, TrashDir, old.ID), filepath.Join(pf.FolderLocation, TrashDir, deletedAt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is purged with retention turned off or from a read-only store
+	if purged, err := pf.PurgeExpiredTrash(0); err != nil || purged != 0 {
+		t.Errorf("PurgeExpiredTrash(0) = %d, %v, want nothing purged", purged, err)
+	}
+	pf.ReadOnly = true
+	if purged, err := pf.PurgeExpiredTrash(DefaultTrashRetention); err != nil || purged != 0 {
+		t.Errorf("PurgeExpiredTrash on a read-only store = %d, %v, want nothing purged", purged, err)
+	}
+	pf.ReadOnly = false
+
+	purged, err := pf.PurgeExpiredTrash(DefaultTrashRetention)
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeExpiredTrash = %d, %v, want 1", purged, err)
+	}
+	trashed, err = pf.ListTrash()
+	if err != nil || len(trashed) != 1 || trashed[0].Filename != "recent" {
+		t.Errorf("ListTrash after purging = %+v, %v, want only recent", trashed, err)
+	}
+}
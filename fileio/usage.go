@@ -0,0 +1,65 @@
+package fileio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// UsageFilename is the store-relative path of the record of when each entry was last opened.
+// It only orders the password list, so like the lock file it is local to one copy of the store.
+const UsageFilename = ".checker/usage.json"
+
+// LoadUsage returns when each entry (filename without .gpg) was last opened.
+// A missing file means no entry has been opened yet.
+func (pf *PasswordFolder) LoadUsage() (map[string]time.Time, error) {
+	path := filepath.Join(pf.FolderLocation, UsageFilename)
+	usage := make(map[string]time.Time)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	err = json.Unmarshal(data, &usage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return usage, nil
+}
+
+// RecordUsage notes that the entry fileName (without .gpg) was opened now, forgetting entries
+// that no longer exist so the record does not grow forever. Nothing is recorded while the
+// store is open read-only, as another instance may be writing the same file.
+func (pf *PasswordFolder) RecordUsage(fileName string) error {
+	if pf.ReadOnly {
+		return nil
+	}
+	usage, err := pf.LoadUsage()
+	if err != nil {
+		return err
+	}
+	// Entries deleted, purged or renamed since they were last opened
+	for name := range usage {
+		if !FileExists(fmt.Sprintf("%s/%s.gpg", pf.FolderLocation, name)) {
+			delete(usage, name)
+		}
+	}
+	usage[fileName] = time.Now()
+
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode entry usage: %v", err)
+	}
+	path := filepath.Join(pf.FolderLocation, UsageFilename)
+	err = WriteFileAtomic(path, append(data, '\n'), FilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
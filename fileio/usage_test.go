@@ -0,0 +1,81 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordUsage(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"github", "work/aws"} {
+		err = pf.WriteToFile(name, []byte("encrypted"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := pf.LoadUsage()
+	if err != nil || len(usage) != 0 {
+		t.Fatalf("LoadUsage of a new store = %v, %v, want nothing opened", usage, err)
+	}
+	for _, name := range []string{"github", "work/aws"} {
+		err = pf.RecordUsage(name)
+		if err != nil {
+			t.Fatalf("RecordUsage: %v", err)
+		}
+	}
+	usage, err = pf.LoadUsage()
+	if err != nil || usage["github"].IsZero() || usage["work/aws"].IsZero() {
+		t.Fatalf("LoadUsage = %v, %v, want both entries", usage, err)
+	}
+
+	// An entry deleted since it was opened is forgotten the next time the record is saved
+	err = os.Remove(filepath.Join(pf.FolderLocation, "work", "aws.gpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pf.RecordUsage("github")
+	if err != nil {
+		t.Fatalf("RecordUsage: %v", err)
+	}
+	usage, err = pf.LoadUsage()
+	if err != nil {
+		t.Fatalf("LoadUsage: %v", err)
+	}
+	if _, ok := usage["work/aws"]; ok || len(usage) != 1 {
+		t.Errorf("LoadUsage = %v, want only github", usage)
+	}
+}
+
+func TestRecordUsageReadOnly(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pf.ReadOnly = true
+	err = pf.RecordUsage("github")
+	if err != nil {
+		t.Fatalf("RecordUsage: %v", err)
+	}
+	if FileExists(filepath.Join(pf.FolderLocation, UsageFilename)) {
+		t.Error("RecordUsage wrote to a read-only store")
+	}
+}
+
+func TestLoadUsageRejectsMalformedFile(t *testing.T) {
+	pf, err := OpenPasswordFolder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(pf.FolderLocation, UsageFilename), []byte("["), FilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pf.LoadUsage(); err == nil {
+		t.Error("LoadUsage accepted a malformed file")
+	}
+}
@@ -0,0 +1,377 @@
+// Package gitstore keeps the password store in a git repository. Every change made to the
+// store is committed, so its history can be inspected with git, and Sync pulls from and pushes
+// to a remote so the store can be shared between machines. Entries are committed exactly as
+// they are on disk, encrypted. The git command line is used, so git must be installed.
+package gitstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/throttle"
+)
+
+// RemoteName is the git remote the store is synced with
+const RemoteName = "origin"
+
+// ignored lists the files that are local to one copy of the store and never committed:
+// the lock file, the failed login counter, when entries were last opened and temporary files
+// left by an interrupted write
+var ignored = []string{"/" + fileio.LockFilename, "/" + throttle.Filename, "/" + fileio.UsageFilename, ".*.tmp-*"}
+
+// Fallback identity for commits when git has no user configured, so the store works out of the box
+const (
+	fallbackName  = "Password Manager"
+	fallbackEmail = "password-manager@localhost"
+)
+
+// Side is a version of a file that conflicted while syncing
+type Side int
+
+const (
+	Ours   Side = iota // The version in this copy of the store
+	Theirs             // The version pulled from the remote
+)
+
+// String returns the display name of the side
+func (s Side) String() string {
+	if s == Theirs {
+		return "Theirs"
+	}
+	return "Ours"
+}
+
+// stage returns the index stage git keeps the side's version in during a merge
+func (s Side) stage() string {
+	if s == Theirs {
+		return "3"
+	}
+	return "2"
+}
+
+// Repo is the git repository of a password store
+type Repo struct {
+	dir         string   // Absolute path of the store, the repository's working tree
+	identityEnv []string // Fallback identity added to git's environment, looked up on first use
+	checked     bool     // Whether git's configured identity has been looked up
+}
+
+// Open returns the repository of the store at location. The store does not need to be a
+// repository yet; see Enabled and Init.
+func Open(location string) *Repo {
+	return &Repo{dir: location}
+}
+
+// Enabled reports whether the store is a git repository
+func (r *Repo) Enabled() bool {
+	return fileio.FileExists(filepath.Join(r.dir, ".git"))
+}
+
+// Init turns the store into a git repository and commits its current contents. The
+// repository is only accessible to the owner, like the rest of the store.
+func (r *Repo) Init() error {
+	_, err := r.git("init", "--quiet", "--shared=0600")
+	if err != nil {
+		return err
+	}
+	return r.Commit("Start history of password store")
+}
+
+// Commit records every change in the store with message. It does nothing when the store is
+// not a repository or nothing has changed.
+func (r *Repo) Commit(message string) error {
+	if !r.Enabled() {
+		return nil
+	}
+	if r.Merging() {
+		return fmt.Errorf("a sync with conflicts is unfinished, run Sync again to resolve it")
+	}
+	err := r.ignoreLocalFiles()
+	if err != nil {
+		return err
+	}
+	_, err = r.git("add", "--all")
+	if err != nil {
+		return err
+	}
+	status, err := r.git("status", "--porcelain")
+	if err != nil || status == "" {
+		return err
+	}
+	_, err = r.git("commit", "--quiet", "--no-verify", "-m", message)
+	if err != nil {
+		return err
+	}
+	return r.restrict()
+}
+
+// Remote returns the URL of the remote the store is synced with, empty if none is set
+func (r *Repo) Remote() (string, error) {
+	remotes, err := r.git("remote")
+	if err != nil {
+		return "", err
+	}
+	for _, name := range strings.Fields(remotes) {
+		if name == RemoteName {
+			return r.git("remote", "get-url", RemoteName)
+		}
+	}
+	return "", nil
+}
+
+// SetRemote sets the URL of the remote the store is synced with
+func (r *Repo) SetRemote(url string) error {
+	current, err := r.Remote()
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		_, err = r.git("remote", "add", RemoteName, url)
+	} else {
+		_, err = r.git("remote", "set-url", RemoteName, url)
+	}
+	return err
+}
+
+// Pull merges the changes on the remote into the store, committing local changes first.
+// Returns the store-relative paths of files changed on both sides; the merge is then left
+// in progress until each is settled with Resolve and FinishMerge, or AbortMerge is called.
+func (r *Repo) Pull() ([]string, error) {
+	// Carry on with a merge left unfinished by an earlier sync
+	if r.Merging() {
+		conflicts, err := r.conflicts()
+		if err != nil || len(conflicts) > 0 {
+			return conflicts, err
+		}
+		return nil, r.FinishMerge()
+	}
+
+	err := r.Commit("Save local changes before sync")
+	if err != nil {
+		return nil, err
+	}
+	branch, err := r.branch()
+	if err != nil {
+		return nil, err
+	}
+
+	// A new remote has nothing to pull until the first push
+	heads, err := r.git("ls-remote", "--heads", RemoteName, branch)
+	if err != nil || heads == "" {
+		return nil, err
+	}
+	_, err = r.git("fetch", "--quiet", RemoteName, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	_, mergeErr := r.git("merge", "--no-edit", "--allow-unrelated-histories", "FETCH_HEAD")
+	err = r.restrict()
+	if err != nil {
+		return nil, err
+	}
+	if mergeErr == nil {
+		return nil, nil
+	}
+	conflicts, err := r.conflicts()
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return nil, mergeErr
+	}
+	return conflicts, nil
+}
+
+// Push sends the store's commits to the remote
+func (r *Repo) Push() error {
+	branch, err := r.branch()
+	if err != nil {
+		return err
+	}
+	_, err = r.git("push", "--quiet", "--set-upstream", RemoteName, branch)
+	return err
+}
+
+// Version returns one side of a conflicted file, or false if that side deleted it
+func (r *Repo) Version(path string, side Side) ([]byte, bool, error) {
+	exists, err := r.hasStage(path, side)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	cmd := r.command("show", ":"+side.stage()+":"+path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, false, fmt.Errorf("git show: %s", strings.TrimSpace(stderr.String()))
+	}
+	return data, true, nil
+}
+
+// Resolve settles a conflicted file by keeping one side, deleting the file if that side did
+func (r *Repo) Resolve(path string, side Side) error {
+	exists, err := r.hasStage(path, side)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err = r.git("rm", "--quiet", "--", path)
+		return err
+	}
+	_, err = r.git("checkout", "--"+strings.ToLower(side.String()), "--", path)
+	if err != nil {
+		return err
+	}
+	_, err = r.git("add", "--", path)
+	if err != nil {
+		return err
+	}
+	return r.restrict()
+}
+
+// FinishMerge commits a merge once every conflict has been resolved
+func (r *Repo) FinishMerge() error {
+	_, err := r.git("commit", "--quiet", "--no-verify", "--no-edit")
+	if err != nil {
+		return err
+	}
+	return r.restrict()
+}
+
+// Merging reports whether a merge with conflicts is in progress
+func (r *Repo) Merging() bool {
+	return fileio.FileExists(filepath.Join(r.dir, ".git", "MERGE_HEAD"))
+}
+
+// AbortMerge cancels a merge with conflicts and puts the store back as it was before Pull
+func (r *Repo) AbortMerge() error {
+	_, err := r.git("merge", "--abort")
+	if err != nil {
+		return err
+	}
+	return r.restrict()
+}
+
+// ignoreLocalFiles adds the files that are local to one copy of the store to its .gitignore,
+// including any added by later versions to a store that was already a repository
+func (r *Repo) ignoreLocalFiles() error {
+	ignorePath := filepath.Join(r.dir, ".gitignore")
+	content, err := os.ReadFile(ignorePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %v", ignorePath, err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	var missing []string
+	for _, pattern := range ignored {
+		if !slices.Contains(lines, pattern) {
+			missing = append(missing, pattern)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	text := string(content)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += strings.Join(missing, "\n") + "\n"
+	return fileio.WriteFileAtomic(ignorePath, []byte(text), fileio.FilePerm)
+}
+
+// restrict takes away access by other users from the files git has written. git creates
+// entries it checks out and some of its own files with the default permissions.
+func (r *Repo) restrict() error {
+	store := &fileio.PasswordFolder{FolderLocation: r.dir}
+	issues, err := store.CheckPermissions()
+	if err != nil {
+		return err
+	}
+	return fileio.FixPermissions(issues)
+}
+
+// conflicts returns the files of the merge in progress that still need resolving
+func (r *Repo) conflicts() ([]string, error) {
+	// -z lists paths unquoted, separated by NUL
+	output, err := r.git("diff", "--name-only", "-z", "--diff-filter=U")
+	output = strings.Trim(output, "\x00")
+	if err != nil || output == "" {
+		return nil, err
+	}
+	return strings.Split(output, "\x00"), nil
+}
+
+// hasStage reports whether a conflicted file has a version on the given side
+func (r *Repo) hasStage(path string, side Side) (bool, error) {
+	stages, err := r.git("ls-files", "--unmerged", "--", path)
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(stages, "\n") {
+		// Each line is "<mode> <object> <stage>\t<path>"
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[2] == side.stage() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// branch returns the name of the store's current branch
+func (r *Repo) branch() (string, error) {
+	branch, err := r.git("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("the store is not on a branch: %v", err)
+	}
+	return branch, nil
+}
+
+// git runs a git command in the store and returns its trimmed output
+func (r *Repo) git(args ...string) (string, error) {
+	cmd := r.command(args...)
+	output, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(output))
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return text, fmt.Errorf("git %s: %s", args[0], text)
+	}
+	if err != nil {
+		return text, fmt.Errorf("could not run git: %v", err)
+	}
+	return text, nil
+}
+
+// command prepares a git command in the store, supplying an identity when git has none
+func (r *Repo) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), r.identity()...)
+	return cmd
+}
+
+// identity returns the environment variables that supply the fallback identity when git has
+// no user configured, or none. git is asked once per Repo rather than before every command.
+func (r *Repo) identity() []string {
+	if r.checked {
+		return r.identityEnv
+	}
+	check := exec.Command("git", "config", "user.email")
+	check.Dir = r.dir
+	if email, err := check.Output(); err != nil || len(bytes.TrimSpace(email)) == 0 {
+		r.identityEnv = []string{
+			"GIT_AUTHOR_NAME=" + fallbackName, "GIT_AUTHOR_EMAIL=" + fallbackEmail,
+			"GIT_COMMITTER_NAME=" + fallbackName, "GIT_COMMITTER_EMAIL=" + fallbackEmail,
+		}
+	}
+	r.checked = true
+	return r.identityEnv
+}
@@ -0,0 +1,289 @@
+package gitstore
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// isolateGit keeps the user's git configuration out of the tests, so commits use the fallback
+// identity and the default branch is the same for every repository
+func isolateGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+// newRemote creates an empty bare repository to sync with
+func newRemote(t *testing.T) string {
+	t.Helper()
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	output, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, output)
+	}
+	return remote
+}
+
+// newStore creates a store holding files and keeps it in git, synced with remote when given
+func newStore(t *testing.T, remote string, files map[string]string) *Repo {
+	t.Helper()
+	repo := Open(t.TempDir())
+	for name, content := range files {
+		writeFile(t, repo, name, content)
+	}
+	err := repo.Init()
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if remote != "" {
+		err = repo.SetRemote(remote)
+		if err != nil {
+			t.Fatalf("SetRemote: %v", err)
+		}
+	}
+	return repo
+}
+
+// writeFile writes a file in the store, creating its folder
+func writeFile(t *testing.T, repo *Repo, name string, content string) {
+	t.Helper()
+	path := filepath.Join(repo.dir, filepath.FromSlash(name))
+	err := os.MkdirAll(filepath.Dir(path), fileio.DirPerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte(content), fileio.FilePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readFile returns the contents of a file in the store, empty if it does not exist
+func readFile(t *testing.T, repo *Repo, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(repo.dir, filepath.FromSlash(name)))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// sync pulls and pushes a store that is expected to have no conflicts
+func sync(t *testing.T, repo *Repo) {
+	t.Helper()
+	conflicts, err := repo.Pull()
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if len(conflicts) > 0 {
+		t.Fatalf("Pull found conflicts in %v", conflicts)
+	}
+	err = repo.Push()
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	isolateGit(t)
+	repo := newStore(t, "", map[string]string{"github.gpg": "v1"})
+	if !repo.Enabled() {
+		t.Fatal("the store is not a repository after Init")
+	}
+
+	// Files local to this copy of the store are never committed
+	writeFile(t, repo, "work/aws.gpg", "v1")
+	writeFile(t, repo, fileio.LockFilename, "{}")
+	writeFile(t, repo, fileio.UsageFilename, "{}")
+	err := repo.Commit("Add aws")
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	tracked, err := repo.git("ls-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".gitignore", "github.gpg", "work/aws.gpg"}
+	if files := strings.Split(tracked, "\n"); !slices.Equal(files, want) {
+		t.Errorf("tracked files = %v, want %v", files, want)
+	}
+
+	// Committing without changes adds nothing
+	err = repo.Commit("Nothing")
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	log, err := repo.git("log", "--format=%s|%ae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantLog := "Add aws|" + fallbackEmail + "\nStart history of password store|" + fallbackEmail
+	if log != wantLog {
+		t.Errorf("log = %q, want %q", log, wantLog)
+	}
+}
+
+func TestCommitWithoutRepository(t *testing.T) {
+	isolateGit(t)
+	repo := Open(t.TempDir())
+	err := repo.Commit("Nothing to commit to")
+	if err != nil || repo.Enabled() {
+		t.Errorf("Commit outside a repository = %v, Enabled = %v", err, repo.Enabled())
+	}
+}
+
+func TestSyncBetweenStores(t *testing.T) {
+	isolateGit(t)
+	remote := newRemote(t)
+	laptop := newStore(t, remote, map[string]string{"github.gpg": "v1"})
+	sync(t, laptop)
+
+	// A second machine starts from the remote
+	desktop := newStore(t, remote, nil)
+	sync(t, desktop)
+	if got := readFile(t, desktop, "github.gpg"); got != "v1" {
+		t.Fatalf("desktop has github.gpg = %q after pulling, want v1", got)
+	}
+	if url, _ := desktop.Remote(); url != remote {
+		t.Errorf("Remote = %q, want %q", url, remote)
+	}
+
+	// Uncommitted changes are committed by Pull, then pushed
+	writeFile(t, desktop, "work/aws.gpg", "v1")
+	writeFile(t, desktop, "github.gpg", "v2")
+	sync(t, desktop)
+	sync(t, laptop)
+	if readFile(t, laptop, "work/aws.gpg") != "v1" || readFile(t, laptop, "github.gpg") != "v2" {
+		t.Error("the laptop did not receive the desktop's changes")
+	}
+}
+
+func TestResolveConflict(t *testing.T) {
+	tests := []struct {
+		name        string
+		side        Side
+		laptop      string // Content the laptop pushes first, empty to delete the file
+		desktop     string // Content the desktop changes the file to, empty to delete it
+		want        string
+		wantDeleted bool
+	}{
+		{name: "keep ours", side: Ours, laptop: "laptop", desktop: "desktop", want: "desktop"},
+		{name: "keep theirs", side: Theirs, laptop: "laptop", desktop: "desktop", want: "laptop"},
+		{name: "keep theirs deleted", side: Theirs, laptop: "", desktop: "desktop", wantDeleted: true},
+		{name: "keep ours deleted", side: Ours, laptop: "laptop", desktop: "", wantDeleted: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			isolateGit(t)
+			remote := newRemote(t)
+			laptop := newStore(t, remote, map[string]string{"github.gpg": "v1"})
+			sync(t, laptop)
+			desktop := newStore(t, remote, nil)
+			sync(t, desktop)
+
+			change(t, laptop, "github.gpg", test.laptop)
+			sync(t, laptop)
+			change(t, desktop, "github.gpg", test.desktop)
+
+			conflicts, err := desktop.Pull()
+			if err != nil {
+				t.Fatalf("Pull: %v", err)
+			}
+			if !slices.Equal(conflicts, []string{"github.gpg"}) || !desktop.Merging() {
+				t.Fatalf("Pull = %v with Merging = %v, want a conflict in github.gpg", conflicts, desktop.Merging())
+			}
+			for _, side := range []Side{Ours, Theirs} {
+				content := test.desktop
+				if side == Theirs {
+					content = test.laptop
+				}
+				data, exists, err := desktop.Version("github.gpg", side)
+				if err != nil || exists != (content != "") || string(data) != content {
+					t.Errorf("Version(%s) = %q, %v, %v, want %q", side, data, exists, err, content)
+				}
+			}
+
+			// Committing is refused until the conflict is settled
+			if err := desktop.Commit("During the merge"); err == nil {
+				t.Error("Commit worked during an unfinished merge")
+			}
+			err = desktop.Resolve("github.gpg", test.side)
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			err = desktop.FinishMerge()
+			if err != nil {
+				t.Fatalf("FinishMerge: %v", err)
+			}
+			if desktop.Merging() {
+				t.Error("still merging after FinishMerge")
+			}
+			got := readFile(t, desktop, "github.gpg")
+			if test.wantDeleted && fileio.FileExists(filepath.Join(desktop.dir, "github.gpg")) {
+				t.Errorf("github.gpg = %q, want it deleted", got)
+			} else if !test.wantDeleted && got != test.want {
+				t.Errorf("github.gpg = %q, want %q", got, test.want)
+			}
+
+			// The resolution reaches the other machine
+			sync(t, desktop)
+			sync(t, laptop)
+			if readFile(t, laptop, "github.gpg") != got {
+				t.Error("the laptop did not receive the resolution")
+			}
+		})
+	}
+}
+
+func TestAbortMerge(t *testing.T) {
+	isolateGit(t)
+	remote := newRemote(t)
+	laptop := newStore(t, remote, map[string]string{"github.gpg": "v1"})
+	sync(t, laptop)
+	desktop := newStore(t, remote, nil)
+	sync(t, desktop)
+
+	change(t, laptop, "github.gpg", "laptop")
+	sync(t, laptop)
+	change(t, desktop, "github.gpg", "desktop")
+	conflicts, err := desktop.Pull()
+	if err != nil || len(conflicts) != 1 {
+		t.Fatalf("Pull = %v, %v, want a conflict", conflicts, err)
+	}
+
+	err = desktop.AbortMerge()
+	if err != nil {
+		t.Fatalf("AbortMerge: %v", err)
+	}
+	if desktop.Merging() || readFile(t, desktop, "github.gpg") != "desktop" {
+		t.Error("AbortMerge did not put the store back as it was")
+	}
+}
+
+// change writes a new version of a file in the store and commits it, deleting the file when
+// content is empty
+func change(t *testing.T, repo *Repo, name string, content string) {
+	t.Helper()
+	if content == "" {
+		err := os.Remove(filepath.Join(repo.dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		writeFile(t, repo, name, content)
+	}
+	err := repo.Commit("Change " + name)
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Canonical column names shared by the CSV based parsers
+const (
+	columnTitle    = "title"
+	columnUsername = "username"
+	columnPassword = "password"
+	columnURL      = "url"
+	columnCreated  = "created"
+	columnModified = "modified"
+	columnOTP      = "otp"
+	columnNotes    = "notes"
+	columnTags     = "tags"
+)
+
+// csvRow maps canonical column names to the values of a single CSV record
+type csvRow map[string]string
+
+// readCSV reads a CSV export with a header row and maps each record onto canonical column names.
+// aliases lists, for every canonical column, the header names used by the exporting application
+// (compared case-insensitively). The password column is always required.
+func readCSV(r io.Reader, aliases map[string][]string) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Some exporters omit trailing empty columns
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header row: %v", err)
+	}
+
+	// Work out which column index holds each canonical field
+	indexes := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for column, names := range aliases {
+			if _, found := indexes[column]; found {
+				continue
+			}
+			for _, alias := range names {
+				if name == strings.ToLower(alias) {
+					indexes[column] = i
+					break
+				}
+			}
+		}
+	}
+	if _, ok := indexes[columnPassword]; !ok {
+		return nil, fmt.Errorf("no password column found in header %v", header)
+	}
+
+	var rows []csvRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(csvRow)
+		for column, index := range indexes {
+			if index < len(record) {
+				row[column] = record[index]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
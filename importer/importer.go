@@ -0,0 +1,149 @@
+// Package importer reads password exports from other password managers and writes them into the store.
+// Each supported format is implemented as a Parser and registered in Parsers, so new formats can be
+// added without touching the import flow.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// Entry is a single credential read from an external export, ready to be stored
+type Entry struct {
+	SiteName  string          // Display name for the site, used to generate the filename
+	Data      encryption.Data // Password and metadata to encrypt
+	Duplicate bool            // Whether the entry matches an existing entry or an earlier entry in the same file
+}
+
+// Parser converts an export file from another password manager into entries
+type Parser interface {
+	// Name returns the human readable name of the format, e.g. "Bitwarden (JSON)"
+	Name() string
+
+	// Parse reads the export and returns every login it contains
+	Parse(r io.Reader) ([]Entry, error)
+}
+
+// Parsers holds every registered import format in the order they are offered to the user
+var Parsers []Parser
+
+// Register adds a parser to the list of available import formats
+func Register(parser Parser) {
+	Parsers = append(Parsers, parser)
+}
+
+func init() {
+	Register(BitwardenParser{})
+	Register(KeePassParser{})
+	Register(OnePasswordParser{})
+	Register(BrowserParser{})
+}
+
+// ParseFile opens the file at path and parses it with the given parser
+func ParseFile(parser Parser, path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open import file %s: %v", path, err)
+	}
+	defer file.Close()
+
+	entries, err := parser.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s export: %v", parser.Name(), err)
+	}
+	return entries, nil
+}
+
+// DuplicateKey returns the key used to detect duplicate entries. Two entries are duplicates
+// when they would produce the same filename prefix and have the same username.
+func DuplicateKey(siteName, username string) string {
+	return utils.CleanSiteName(siteName) + "|" + strings.ToLower(strings.TrimSpace(username))
+}
+
+// MarkDuplicates flags every entry whose key is in existing or appears earlier in entries.
+// Returns the number of entries marked as duplicates.
+func MarkDuplicates(entries []Entry, existing map[string]bool) int {
+	seen := make(map[string]bool, len(existing)+len(entries))
+	for key := range existing {
+		seen[key] = true
+	}
+
+	count := 0
+	for i := range entries {
+		key := DuplicateKey(entries[i].SiteName, entries[i].Data.Username)
+		entries[i].Duplicate = seen[key]
+		if entries[i].Duplicate {
+			count++
+		}
+		seen[key] = true
+	}
+	return count
+}
+
+// Commit encrypts and writes the entries into the store. Duplicates are skipped when
+// skipDuplicates is set. Returns the number of entries written.
+func Commit(pf *fileio.PasswordFolder, ef *encryption.EncryptionFunctions, entries []Entry, skipDuplicates bool) (int, error) {
+	written := 0
+	for _, entry := range entries {
+		if entry.Duplicate && skipDuplicates {
+			continue
+		}
+
+		filename := pf.UniqueFilename(utils.GenerateFilename(entry.SiteName))
+		err := ef.EncryptPasswordAndWriteToFile(filename, entry.Data)
+		if err != nil {
+			return written, fmt.Errorf("failed to save '%s': %v", entry.SiteName, err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// siteNameFromURL derives a display name from a URL when an export has no title
+func siteNameFromURL(rawURL string) string {
+	host := strings.TrimSpace(rawURL)
+	if i := strings.Index(host, "://"); i != -1 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/:?#"); i != -1 {
+		host = host[:i]
+	}
+	return strings.TrimPrefix(host, "www.")
+}
+
+// newEntry builds an entry from the common fields shared by every format.
+// Entries without a title fall back to the URL host, and usernames that look like
+// email addresses are also stored as the email.
+func newEntry(title, username, password, url string) Entry {
+	siteName := utils.SanitizeInput(title)
+	if siteName == "" {
+		siteName = siteNameFromURL(url)
+	}
+	if siteName == "" {
+		siteName = "Imported"
+	}
+
+	now := time.Now()
+	data := encryption.Data{
+		Password:  password,
+		Username:  utils.SanitizeInput(username),
+		URL:       utils.SanitizeInput(url),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if strings.Contains(data.Username, "@") {
+		data.Email = data.Username
+	}
+
+	return Entry{
+		SiteName: siteName,
+		Data:     data,
+	}
+}
@@ -0,0 +1,55 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/Fozzyack/password-manager/encryption"
+)
+
+func TestMarkDuplicates(t *testing.T) {
+	entries := []Entry{
+		{SiteName: "GitHub", Data: encryption.Data{Username: "octocat"}},
+		{SiteName: "GitLab", Data: encryption.Data{Username: "octocat"}},
+		{SiteName: "gitlab", Data: encryption.Data{Username: " OctoCat "}},
+		{SiteName: "GitLab", Data: encryption.Data{Username: "someone-else"}},
+	}
+	existing := map[string]bool{
+		DuplicateKey("github", "octocat"): true,
+	}
+
+	count := MarkDuplicates(entries, existing)
+	if count != 2 {
+		t.Errorf("MarkDuplicates = %d, want 2", count)
+	}
+	want := []bool{true, false, true, false}
+	for i, entry := range entries {
+		if entry.Duplicate != want[i] {
+			t.Errorf("entry %d (%s, %s): Duplicate = %v, want %v", i, entry.SiteName, entry.Data.Username, entry.Duplicate, want[i])
+		}
+	}
+}
+
+func TestSiteNameFromURL(t *testing.T) {
+	tests := map[string]string{
+		"https://www.example.com/login?next=/": "example.com",
+		"http://intranet:8080":                 "intranet",
+		"accounts.example.org/#signin":         "accounts.example.org",
+		"":                                     "",
+	}
+	for url, want := range tests {
+		if got := siteNameFromURL(url); got != want {
+			t.Errorf("siteNameFromURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestNewEntryFallsBackToURL(t *testing.T) {
+	entry := newEntry("", "frank", "pw", "https://shop.example.com/account")
+	if entry.SiteName != "shop.example.com" {
+		t.Errorf("SiteName = %q, want the URL host", entry.SiteName)
+	}
+	entry = newEntry("  ", "", "pw", "")
+	if entry.SiteName != "Imported" {
+		t.Errorf("SiteName = %q, want Imported", entry.SiteName)
+	}
+}
@@ -0,0 +1,227 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/otp"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// BitwardenParser reads the unencrypted JSON export produced by Bitwarden
+type BitwardenParser struct{}
+
+// bitwardenExport mirrors the parts of the Bitwarden JSON export that map onto a password entry
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Items     []struct {
+		Type         int    `json:"type"` // 1 is a login, other types are cards, notes and identities
+		Name         string `json:"name"`
+		Notes        string `json:"notes"`
+		Favorite     bool   `json:"favorite"`
+		CreationDate string `json:"creationDate"`
+		RevisionDate string `json:"revisionDate"`
+		Login        *struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			TOTP     string `json:"totp"`
+			URIs     []struct {
+				URI string `json:"uri"`
+			} `json:"uris"`
+		} `json:"login"`
+		Fields []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+			Type  int    `json:"type"` // 0 is text, 1 is hidden, 2 is a checkbox
+		} `json:"fields"`
+	} `json:"items"`
+}
+
+// Name implements the Parser interface
+func (BitwardenParser) Name() string {
+	return "Bitwarden (JSON)"
+}
+
+// Parse implements the Parser interface. Only login items are imported.
+func (BitwardenParser) Parse(r io.Reader) ([]Entry, error) {
+	export := bitwardenExport{}
+	err := json.NewDecoder(r).Decode(&export)
+	if err != nil {
+		return nil, err
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("encrypted Bitwarden exports are not supported, export as unencrypted JSON")
+	}
+
+	var entries []Entry
+	for _, item := range export.Items {
+		if item.Type != 1 || item.Login == nil {
+			continue
+		}
+
+		url := ""
+		if len(item.Login.URIs) > 0 {
+			url = item.Login.URIs[0].URI
+		}
+
+		entry := newEntry(item.Name, item.Login.Username, item.Login.Password, url)
+		setTimestamps(&entry, item.CreationDate, item.RevisionDate)
+		setOTP(&entry, item.Login.TOTP)
+		for _, field := range item.Fields {
+			fieldType := encryption.FieldText
+			if field.Type == 1 {
+				fieldType = encryption.FieldHidden
+			}
+			entry.Data.Fields = append(entry.Data.Fields, encryption.Field{Name: field.Name, Type: fieldType, Value: field.Value})
+		}
+		setNotes(&entry, item.Notes)
+		entry.Data.Favourite = item.Favorite
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// KeePassParser reads CSV exports from KeePassXC and KeePass 2
+type KeePassParser struct{}
+
+// Name implements the Parser interface
+func (KeePassParser) Name() string {
+	return "KeePass / KeePassXC (CSV)"
+}
+
+// Parse implements the Parser interface
+func (KeePassParser) Parse(r io.Reader) ([]Entry, error) {
+	return parseCSVEntries(r, map[string][]string{
+		columnTitle:    {"Title", "Account"},
+		columnUsername: {"Username", "Login Name", "User Name"},
+		columnPassword: {"Password"},
+		columnURL:      {"URL", "Web Site"},
+		columnCreated:  {"Created", "Creation Time"},
+		columnModified: {"Last Modified", "Last Modification"},
+		columnOTP:      {"TOTP"},
+		columnNotes:    {"Notes", "Comments"},
+	})
+}
+
+// OnePasswordParser reads CSV exports from 1Password
+type OnePasswordParser struct{}
+
+// Name implements the Parser interface
+func (OnePasswordParser) Name() string {
+	return "1Password (CSV)"
+}
+
+// Parse implements the Parser interface
+func (OnePasswordParser) Parse(r io.Reader) ([]Entry, error) {
+	return parseCSVEntries(r, map[string][]string{
+		columnTitle:    {"Title", "Name"},
+		columnUsername: {"Username"},
+		columnPassword: {"Password"},
+		columnURL:      {"Url", "Website", "URLs"},
+		columnCreated:  {"Created Date", "createdAt"},
+		columnModified: {"Modified Date", "updatedAt"},
+		columnOTP:      {"OTPAuth", "One-time password"},
+		columnNotes:    {"Notes", "notesPlain"},
+		columnTags:     {"Tags", "tags"},
+	})
+}
+
+// BrowserParser reads password CSV exports from Chrome, Edge and Firefox.
+// Firefox exports have no title column, so the site name is derived from the URL.
+type BrowserParser struct{}
+
+// Name implements the Parser interface
+func (BrowserParser) Name() string {
+	return "Chrome / Firefox (CSV)"
+}
+
+// Parse implements the Parser interface
+func (BrowserParser) Parse(r io.Reader) ([]Entry, error) {
+	return parseCSVEntries(r, map[string][]string{
+		columnTitle:    {"name"},
+		columnUsername: {"username"},
+		columnPassword: {"password"},
+		columnURL:      {"url", "origin"},
+		columnCreated:  {"timeCreated"},
+		columnModified: {"timePasswordChanged"},
+	})
+}
+
+// parseCSVEntries reads a CSV export using the given column aliases and converts every
+// row with a password into an entry
+func parseCSVEntries(r io.Reader, aliases map[string][]string) ([]Entry, error) {
+	rows, err := readCSV(r, aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, row := range rows {
+		if row[columnPassword] == "" {
+			continue
+		}
+		entry := newEntry(row[columnTitle], row[columnUsername], row[columnPassword], row[columnURL])
+		setTimestamps(&entry, row[columnCreated], row[columnModified])
+		setOTP(&entry, row[columnOTP])
+		setNotes(&entry, row[columnNotes])
+		entry.Data.Tags = utils.ParseTags(strings.ReplaceAll(row[columnTags], ";", ","))
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// setTimestamps copies the exported creation and modification times onto the entry when they can be parsed
+func setTimestamps(entry *Entry, created, modified string) {
+	if t, ok := parseTime(created); ok {
+		entry.Data.CreatedAt = t
+		entry.Data.UpdatedAt = t
+	}
+	if t, ok := parseTime(modified); ok {
+		entry.Data.UpdatedAt = t
+	}
+}
+
+// setOTP stores the exported 2FA key on the entry. Keys that cannot be read are left out
+// rather than failing the import; the password is what matters most.
+func setOTP(entry *Entry, value string) {
+	key, err := otp.Normalize(value)
+	if err == nil {
+		entry.Data.OTP = key
+	}
+}
+
+// setNotes keeps exported notes as a notes field on the entry
+func setNotes(entry *Entry, notes string) {
+	if strings.TrimSpace(notes) != "" {
+		entry.Data.Fields = append(entry.Data.Fields, encryption.Field{Name: "Notes", Type: encryption.FieldNotes, Value: notes})
+	}
+}
+
+// parseTime understands the timestamp formats used by the supported exporters:
+// RFC3339, plain date-times, and Unix timestamps in seconds or milliseconds
+func parseTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if number > 1e12 {
+			return time.UnixMilli(number), true
+		}
+		return time.Unix(number, 0), true
+	}
+
+	layouts := []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+)
+
+func TestBitwardenParser(t *testing.T) {
+	export := `{
+		"encrypted": false,
+		"items": [
+			{
+				"type": 1,
+				"name": "GitHub",
+				"notes": "recovery codes in the safe",
+				"favorite": true,
+				"creationDate": "2024-01-02T03:04:05Z",
+				"revisionDate": "2024-02-03T04:05:06Z",
+				"login": {
+					"username": "octo@example.com",
+					"password": "hunter2",
+					"totp": "JBSWY3DPEHPK3PXP",
+					"uris": [{"uri": "https://github.com/login"}]
+				},
+				"fields": [{"name": "PIN", "value": "1234", "type": 1}]
+			},
+			{"type": 2, "name": "A secure note"}
+		]
+	}`
+
+	entries, err := BitwardenParser{}.Parse(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want only the login", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.SiteName != "GitHub" || entry.Data.Password != "hunter2" || entry.Data.URL != "https://github.com/login" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.Data.Email != "octo@example.com" {
+		t.Errorf("Email = %q, want the username that looks like an email", entry.Data.Email)
+	}
+	if !entry.Data.Favourite {
+		t.Error("the favourite was not carried over")
+	}
+	if !strings.HasPrefix(entry.Data.OTP, "otpauth://totp/") {
+		t.Errorf("OTP = %q, want an otpauth:// URI", entry.Data.OTP)
+	}
+	if want := time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC); !entry.Data.UpdatedAt.Equal(want) {
+		t.Errorf("UpdatedAt = %v, want %v", entry.Data.UpdatedAt, want)
+	}
+	wantFields := []encryption.Field{
+		{Name: "PIN", Type: encryption.FieldHidden, Value: "1234"},
+		{Name: "Notes", Type: encryption.FieldNotes, Value: "recovery codes in the safe"},
+	}
+	if !reflect.DeepEqual(entry.Data.Fields, wantFields) {
+		t.Errorf("Fields = %+v, want %+v", entry.Data.Fields, wantFields)
+	}
+}
+
+func TestBitwardenParserRejectsEncryptedExports(t *testing.T) {
+	_, err := BitwardenParser{}.Parse(strings.NewReader(`{"encrypted": true, "items": []}`))
+	if err == nil {
+		t.Fatal("Parse accepted an encrypted export")
+	}
+}
+
+func TestCSVParsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser Parser
+		export string
+		want   []Entry
+	}{
+		{
+			name:   "KeePassXC",
+			parser: KeePassParser{},
+			export: "\"Group\",\"Title\",\"Username\",\"Password\",\"URL\",\"Notes\"\n" +
+				"\"Root\",\"Mail\",\"alice\",\"s3cret\",\"https://mail.example.com\",\"\"\n" +
+				"\"Root\",\"No password\",\"bob\",\"\",\"\",\"\"\n",
+			want: []Entry{{SiteName: "Mail", Data: encryption.Data{Username: "alice", Password: "s3cret", URL: "https://mail.example.com"}}},
+		},
+		{
+			name:   "1Password with tags",
+			parser: OnePasswordParser{},
+			export: "Title,Url,Username,Password,Tags\n" +
+				"Bank,https://bank.example.com,carol,pa55,Finance;Home Office\n",
+			want: []Entry{{SiteName: "Bank", Data: encryption.Data{Username: "carol", Password: "pa55", URL: "https://bank.example.com", Tags: []string{"finance", "home-office"}}}},
+		},
+		{
+			name:   "Firefox without a title column",
+			parser: BrowserParser{},
+			export: "\ufeffurl,username,password\n" +
+				"https://www.example.org:8443/login,dave@example.org,letmein\n",
+			want: []Entry{{SiteName: "example.org", Data: encryption.Data{Username: "dave@example.org", Email: "dave@example.org", Password: "letmein", URL: "https://www.example.org:8443/login"}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := test.parser.Parse(strings.NewReader(test.export))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			// Timestamps default to the time of the import, so they are left out of the comparison
+			for i := range entries {
+				entries[i].Data.CreatedAt = time.Time{}
+				entries[i].Data.UpdatedAt = time.Time{}
+			}
+			if !reflect.DeepEqual(entries, test.want) {
+				t.Errorf("entries = %+v\nwant %+v", entries, test.want)
+			}
+		})
+	}
+}
+
+func TestCSVParserRequiresPasswordColumn(t *testing.T) {
+	_, err := BrowserParser{}.Parse(strings.NewReader("name,url,username\nsite,https://example.com,erin\n"))
+	if err == nil {
+		t.Fatal("Parse accepted an export without a password column")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{"2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"2024-01-02 03:04:05", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"1704164645", time.Unix(1704164645, 0), true},
+		{"1704164645123", time.UnixMilli(1704164645123), true},
+		{"", time.Time{}, false},
+		{"yesterday", time.Time{}, false},
+	}
+	for _, test := range tests {
+		got, ok := parseTime(test.value)
+		if ok != test.ok || !got.Equal(test.want) {
+			t.Errorf("parseTime(%q) = %v, %v, want %v, %v", test.value, got, ok, test.want, test.ok)
+		}
+	}
+}
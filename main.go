@@ -3,22 +3,75 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/Fozzyack/password-manager/cli"
+	"github.com/Fozzyack/password-manager/clipboard"
+	"github.com/Fozzyack/password-manager/config"
 	"github.com/Fozzyack/password-manager/encryption"
 	"github.com/Fozzyack/password-manager/fileio"
 	"github.com/Fozzyack/password-manager/menus"
+	"github.com/Fozzyack/password-manager/session"
+	"github.com/Fozzyack/password-manager/throttle"
 	"github.com/Fozzyack/password-manager/types"
 )
 
 // main is the application entry point. It initializes the password store,
 // handles the login flow with validation, and manages the main application loop.
-// The application supports first-time setup with master password and phrase validation,
+// The application supports first-time setup with a master password that wraps a generated data key,
 // as well as subsequent logins with password verification.
 func main() {
 
-	passwordFolder := fileio.InitPasswordFolder()
-	var err error
+	// Global flags choose the store and must come before any subcommand
+	storeFlag := flag.String("store", "", "path of the password store to open")
+	vaultFlag := flag.String("vault", "", "name of a vault from the config file to open")
+	configFlag := flag.String("config", "", "path of the config file (default $PASSWORD_MANAGER_CONFIG or ~/.config/password-manager/config.json)")
+	flag.Usage = func() {
+		cli.PrintUsage(os.Stderr)
+		fmt.Fprintf(os.Stderr, "\nGlobal flags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	configPath := config.DefaultPath()
+	if *configFlag != "" {
+		configPath = config.ExpandPath(*configFlag)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(cli.ExitError)
+	}
+	store, err := cfg.Resolve(*storeFlag, *vaultFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(cli.ExitError)
+	}
+
+	// Run a non-interactive subcommand when one is given, e.g. `password-manager show github`
+	if flag.NArg() > 0 {
+		settings := cli.Settings{
+			Location:         store.Location,
+			MaxLoginAttempts: cfg.MaxLoginAttempts,
+			Keyring:          cfg.KeyringPath(),
+		}
+		os.Exit(cli.Run(flag.Args(), settings, os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	// Configure how long copied secrets stay on the clipboard, and never leave one behind on exit
+	clipboard.SetDefault(clipboard.NewManager(clipboard.DetectBackend(), clipboardTimeout()))
+	defer clipboard.Default().Clear()
+
+	passwordFolder, err := fileio.OpenPasswordFolder(store.Location)
+	if err != nil {
+		log.Fatal("Could not Open or Create Password Store File: ", err)
+	}
 
 	for file := range(passwordFolder.Dirs) {
 		fmt.Println(passwordFolder.Dirs[file])
@@ -28,56 +81,122 @@ func main() {
 		Quit : false,
 		LoggedIn: false,
 		ErrorMessage: "",
+		Vault: store.Name,
 	}
 	encrypt := encryption.NewEncryption(passwordFolder)
-	menu := menus.InitMenus(passwordFolder, encrypt, options)
-	for !options.LoggedIn && !options.Quit{
-		options.LoggedIn, err = menu.Login()
-		if err != nil {
-			panic(err)
-		} else if options.Quit {
-			fmt.Print("\033[2J\033[H") // Clear screen
-			fmt.Println("Escape Sequence Detected :: Exiting")
-		} else if !options.LoggedIn {
-			// Clear screen and show error message
-			fmt.Print("\033[2J\033[H")
-			fmt.Printf("\033[91m\033[1mIncorrect Password - Try again\033[0m\n\n")
-			fmt.Println("Press Enter to continue...")
-			fmt.Scanln() // Wait for user to press Enter
-		} else {
-			// Clear error message on successful login
-			options.ErrorMessage = ""
-		}
-	}
-
-	// Main menu loop after successful login
-	for options.LoggedIn && !options.Quit {
-		action, err := menu.ShowMainMenu()
-		if err != nil {
-			fmt.Printf("Error displaying menu: %v\n", err)
-			break
-		}
-
-		// Handle the selected action
-		handleMenuAction(action, menu)
-		
-		// Check if user wants to quit
-		if action == "quit" || options.Quit {
-			fmt.Print("\033[2J\033[H") // Clear screen
-			fmt.Println("Goodbye! 👋")
-			break
+	encrypt.KeyringPath = cfg.KeyringPath()
+	menu := menus.InitMenus(passwordFolder, encrypt, cfg, session.NewLocker(lockTimeout(cfg)), options)
+
+	// Only one instance may write to a store, any other opens it read-only
+	err = menu.AcquireStoreLock()
+	if err != nil {
+		log.Fatal("Could not Lock Password Store: ", err)
+	}
+	defer menu.ReleaseStoreLock()
+
+	// Offer to lock down a store that other users can read before anything is unlocked
+	err = menu.CheckPermissions()
+	if err != nil {
+		log.Printf("WARNING: Could not check store permissions: %v", err)
+	}
+
+	// Switching vaults logs the user out, so keep returning to the login screen until they quit
+	var loginErr error
+	for !options.Quit {
+		for !options.LoggedIn && !options.Quit{
+			options.LoggedIn, err = menu.Login()
+			if errors.Is(err, throttle.ErrLockedOut) || errors.Is(err, encryption.ErrNoIdentity) || errors.Is(err, fileio.ErrReadOnly) {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("\033[91m\033[1m🔒 %v\033[0m\n", err)
+				options.Quit = true
+			} else if err != nil {
+				// A damaged key file or encryption settings, not a wrong password
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Fprintf(os.Stderr, "❌ Error logging in: %v\n", err)
+				loginErr = err
+				options.Quit = true
+			} else if options.Quit {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Println("Escape Sequence Detected :: Exiting")
+			} else if !options.LoggedIn {
+				// Clear screen and show error message
+				fmt.Print("\033[2J\033[H")
+				fmt.Printf("\033[91m\033[1mIncorrect Password - Try again\033[0m\n\n")
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln() // Wait for user to press Enter
+			} else {
+				// Clear error message on successful login
+				options.ErrorMessage = ""
+			}
+		}
+
+		// Main menu loop after successful login
+		for options.LoggedIn && !options.Quit {
+			action, err := menu.ShowMainMenu()
+			if menu.IsLocked() {
+				// Idle while the menu was open, go back to the login screen
+				menu.Lock()
+				break
+			}
+			if err != nil {
+				fmt.Printf("Error displaying menu: %v\n", err)
+				options.Quit = true
+				break
+			}
+
+			// Handle the selected action
+			handleMenuAction(action, menu)
+			if menu.IsLocked() {
+				menu.Lock()
+				break
+			}
+			
+			// Check if user wants to quit
+			if action == "quit" || options.Quit {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Println("Goodbye! 👋")
+				options.Quit = true
+				break
+			}
 		}
 	}
+
+	if loginErr != nil {
+		// os.Exit skips the deferred cleanup, so release the store and clipboard first
+		menu.ReleaseStoreLock()
+		clipboard.Default().Clear()
+		os.Exit(cli.ExitError)
+	}
+}
+
+// writeActions are the menu actions that change the store, refused while it is open read-only
+var writeActions = map[string]bool{
+	"add":           true,
+	"change_master": true,
+	"import":        true,
+	"restore":       true,
+	"tags":          true,
+	"trash":         true,
+	"crypto":        true,
+	"recipients":    true,
+	"sync":          true,
 }
 
 // handleMenuAction processes the selected menu action and calls appropriate functions
 func handleMenuAction(action string, menu *menus.Menu) {
 	fmt.Print("\033[2J\033[H") // Clear screen
 
+	if writeActions[action] && menu.Options.ReadOnly {
+		fmt.Printf("🔒 This vault is open read-only while another instance holds its lock.\n")
+		fmt.Printf("Close the other instance to make changes.\n")
+		waitForEnter()
+		return
+	}
+
 	switch action {
 	case "list":
 		_, err := menu.ListAllPasswords()
-		if err != nil {
+		if err != nil && !menu.IsLocked() {
 			fmt.Print("\033[2J\033[H") // Clear screen
 			fmt.Printf("❌ Error listing passwords: %v\n\n", err)
 			waitForEnter()
@@ -85,7 +204,7 @@ func handleMenuAction(action string, menu *menus.Menu) {
 
 	case "add":
 		_, err := menu.AddNewPassword()
-		if err != nil {
+		if err != nil && !menu.IsLocked() {
 			fmt.Print("\033[2J\033[H") // Clear screen
 			fmt.Printf("❌ Error adding password: %v\n\n", err)
 			waitForEnter()
@@ -94,16 +213,94 @@ func handleMenuAction(action string, menu *menus.Menu) {
 
 	case "change_master":
 		_, err := menu.ChangeMasterPassword()
-		if err != nil {
+		if err != nil && !menu.IsLocked() {
 			fmt.Print("\033[2J\033[H") // Clear screen
 			fmt.Printf("❌ Error changing master password: %v\n\n", err)
 			waitForEnter()
 		}
 
+	case "import":
+		_, err := menu.ImportPasswords()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error importing passwords: %v\n\n", err)
+			waitForEnter()
+		}
+
 	case "export":
-		fmt.Println("📤 Exporting passwords...")
-		fmt.Println("This feature is coming soon!")
-		waitForEnter()
+		_, err := menu.ExportPasswords()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error exporting passwords: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "backup":
+		_, err := menu.CreateBackup()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error creating backup: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "restore":
+		_, err := menu.RestoreBackup()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error restoring backup: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "tags":
+		_, err := menu.ManageTags()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error managing tags: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "trash":
+		_, err := menu.ShowTrash()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error managing the trash: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "crypto":
+		_, err := menu.EncryptionSettings()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error changing encryption settings: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "recipients":
+		_, err := menu.ManageRecipients()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error changing recipients: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "sync":
+		_, err := menu.Sync()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error syncing vault: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "vault":
+		_, err := menu.SwitchVault()
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error switching vault: %v\n\n", err)
+			waitForEnter()
+		}
+
+	case "lock":
+		menu.Lock()
 
 	case "quit":
 		// Handled in main loop
@@ -115,6 +312,39 @@ func handleMenuAction(action string, menu *menus.Menu) {
 	}
 }
 
+// clipboardTimeout returns the clipboard clearing timeout, read in seconds from
+// PASSWORD_MANAGER_CLIPBOARD_TIMEOUT. A value of 0 disables clearing.
+func clipboardTimeout() time.Duration {
+	value := os.Getenv("PASSWORD_MANAGER_CLIPBOARD_TIMEOUT")
+	if value == "" {
+		return clipboard.DefaultTimeout
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		fmt.Printf("Ignoring invalid PASSWORD_MANAGER_CLIPBOARD_TIMEOUT %q\n", value)
+		return clipboard.DefaultTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// lockTimeout returns how long the vault stays unlocked without input, read in seconds from
+// PASSWORD_MANAGER_LOCK_TIMEOUT or lock_timeout in the config file. A value of 0 disables auto-lock.
+func lockTimeout(cfg *config.Config) time.Duration {
+	value := os.Getenv("PASSWORD_MANAGER_LOCK_TIMEOUT")
+	if value == "" {
+		if cfg.LockTimeout == nil || *cfg.LockTimeout < 0 {
+			return session.DefaultTimeout
+		}
+		return time.Duration(*cfg.LockTimeout) * time.Second
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		fmt.Printf("Ignoring invalid PASSWORD_MANAGER_LOCK_TIMEOUT %q\n", value)
+		return session.DefaultTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 // waitForEnter pauses execution until the user presses Enter
 func waitForEnter() {
 	fmt.Println("\nPress Enter to continue...")
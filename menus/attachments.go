@@ -0,0 +1,57 @@
+package menus
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/ui/textinput"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// SaveAttachment asks where to save an entry's attachment and writes it there, readable only
+// by the owner as it is usually a secret. Existing files are never overwritten.
+// Returns true if the attachment was saved, false if cancelled.
+func (m *Menu) SaveAttachment(attachment encryption.Attachment) (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	var path string
+	header := fmt.Sprintf("Save %s (%s) to\nA folder saves it under its own name", attachment.Name, utils.FormatSize(len(attachment.Data)))
+	_, err := m.run(textinput.InitialModel(header, "~/Downloads", &path, m.Options))
+	if err != nil {
+		return false, err
+	}
+	path = strings.TrimSpace(path)
+	if m.Options.Quit || path == "" {
+		// Escape cancels rather than quitting the application
+		m.Options.Quit = false
+		return false, nil
+	}
+
+	path = export.ExpandPath(path)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, attachment.Name)
+	}
+	if fileio.FileExists(path) {
+		return false, fmt.Errorf("'%s' already exists", path)
+	}
+	err = os.WriteFile(path, attachment.Data, fileio.FilePerm)
+	if err != nil {
+		return false, fmt.Errorf("failed to save attachment: %v", err)
+	}
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Attachment saved successfully!\n\n")
+	fmt.Printf("File: %s\n\n", path)
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
@@ -0,0 +1,191 @@
+package menus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/backup"
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/Fozzyack/password-manager/ui/backupform"
+	"github.com/Fozzyack/password-manager/ui/collision"
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	"github.com/Fozzyack/password-manager/ui/textinput"
+)
+
+// CreateBackup asks for a destination and passphrase and writes an encrypted backup of the whole store.
+// Returns true if the backup was written, false if cancelled.
+func (m *Menu) CreateBackup() (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	backupForm := backupform.NewBackupForm(m.Options)
+	finalModel, err := m.run(backupForm)
+	if err != nil {
+		return false, fmt.Errorf("error running backup form: %v", err)
+	}
+
+	formModel := finalModel.(backupform.BackupFormModel)
+	if formModel.IsCancelled() || !formModel.IsSubmitted() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	path, passphrase := formModel.GetFormData()
+	path = export.ExpandPath(path)
+
+	count, err := backup.Create(m.passwordFolder, passphrase, path)
+	if err != nil {
+		return false, err
+	}
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Backup created successfully!\n\n")
+	fmt.Printf("Entries: %d\n", count)
+	fmt.Printf("File: %s\n\n", path)
+	fmt.Printf("Keep the backup passphrase safe - the backup cannot be restored without it.\n\n")
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
+
+// RestoreBackup opens an encrypted backup, verifies its manifest, lets the user resolve
+// filename collisions and writes the entries into the store. Entries from a vault with a
+// different data key are re-encrypted with the current key after asking for that vault's
+// master password. The store's own .checker/init.gpg is never replaced.
+// Returns true if entries were restored, false if cancelled.
+func (m *Menu) RestoreBackup() (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	restoreForm := backupform.NewRestoreForm(m.Options)
+	finalModel, err := m.run(restoreForm)
+	if err != nil {
+		return false, fmt.Errorf("error running restore form: %v", err)
+	}
+
+	formModel := finalModel.(backupform.BackupFormModel)
+	if formModel.IsCancelled() || !formModel.IsSubmitted() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	path, passphrase := formModel.GetFormData()
+	bundle, err := backup.Open(export.ExpandPath(path), passphrase)
+	if err != nil {
+		return false, err
+	}
+
+	if len(bundle.Entries) == 0 {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("The backup does not contain any password entries.\n\n")
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+		return false, nil
+	}
+
+	// Make sure the entries can be read with the current data key
+	unlocked, err := m.unlockBundle(bundle)
+	if err != nil || !unlocked {
+		return false, err
+	}
+
+	// Let the user decide what to do with entries that already exist
+	resolutions := map[string]backup.Resolution{}
+	collisions := bundle.Collisions(m.passwordFolder)
+	if len(collisions) > 0 {
+		collisionView := collision.NewCollisionView(collisions, m.Options)
+		finalCollisionModel, err := m.run(collisionView)
+		if err != nil {
+			return false, fmt.Errorf("error running collision view: %v", err)
+		}
+
+		collisionModel := finalCollisionModel.(collision.CollisionModel)
+		if !collisionModel.IsConfirmed() {
+			return false, nil
+		}
+		resolutions = collisionModel.GetResolutions()
+	} else {
+		confirmDialog := confirm.NewWarningDialog(
+			"restore",
+			"Restore all entries from this backup into your password store?",
+			fmt.Sprintf("Entries: %d\nCreated: %s", len(bundle.Entries), bundle.Manifest.CreatedAt.Format("Jan 2, 2006 at 3:04 PM")),
+			m.Options,
+		)
+		finalConfirmModel, err := m.run(confirmDialog)
+		if err != nil {
+			return false, fmt.Errorf("error running confirmation dialog: %v", err)
+		}
+		if !finalConfirmModel.(confirm.ConfirmModel).IsConfirmed() {
+			return false, nil
+		}
+	}
+
+	count, err := backup.Restore(m.passwordFolder, bundle, resolutions)
+	if err != nil {
+		return false, err
+	}
+	m.commitChange("Restore %d entries from backup", count)
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Backup restored successfully!\n\n")
+	fmt.Printf("Restored: %d of %d entries\n\n", count, len(bundle.Entries))
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
+
+// unlockBundle checks whether the bundle entries can be read with the current vault's keys.
+// If not, it asks for the master password of the vault the backup came from, unlocks that
+// vault's data key from the bundled init.gpg and re-encrypts the entries for the current vault.
+// Returns false without an error if the user cancelled.
+func (m *Menu) unlockBundle(bundle *backup.Bundle) (bool, error) {
+	// Entries in folders with their own encryption are restored as-is
+	first := ""
+	for _, name := range bundle.EntryNames() {
+		if !bundle.InFolderScope(name) {
+			first = name
+			break
+		}
+	}
+	if first == "" {
+		return true, nil
+	}
+	_, err := m.encryptionFunctions.DecryptEntry(first, bundle.Entries[first])
+	if err == nil {
+		return true, nil
+	}
+
+	if bundle.Init == nil {
+		return false, fmt.Errorf("backup entries use a different key and the backup has no key file")
+	}
+
+	backupPassword := ""
+	_, err = m.run(textinput.InitialModel("This backup is from another vault - enter that vault's Master password", "Password", &backupPassword, m.Options))
+	if err != nil {
+		return false, err
+	}
+	if m.Options.Quit {
+		// Escape cancels the restore rather than quitting the application
+		m.Options.Quit = false
+		return false, nil
+	}
+
+	keyFile, err := encryption.DecryptKeyFile(bundle.Init, []byte(backupPassword))
+	if errors.Is(err, encryption.ErrWrongPassword) {
+		return false, fmt.Errorf("incorrect master password for the backup vault")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = bundle.Reencrypt(keyFile.Keys(), m.encryptionFunctions)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
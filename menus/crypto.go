@@ -0,0 +1,156 @@
+package menus
+
+import (
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/ui/cryptoform"
+	"github.com/Fozzyack/password-manager/ui/progress"
+	"github.com/Fozzyack/password-manager/ui/textinput"
+)
+
+// EncryptionSettings lets the user change the store's encryption settings and then re-encrypts
+// the key file and every entry under them, showing progress as it goes.
+// Returns true if the settings were applied, false if cancelled.
+func (m *Menu) EncryptionSettings() (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	current, err := m.encryptionFunctions.CryptoConfig()
+	if err != nil {
+		return false, err
+	}
+
+	finalModel, err := m.run(cryptoform.NewCryptoForm(current, m.Options))
+	if err != nil {
+		return false, fmt.Errorf("error running encryption settings: %v", err)
+	}
+
+	formModel := finalModel.(cryptoform.CryptoFormModel)
+	if formModel.IsCancelled() || !formModel.IsSubmitted() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	// The key file is protected by the master password, so ask for it before changing anything
+	masterPassword := ""
+	_, err = m.run(textinput.InitialModel("Enter your Master password (or key passphrase) to re-encrypt the store", "Password", &masterPassword, m.Options))
+	if err != nil {
+		return false, err
+	}
+	if m.Options.Quit {
+		// Escape cancels the rekey rather than quitting the application
+		m.Options.Quit = false
+		return false, nil
+	}
+
+	err = m.encryptionFunctions.VerifyMasterPassword(masterPassword)
+	if err != nil {
+		return false, err
+	}
+
+	err = m.encryptionFunctions.SetCryptoConfig(formModel.GetConfig())
+	if err != nil {
+		return false, err
+	}
+
+	// Recipient vaults are opened with a private key, so their key file is not ours to re-wrap
+	recipientMode, err := m.encryptionFunctions.RecipientMode()
+	if err != nil {
+		return false, err
+	}
+	if !recipientMode {
+		err = m.encryptionFunctions.ReencryptFileWithPassword(encryption.InitFilename, []byte(masterPassword))
+		if err != nil {
+			return false, fmt.Errorf("failed to re-encrypt key file: %v", err)
+		}
+	}
+
+	filenames, locked, err := m.unlockedFilenames()
+	if err != nil {
+		return false, err
+	}
+
+	rekey := progress.NewProgress("🔐 Re-encrypting Entries", filenames, m.encryptionFunctions.ReencryptFile, m.Options)
+	finalProgressModel, err := m.run(rekey)
+	if err != nil {
+		return false, fmt.Errorf("error running re-encryption: %v", err)
+	}
+	err = m.encryptionFunctions.ReencryptTrash()
+	if err != nil {
+		return false, fmt.Errorf("failed to re-encrypt entries in the trash: %v", err)
+	}
+
+	m.commitChange("Change encryption settings")
+
+	progressModel := finalProgressModel.(progress.ProgressModel)
+	if remaining := len(filenames) - progressModel.Completed(); remaining > 0 {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("⚠️  Re-encryption stopped early: %d entries still use the old settings.\n\n", remaining)
+		fmt.Printf("Run Encryption Settings again to finish.\n\n")
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+	} else if locked > 0 {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("⚠️  %d entries in locked folders still use the old settings.\n\n", locked)
+		fmt.Printf("Open those folders and run Encryption Settings again to finish.\n\n")
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+	}
+	return true, nil
+}
+
+// unlockedFilenames returns the entries whose folders are unlocked, and how many were left out
+func (m *Menu) unlockedFilenames() ([]string, int, error) {
+	filenames, err := m.passwordFolder.ListEntryFilenames()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read password store: %v", err)
+	}
+
+	var unlocked []string
+	for _, filename := range filenames {
+		scope, err := m.encryptionFunctions.ScopeOf(filename)
+		if err != nil {
+			return nil, 0, err
+		}
+		if m.encryptionFunctions.ScopeUnlocked(scope) {
+			unlocked = append(unlocked, filename)
+		}
+	}
+	return unlocked, len(filenames) - len(unlocked), nil
+}
+
+// migrateKey upgrades a store from the version 1 phrase to a generated data key and
+// re-encrypts every entry with it. Entries stay readable throughout, so an interrupted
+// upgrade simply continues at the next login.
+func (m *Menu) migrateKey(masterPassword string) error {
+	err := m.encryptionFunctions.StartMigration(masterPassword)
+	if err != nil {
+		return err
+	}
+
+	filenames, err := m.passwordFolder.ListEntryFilenames()
+	if err != nil {
+		return fmt.Errorf("failed to read password store: %v", err)
+	}
+
+	upgrade := progress.NewProgress("🔑 Upgrading Store Key", filenames, m.encryptionFunctions.ReencryptFile, m.Options)
+	finalModel, err := m.run(upgrade)
+	if err != nil {
+		return err
+	}
+
+	progressModel := finalModel.(progress.ProgressModel)
+	if progressModel.Completed() < len(filenames) || len(progressModel.GetFailures()) > 0 {
+		return fmt.Errorf("%d of %d entries were not upgraded", len(filenames)-progressModel.Completed()+len(progressModel.GetFailures()), len(filenames))
+	}
+	err = m.encryptionFunctions.ReencryptTrash()
+	if err != nil {
+		return fmt.Errorf("failed to upgrade entries in the trash: %v", err)
+	}
+	err = m.encryptionFunctions.FinishMigration(masterPassword)
+	if err != nil {
+		return err
+	}
+	m.commitChange("Upgrade store key")
+	return nil
+}
@@ -0,0 +1,83 @@
+package menus
+
+import (
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	"github.com/Fozzyack/password-manager/ui/exportform"
+)
+
+// ExportPasswords displays the export form, warns that the output is plaintext and writes
+// every decrypted entry to the chosen file.
+// Returns true if the export was written, false if cancelled or failed.
+func (m *Menu) ExportPasswords() (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	// Ask for the format and destination
+	exportForm := exportform.NewExportForm(m.Options)
+	finalModel, err := m.run(exportForm)
+	if err != nil {
+		return false, fmt.Errorf("error running export form: %v", err)
+	}
+
+	formModel := finalModel.(exportform.ExportFormModel)
+	if formModel.IsCancelled() || !formModel.IsSubmitted() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	format := formModel.GetFormat()
+	path := export.ExpandPath(formModel.GetPath())
+
+	// Protected folders must be unlocked for their entries to be exported
+	locked, err := export.LockedFolders(m.passwordFolder, m.encryptionFunctions)
+	if err != nil {
+		return false, err
+	}
+	for _, folder := range locked {
+		unlocked, err := m.UnlockFolder(folder)
+		if err != nil || !unlocked {
+			return false, err
+		}
+	}
+
+	// Decrypt everything up front so a bad entry is reported before anything is written
+	records, err := export.CollectRecords(m.passwordFolder, m.encryptionFunctions)
+	if err != nil {
+		return false, err
+	}
+
+	// Warn the user that the export will not be encrypted
+	confirmDialog := confirm.NewWarningDialog(
+		"export",
+		"The export file will contain every password in PLAINTEXT.\nAnyone who can read the file can read your passwords.",
+		fmt.Sprintf("Entries: %d\nFormat: %s\nFile: %s", len(records), format, path),
+		m.Options,
+	)
+	finalConfirmModel, err := m.run(confirmDialog)
+	if err != nil {
+		return false, fmt.Errorf("error running confirmation dialog: %v", err)
+	}
+
+	if !finalConfirmModel.(confirm.ConfirmModel).IsConfirmed() {
+		return false, nil
+	}
+
+	err = export.WriteRecords(path, format, records)
+	if err != nil {
+		return false, err
+	}
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Passwords exported successfully!\n\n")
+	fmt.Printf("Entries: %d\n", len(records))
+	fmt.Printf("File: %s\n\n", path)
+	fmt.Printf("⚠️  This file is NOT encrypted. Delete it once it is no longer needed.\n\n")
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
@@ -0,0 +1,74 @@
+package menus
+
+import (
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	"github.com/Fozzyack/password-manager/ui/history"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// ShowHistory lists the earlier versions of an entry and compares them with current. The
+// revision the user picks can be restored after confirmation; the version it replaces is
+// kept in the history. Returns true if a revision was restored, false if closed.
+func (m *Menu) ShowHistory(filename, siteName string, current encryption.Data) (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	saved, err := m.passwordFolder.ListRevisions(filename)
+	if err != nil {
+		return false, err
+	}
+	revisions := make([]history.Revision, 0, len(saved))
+	for _, revision := range saved {
+		entry, err := m.encryptionFunctions.DecryptRevision(filename, revision)
+		listed := history.Revision{SavedAt: revision.SavedAt, Entry: entry}
+		if err != nil {
+			listed.Err = "cannot be decrypted with the keys unlocked now"
+		}
+		revisions = append(revisions, listed)
+	}
+
+	historyView := history.NewHistoryView(siteName, current, revisions, m.Options)
+	finalModel, err := m.run(historyView)
+	if err != nil {
+		return false, fmt.Errorf("error running history view: %v", err)
+	}
+	historyModel := finalModel.(history.HistoryModel)
+	if !historyModel.IsRestoreRequested() {
+		return false, nil
+	}
+
+	selected := saved[historyModel.GetSelectedIndex()]
+	confirmDialog := confirm.NewWarningDialog(
+		"restore",
+		"Replace the current version of this entry with the earlier one?\nThe current version will be kept in the history.",
+		fmt.Sprintf("Site: %s\nReplaced: %s", siteName, utils.FormatTimestampForDisplay(selected.SavedAt)),
+		m.Options,
+	)
+	finalConfirmModel, err := m.run(confirmDialog)
+	if err != nil {
+		return false, fmt.Errorf("error running confirmation dialog: %v", err)
+	}
+	if !finalConfirmModel.(confirm.ConfirmModel).IsConfirmed() {
+		return false, nil
+	}
+
+	err = m.encryptionFunctions.RestoreRevision(filename, selected)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore earlier version: %v", err)
+	}
+	m.commitChange("Restore earlier version of %s", filename)
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Earlier version restored successfully!\n\n")
+	fmt.Printf("Site: %s\n", siteName)
+	fmt.Printf("File: %s.gpg\n\n", filename)
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
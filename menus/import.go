@@ -0,0 +1,96 @@
+package menus
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/Fozzyack/password-manager/importer"
+	"github.com/Fozzyack/password-manager/ui/importform"
+	"github.com/Fozzyack/password-manager/ui/list"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// ImportPasswords reads an export from another password manager, shows a preview with
+// duplicates flagged and writes the entries into the store once the user confirms.
+// Returns true if entries were imported, false if cancelled.
+func (m *Menu) ImportPasswords() (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	importForm := importform.NewImportForm(m.Options)
+	finalModel, err := m.run(importForm)
+	if err != nil {
+		return false, fmt.Errorf("error running import form: %v", err)
+	}
+
+	formModel := finalModel.(importform.ImportFormModel)
+	if formModel.IsCancelled() || !formModel.IsSubmitted() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	entries, err := importer.ParseFile(formModel.GetParser(), export.ExpandPath(formModel.GetPath()))
+	if err != nil {
+		return false, err
+	}
+
+	if len(entries) == 0 {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("No logins were found in the selected file.\n\n")
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+		return false, nil
+	}
+
+	// Flag entries that already exist in the store or repeat within the file
+	existingEntries, err := m.getAllPasswordEntries()
+	if err != nil {
+		return false, fmt.Errorf("failed to load password entries: %v", err)
+	}
+	existing := make(map[string]bool, len(existingEntries))
+	for _, entry := range existingEntries {
+		// Entries in sub-folders are compared by their own name, without the folder
+		name := path.Base(utils.StripFilenameTimestamp(entry.Filename))
+		existing[importer.DuplicateKey(name, entry.Username)] = true
+	}
+	importer.MarkDuplicates(entries, existing)
+
+	// Preview everything before committing
+	previewEntries := make([]list.PasswordEntry, len(entries))
+	for i, entry := range entries {
+		previewEntries[i] = list.PasswordEntry{
+			SiteName:  entry.SiteName,
+			Username:  entry.Data.Username,
+			Email:     entry.Data.Email,
+			CreatedAt: entry.Data.CreatedAt,
+			Duplicate: entry.Duplicate,
+		}
+	}
+
+	previewList := list.NewPreviewList(previewEntries, "📥 Import Preview", m.Options)
+	finalPreviewModel, err := m.run(previewList)
+	if err != nil {
+		return false, fmt.Errorf("error running import preview: %v", err)
+	}
+
+	previewModel := finalPreviewModel.(list.ListModel)
+	if !previewModel.IsConfirmed() {
+		return false, nil
+	}
+
+	count, err := importer.Commit(m.passwordFolder, m.encryptionFunctions, entries, previewModel.IsSkippingDuplicates())
+	if err != nil {
+		return false, fmt.Errorf("import stopped after %d entries: %v", count, err)
+	}
+	m.commitChange("Import %d entries", count)
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Passwords imported successfully!\n\n")
+	fmt.Printf("Imported: %d of %d entries\n\n", count, len(entries))
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
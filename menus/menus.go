@@ -3,11 +3,17 @@
 package menus
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 	"time"
+	"github.com/Fozzyack/password-manager/clipboard"
+	"github.com/Fozzyack/password-manager/config"
 	"github.com/Fozzyack/password-manager/encryption"
 	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/otp"
+	"github.com/Fozzyack/password-manager/session"
+	"github.com/Fozzyack/password-manager/templates"
+	"github.com/Fozzyack/password-manager/throttle"
 	"github.com/Fozzyack/password-manager/types"
 	"github.com/Fozzyack/password-manager/ui/textinput"
 	"github.com/Fozzyack/password-manager/ui/menu"
@@ -16,6 +22,7 @@ import (
 	"github.com/Fozzyack/password-manager/ui/detail"
 	"github.com/Fozzyack/password-manager/ui/confirm"
 	"github.com/Fozzyack/password-manager/ui/change"
+	"github.com/Fozzyack/password-manager/ui/entrytype"
 	"github.com/Fozzyack/password-manager/utils"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -27,17 +34,60 @@ import (
 type Menu struct {
 	passwordFolder      *fileio.PasswordFolder
 	encryptionFunctions *encryption.EncryptionFunctions
+	config              *config.Config
+	locker              *session.Locker
+	lockNotice          string // Shown on the login screen after the session locked itself
+	failedAttempts      int    // Wrong master passwords entered at the current login screen
 	Options             *types.Options
 }
 
-func InitMenus(pf *fileio.PasswordFolder, ef *encryption.EncryptionFunctions, options *types.Options) *Menu {
+func InitMenus(pf *fileio.PasswordFolder, ef *encryption.EncryptionFunctions, cfg *config.Config, locker *session.Locker, options *types.Options) *Menu {
+	// Screens that wait on the terminal rather than a program cannot notice the timeout, so
+	// the key is wiped in the background and the main loop logs out once they return
+	locker.OnExpire(func() {
+		clipboard.Default().Clear()
+		ef.Lock()
+	})
 	return &Menu{
 		passwordFolder:      pf,
 		encryptionFunctions: ef,
+		config:              cfg,
+		locker:              locker,
 		Options:             options,
 	}
 }
 
+// run runs a screen as a Bubble Tea program under the session's idle timer. A clipboard clear
+// that would write to the terminal waits until the screen has closed.
+// Returns session.ErrLocked if the session locked before or while the screen was open.
+func (m *Menu) run(model tea.Model) (tea.Model, error) {
+	clipboard.Default().Pause()
+	defer clipboard.Default().Resume()
+	return m.locker.Run(model)
+}
+
+// IsLocked reports whether the session has locked, either manually or after the idle timeout
+func (m *Menu) IsLocked() bool {
+	return m.locker.IsLocked()
+}
+
+// Lock wipes the in-memory key, clears the clipboard and logs the user out so the main loop
+// returns to the login screen. It is used both by the "Lock Now" action and when the
+// session has been idle for too long.
+func (m *Menu) Lock() {
+	if !m.Options.LoggedIn {
+		return // Already locked
+	}
+	if !m.locker.IsLocked() {
+		m.locker.Lock()
+	} else if m.locker.Timeout() > 0 {
+		m.lockNotice = fmt.Sprintf("🔒 Locked after %s of inactivity", m.locker.Timeout())
+	}
+	clipboard.Default().Clear()
+	m.encryptionFunctions.Lock()
+	m.Options.LoggedIn = false
+}
+
 // validatePassword checks if password meets minimum length requirement
 func validatePassword(password string) (bool, string) {
 	const minPasswordLength = 8
@@ -47,23 +97,25 @@ func validatePassword(password string) (bool, string) {
 	return true, ""
 }
 
-// validatePhrase checks if phrase meets minimum length requirement
-func validatePhrase(phrase string) (bool, string) {
-	const minPhraseLength = 12
-	if len(phrase) < minPhraseLength {
-		return false, "Phrase must be at least 12 characters long"
-	}
-	return true, ""
-}
-
 func (menu *Menu) Login() (bool, error) {
-	// Clear any previous error message before showing login
-	menu.Options.ErrorMessage = ""
+	// Clear any previous error message before showing login, explaining an automatic lock if there was one
+	menu.Options.ErrorMessage = menu.lockNotice
+	menu.lockNotice = ""
 	
-	var err error
-	p := tea.NewProgram(textinput.InitialModelWithMasking("Welcome, please type in your Master password", "Password", &menu.passwordFolder.Password, menu.Options, false))
+	// Recipient vaults are opened with the local private key and need no master password of their own
+	recipientMode, err := menu.encryptionFunctions.RecipientMode()
+	if err != nil {
+		return false, err
+	}
+	p := tea.NewProgram(textinput.InitialModelWithMasking(menu.loginHeader("Welcome, please type in your Master password"), "Password", &menu.passwordFolder.Password, menu.Options, false))
+
+	if !menu.passwordFolder.InitCheck && !recipientMode {
+		// The instance holding the lock is setting the store up
+		err = menu.passwordFolder.CheckWritable()
+		if err != nil {
+			return false, err
+		}
 
-	if !menu.passwordFolder.InitCheck {
 		// Validate master password (visible during setup)
 		for {
 			_, err = p.Run()
@@ -82,71 +134,145 @@ func (menu *Menu) Login() (bool, error) {
 			// Show validation error and prompt again
 			menu.Options.ErrorMessage = errorMsg
 			menu.passwordFolder.Password = "" // Clear invalid password
-			p = tea.NewProgram(textinput.InitialModelWithMasking("Welcome, please type in your Master password", "Password", &menu.passwordFolder.Password, menu.Options, false))
+			p = tea.NewProgram(textinput.InitialModelWithMasking(menu.loginHeader("Welcome, please type in your Master password"), "Password", &menu.passwordFolder.Password, menu.Options, false))
 		}
 		
-		// Validate phrase
-		phrase := ""
-		for {
-			menu.Options.ErrorMessage = "" // Clear previous errors
-			p = tea.NewProgram(textinput.InitialModel("Type in a Random phrase", "the quick brown fox...", &phrase, menu.Options))
-			_, err = p.Run()
-			if err != nil {
-				return false, err
-			}
-			if menu.Options.Quit {
-				return false, nil
-			}
-			
-			valid, errorMsg := validatePhrase(phrase)
-			if valid {
-				break
-			}
-			
-			// Show validation error
-			menu.Options.ErrorMessage = errorMsg
-			phrase = "" // Clear invalid phrase
-		}
-		menu.passwordFolder.InitCheck = false
-		data := encryption.Data{
-			Password:  phrase,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-		err = menu.encryptionFunctions.EncryptPasswordAndWriteToFile(".checker/init", data)
+		// Generate the data key and wrap it with the new master password
+		err = menu.encryptionFunctions.InitStore(menu.passwordFolder.Password)
 		if err != nil {
 			return false, err
 		}
-		menu.passwordFolder.InitCheck = true
-		menu.passwordFolder.Password = ""
+		menu.encryptionFunctions.Lock()
 	}
 	
-	p = tea.NewProgram(textinput.InitialModel("Hello Again! Please enter your Password", "Password", &menu.passwordFolder.Password, menu.Options))
+	// Refuse logins once the store is locked out and make repeated guesses wait
+	attempts, err := throttle.Load(menu.passwordFolder.FolderLocation, menu.config.MaxLoginAttempts)
+	if err != nil {
+		return false, err
+	}
+	if attempts.LockedOut() {
+		return false, attempts.LockoutError()
+	}
+	waitForBackoff(attempts.Delay())
+
+	prompt := "Hello Again! Please enter your Password"
+	if recipientMode {
+		prompt = "Hello Again! Please enter your private key passphrase"
+	}
+	p = tea.NewProgram(textinput.InitialModel(menu.loginHeader(prompt), "Password", &menu.passwordFolder.Password, menu.Options))
 	_, err = p.Run(); if err != nil {
 		return false, err
 	}
-	data, err := menu.encryptionFunctions.DecryptPasswordFromFile(".checker/init")
+	if menu.Options.Quit {
+		return false, nil
+	}
+	masterPassword := menu.passwordFolder.Password
+	err = menu.encryptionFunctions.Unlock(masterPassword)
+	if err != nil && !errors.Is(err, encryption.ErrWrongPassword) {
+		return false, err
+	}
 	if err != nil {
+		menu.failedAttempts++
+		err = attempts.RecordFailure()
+		if err != nil {
+			return false, err
+		}
+		if attempts.LockedOut() {
+			return false, attempts.LockoutError()
+		}
 		return false, nil
 	}
-	menu.passwordFolder.Password = data.Password
+
+	// Let the user know about failures other than their own typos at this login
+	others := attempts.Failures - menu.failedAttempts
+	recent := attempts.Recent[:max(len(attempts.Recent)-menu.failedAttempts, 0)]
+	menu.failedAttempts = 0
+	err = attempts.RecordSuccess()
+	if err != nil {
+		return false, err
+	}
+	if others > 0 {
+		showFailedAttempts(others, recent)
+	}
+
+	menu.locker.Reset()
+
+	// Stores created before data keys existed are upgraded on their first writable login
+	if menu.encryptionFunctions.NeedsMigration() && !menu.passwordFolder.ReadOnly {
+		err = menu.migrateKey(masterPassword)
+		if err != nil && !menu.IsLocked() {
+			fmt.Print("\033[2J\033[H") // Clear screen
+			fmt.Printf("❌ Error upgrading store key: %v\n\n", err)
+			fmt.Printf("Your entries are still readable. The upgrade will be retried at the next login.\n\n")
+			fmt.Println("Press Enter to continue...")
+			fmt.Scanln()
+		}
+	}
+
+	// Entries deleted longer ago than the retention period are gone for good
+	purged, err := menu.passwordFolder.PurgeExpiredTrash(menu.config.TrashRetentionPeriod())
+	if err != nil {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("⚠️  Could not empty expired entries from the trash: %v\n\n", err)
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+	}
+	if purged > 0 {
+		menu.commitChange("Purge %d expired entries from the trash", purged)
+	}
 	return true, nil
 }
 
+// waitForBackoff shows a countdown until the next login attempt is allowed
+func waitForBackoff(delay time.Duration) {
+	for delay > 0 {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("\033[91m\033[1mToo many failed login attempts\033[0m\n\n")
+		fmt.Printf("Please wait %s before trying again...\n", delay.Round(time.Second))
+		step := min(delay, time.Second)
+		time.Sleep(step)
+		delay -= step
+	}
+}
+
+// showFailedAttempts warns that someone entered the wrong master password before this login
+func showFailedAttempts(count int, times []time.Time) {
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("⚠️  %d failed login attempt(s) since your last login\n\n", count)
+	for _, t := range times {
+		fmt.Printf("  • %s\n", utils.FormatTimestampForDisplay(t))
+	}
+	if count > len(times) {
+		fmt.Printf("  • ...and %d earlier\n", count-len(times))
+	}
+	fmt.Printf("\nIf this wasn't you, consider changing your master password.\n\n")
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+}
+
+// loginHeader adds the vault name to a login prompt when a named vault other than the default is open
+func (menu *Menu) loginHeader(header string) string {
+	if menu.Options.Vault == "" || menu.Options.Vault == config.DefaultVault {
+		return header
+	}
+	return fmt.Sprintf("%s\nVault: %s", header, menu.Options.Vault)
+}
+
 // ShowMainMenu displays the main menu and handles user selection.
 // Returns the selected action string and any error that occurred.
 func (m *Menu) ShowMainMenu() (string, error) {
 	// Clear any previous error messages
 	m.Options.ErrorMessage = ""
+
+	// A read-only store becomes writable once the other instance has exited
+	m.retryStoreLock()
 	
 	// Clear screen before showing menu
 	fmt.Print("\033[2J\033[H")
 	
 	// Create and run the main menu
 	mainMenu := menu.InitialMenuModel(m.Options)
-	p := tea.NewProgram(mainMenu)
-	
-	finalModel, err := p.Run()
+	finalModel, err := m.run(mainMenu)
 	if err != nil {
 		return "", err
 	}
@@ -167,11 +293,20 @@ func (m *Menu) AddNewPassword() (bool, error) {
 	// Clear any previous error messages
 	m.Options.ErrorMessage = ""
 	
-	// Create and run the password form
-	passwordForm := form.NewPasswordForm(m.Options)
-	p := tea.NewProgram(passwordForm)
+	// Choose the kind of entry, which decides the fields of the form
+	finalModel, err := m.run(entrytype.NewEntryTypePicker(m.Options))
+	if err != nil {
+		return false, fmt.Errorf("error running entry type picker: %v", err)
+	}
+	picker := finalModel.(entrytype.EntryTypeModel)
+	if !picker.IsSelected() {
+		return false, nil // Not an error, just cancelled
+	}
+	template := picker.GetSelectedTemplate()
 	
-	finalModel, err := p.Run()
+	// Create and run the password form
+	passwordForm := form.NewTemplateForm(template, m.Options)
+	finalModel, err = m.run(passwordForm)
 	if err != nil {
 		return false, fmt.Errorf("error running form: %v", err)
 	}
@@ -193,41 +328,73 @@ func (m *Menu) AddNewPassword() (bool, error) {
 	
 	// Sanitize inputs
 	siteName := utils.SanitizeInput(formData["site_service_name"])
+	folder := utils.CleanFolderPath(utils.SanitizeInput(formData["folder"]))
 	username := utils.SanitizeInput(formData["username"])
 	email := utils.SanitizeInput(formData["email"])
 	url := utils.SanitizeInput(formData["url"])
 	password := formData["password"] // Don't sanitize password to preserve special chars
 	
 	// Validate required fields
-	if siteName == "" || password == "" {
+	if siteName == "" || (password == "" && requiresPassword(template)) {
 		m.Options.ErrorMessage = "Site name and password are required"
 		return false, nil
 	}
+
+	// 2FA secrets are stored as otpauth URIs whichever way they were entered
+	otpKey, err := otp.Normalize(formData["2fa_secret"])
+	if err != nil {
+		return false, err
+	}
 	
 	// Create password entry
 	now := time.Now()
 	passwordEntry := encryption.Data{
+		Type:      template.Type,
 		Password:  password,
 		Username:  username,
 		Email:     email,
 		URL:       url,
+		OTP:       otpKey,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	passwordEntry.Fields = formModel.GetCustomFields()
+	passwordEntry.Attachments = formModel.GetAttachments()
+	passwordEntry.Tags = formModel.GetTags()
+	passwordEntry.Favourite = formModel.IsFavourite()
 	
-	// Generate filename
+	// Generate filename, inside the folder if one was given
 	filename := utils.GenerateFilename(siteName)
+	if folder != "" {
+		filename = folder + "/" + filename
+
+		// Entries can only be added to folders whose key is unlocked
+		unlocked, err := m.UnlockFolder(folder)
+		if err != nil || !unlocked {
+			return false, err
+		}
+	}
+	filename = m.passwordFolder.UniqueFilename(filename)
 	
 	// Encrypt and save
 	err = m.encryptionFunctions.EncryptPasswordAndWriteToFile(filename, passwordEntry)
 	if err != nil {
 		return false, fmt.Errorf("failed to save password: %v", err)
 	}
+	m.commitChange("Add %s", filename)
 	
 	// Show success message
 	fmt.Print("\033[2J\033[H") // Clear screen
-	fmt.Printf("✅ Password saved successfully!\n\n")
-	fmt.Printf("Site: %s\n", siteName)
+	if template.Type == encryption.EntryLogin {
+		fmt.Printf("✅ Password saved successfully!\n\n")
+		fmt.Printf("Site: %s\n", siteName)
+	} else {
+		fmt.Printf("✅ %s saved successfully!\n\n", template.Name)
+		fmt.Printf("%s: %s\n", template.NameLabel, siteName)
+	}
+	if folder != "" {
+		fmt.Printf("Folder: %s\n", folder)
+	}
 	if username != "" {
 		fmt.Printf("Username: %s\n", username)
 	}
@@ -237,6 +404,21 @@ func (m *Menu) AddNewPassword() (bool, error) {
 	if url != "" {
 		fmt.Printf("URL: %s\n", url)
 	}
+	if len(passwordEntry.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", utils.FormatTags(passwordEntry.Tags))
+	}
+	if passwordEntry.Favourite {
+		fmt.Printf("⭐ Favourite\n")
+	}
+	if otpKey != "" {
+		fmt.Printf("2FA: codes are shown with the entry\n")
+	}
+	if len(passwordEntry.Fields) > 0 {
+		fmt.Printf("Custom fields: %d\n", len(passwordEntry.Fields))
+	}
+	if len(passwordEntry.Attachments) > 0 {
+		fmt.Printf("Attachments: %d\n", len(passwordEntry.Attachments))
+	}
 	fmt.Printf("File: %s.gpg\n\n", filename)
 	
 	fmt.Println("Press Enter to continue...")
@@ -251,31 +433,86 @@ func (m *Menu) ListAllPasswords() (bool, error) {
 	// Clear any previous error messages
 	m.Options.ErrorMessage = ""
 	
-	// Get all password entries
-	entries, err := m.getAllPasswordEntries()
+	// Get all password entries and folders
+	entries, folders, err := m.loadPasswordTree()
 	if err != nil {
 		return false, fmt.Errorf("failed to load password entries: %v", err)
 	}
 	
 	// If no passwords exist, show empty state and return
-	if len(entries) == 0 {
+	if len(entries) == 0 && len(folders) == 0 {
 		passwordList := list.NewPasswordList(entries, m.Options)
-		p := tea.NewProgram(passwordList)
-		_, err := p.Run()
+		_, err := m.run(passwordList)
 		return false, err
 	}
 	
-	// Show the password list
+	// Show the password list, starting at the root folder in the order last chosen
+	folder := ""
+	order := list.ParseSortOrder(m.config.ListSort)
 	for {
-		passwordList := list.NewPasswordList(entries, m.Options)
-		p := tea.NewProgram(passwordList)
-		
-		finalModel, err := p.Run()
+		passwordList := list.NewFolderList(entries, folders, folder, order, m.Options)
+		finalModel, err := m.run(passwordList)
 		if err != nil {
 			return false, fmt.Errorf("error running password list: %v", err)
 		}
 		
 		listModel := finalModel.(list.ListModel)
+		folder = listModel.GetFolder()
+
+		// Remember a new sort order for the next time the list is opened
+		if listModel.GetSortOrder() != order {
+			order = listModel.GetSortOrder()
+			m.config.ListSort = string(order)
+			err = m.config.Save()
+			if err != nil {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("❌ Error saving sort order: %v\n\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+			}
+		}
+		
+		// Open a locked folder once it has been unlocked
+		if locked := listModel.GetUnlockFolder(); locked != "" {
+			unlocked, err := m.UnlockFolder(locked)
+			if err != nil && m.IsLocked() {
+				return false, err
+			}
+			if err != nil {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("❌ Error unlocking folder: %v\n\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+				continue // Return to list
+			}
+			if unlocked {
+				folder = locked
+				entries, folders, err = m.loadPasswordTree()
+				if err != nil {
+					return false, fmt.Errorf("failed to reload password entries after unlocking: %v", err)
+				}
+			}
+			continue
+		}
+		
+		// Change the encryption of the current folder
+		if listModel.IsSettingsRequested() {
+			_, err = m.ManageFolder(folder)
+			if err != nil && m.IsLocked() {
+				return false, err
+			}
+			if err != nil {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("❌ Error changing folder encryption: %v\n\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+			}
+			entries, folders, err = m.loadPasswordTree()
+			if err != nil {
+				return false, fmt.Errorf("failed to reload password entries: %v", err)
+			}
+			continue
+		}
 		
 		// Check if user selected an entry
 		if !listModel.IsSelected() {
@@ -295,12 +532,20 @@ func (m *Menu) ListAllPasswords() (bool, error) {
 			fmt.Scanln()
 			continue // Go back to the list
 		}
+
+		// Note when the entry was opened for sorting by recent use. The list only loses some
+		// of its order if this fails, so it is not worth interrupting the user over.
+		if m.passwordFolder.RecordUsage(selectedEntry.Filename) == nil {
+			for i := range entries {
+				if entries[i].Filename == selectedEntry.Filename {
+					entries[i].LastUsed = time.Now()
+				}
+			}
+		}
 		
 		// Show password details
 		detailView := detail.NewPasswordDetail(passwordData, selectedEntry.Filename, selectedEntry.SiteName, m.Options)
-		detailProgram := tea.NewProgram(detailView)
-		
-		finalDetailModel, err := detailProgram.Run()
+		finalDetailModel, err := m.run(detailView)
 		if err != nil {
 			return false, fmt.Errorf("error running password detail view: %v", err)
 		}
@@ -310,10 +555,13 @@ func (m *Menu) ListAllPasswords() (bool, error) {
 		// Check if deletion was requested
 		if detailModel.IsDeletionRequested() {
 			// Show confirmation dialog
-			confirmDialog := confirm.NewConfirmDialog(selectedEntry.SiteName, selectedEntry.Filename, "delete", m.Options)
-			confirmProgram := tea.NewProgram(confirmDialog)
-			
-			finalConfirmModel, err := confirmProgram.Run()
+			confirmDialog := confirm.NewWarningDialog(
+				"delete",
+				"Move this password entry to the trash?\nIt can be restored from 🗑️ Trash until it is purged.",
+				fmt.Sprintf("Site: %s\nFile: %s.gpg", selectedEntry.SiteName, selectedEntry.Filename),
+				m.Options,
+			)
+			finalConfirmModel, err := m.run(confirmDialog)
 			if err != nil {
 				return false, fmt.Errorf("error running confirmation dialog: %v", err)
 			}
@@ -322,8 +570,8 @@ func (m *Menu) ListAllPasswords() (bool, error) {
 			
 			// Check if deletion was confirmed
 			if confirmModel.IsConfirmed() {
-				// Delete the file
-				err = m.passwordFolder.DeleteFile(selectedEntry.Filename)
+				// Move the entry and its history to the trash
+				err = m.passwordFolder.TrashFile(selectedEntry.Filename)
 				if err != nil {
 					fmt.Print("\033[2J\033[H") // Clear screen
 					fmt.Printf("❌ Error deleting password: %v\n\n", err)
@@ -331,7 +579,8 @@ func (m *Menu) ListAllPasswords() (bool, error) {
 					fmt.Scanln()
 					continue // Return to list
 				}
-				
+				m.commitChange("Delete %s", selectedEntry.Filename)
+
 				// Refresh directory listing and entries after deletion
 				err = m.passwordFolder.RefreshDirectoryListing()
 				if err != nil {
@@ -343,15 +592,20 @@ func (m *Menu) ListAllPasswords() (bool, error) {
 				}
 				
 				// Get updated entries
-				entries, err = m.getAllPasswordEntries()
+				entries, folders, err = m.loadPasswordTree()
 				if err != nil {
 					return false, fmt.Errorf("failed to reload password entries after deletion: %v", err)
 				}
 				
 				// Show success message
 				fmt.Print("\033[2J\033[H") // Clear screen
-				fmt.Printf("✅ Password deleted successfully!\n\n")
-				fmt.Printf("Deleted: %s (%s.gpg)\n\n", selectedEntry.SiteName, selectedEntry.Filename)
+				fmt.Printf("✅ Password moved to the trash!\n\n")
+				fmt.Printf("Deleted: %s (%s.gpg)\n", selectedEntry.SiteName, selectedEntry.Filename)
+				if retention := m.config.TrashRetentionPeriod(); retention > 0 {
+					fmt.Printf("It can be restored from 🗑️ Trash for %d days.\n\n", int(retention.Hours()/24))
+				} else {
+					fmt.Printf("It can be restored from 🗑️ Trash.\n\n")
+				}
 				fmt.Println("Press Enter to continue...")
 				fmt.Scanln()
 				
@@ -362,41 +616,200 @@ func (m *Menu) ListAllPasswords() (bool, error) {
 			continue
 		}
 		
+		// Check if editing was requested
+		if detailModel.IsEditRequested() {
+			_, err = m.EditPassword(selectedEntry.Filename, selectedEntry.SiteName, passwordData)
+			if err != nil && m.IsLocked() {
+				return false, err
+			}
+			if err != nil {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("❌ Error editing password: %v\n\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+				continue // Return to list
+			}
+
+			// Get updated entries
+			entries, folders, err = m.loadPasswordTree()
+			if err != nil {
+				return false, fmt.Errorf("failed to reload password entries after editing: %v", err)
+			}
+			continue
+		}
+
+		// Move an HOTP key on to its next code
+		if detailModel.IsNextCodeRequested() {
+			_, err = m.NextOTPCode(selectedEntry.Filename, selectedEntry.SiteName, passwordData)
+			if err != nil && m.IsLocked() {
+				return false, err
+			}
+			if err != nil {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("❌ Error generating 2FA code: %v\n\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+			}
+			continue
+		}
+
+		// Save an attachment to a file
+		if detailModel.IsSaveRequested() {
+			_, err = m.SaveAttachment(passwordData.Attachments[detailModel.GetSelectedAttachment()])
+			if err != nil && m.IsLocked() {
+				return false, err
+			}
+			if err != nil {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("❌ Error saving attachment: %v\n\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+			}
+			continue
+		}
+
+		// Check if the entry's history was requested
+		if detailModel.IsHistoryRequested() {
+			_, err = m.ShowHistory(selectedEntry.Filename, selectedEntry.SiteName, passwordData)
+			if err != nil && m.IsLocked() {
+				return false, err
+			}
+			if err != nil {
+				fmt.Print("\033[2J\033[H") // Clear screen
+				fmt.Printf("❌ Error showing history: %v\n\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+				continue // Return to list
+			}
+
+			// Get updated entries
+			entries, folders, err = m.loadPasswordTree()
+			if err != nil {
+				return false, fmt.Errorf("failed to reload password entries after restoring: %v", err)
+			}
+			continue
+		}
+
 		// After viewing details without deletion, return to the list (continue the loop)
 		// User can press Esc from the list to exit completely
 	}
 }
 
-// getAllPasswordEntries retrieves and decrypts all password entries from the store
+// EditPassword displays the password form pre-populated with an existing entry and re-encrypts
+// the updated entry to the same filename. CreatedAt is preserved and UpdatedAt is bumped.
+// Returns true if the entry was updated, false if cancelled.
+func (m *Menu) EditPassword(filename, siteName string, existing encryption.Data) (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	// Create and run the pre-populated form
+	passwordForm := form.NewEditPasswordForm(siteName, encryption.ParentFolder(filename), existing, m.Options)
+	finalModel, err := m.run(passwordForm)
+	if err != nil {
+		return false, fmt.Errorf("error running form: %v", err)
+	}
+
+	formModel := finalModel.(form.FormModel)
+
+	// Check if form was cancelled or not completed
+	if formModel.IsCancelled() || !formModel.IsSubmitted() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	// Get form data
+	formData := formModel.GetFormData()
+	password := formData["password"] // Don't sanitize password to preserve special chars
+	if password == "" && requiresPassword(templates.For(existing.Type)) {
+		m.Options.ErrorMessage = "Password is required"
+		return false, nil
+	}
+
+	otpKey, err := otp.Normalize(formData["2fa_secret"])
+	if err != nil {
+		return false, err
+	}
+
+	// Update the entry, keeping the original creation time
+	updated := existing
+	updated.Username = utils.SanitizeInput(formData["username"])
+	updated.Email = utils.SanitizeInput(formData["email"])
+	updated.URL = utils.SanitizeInput(formData["url"])
+	updated.Password = password
+	updated.OTP = otpKey
+	updated.Fields = formModel.GetCustomFields()
+	updated.Attachments = formModel.GetAttachments()
+	updated.Tags = formModel.GetTags()
+	updated.Favourite = formModel.IsFavourite()
+	updated.UpdatedAt = time.Now()
+
+	// Encrypt and save over the existing file
+	err = m.encryptionFunctions.EncryptPasswordAndWriteToFile(filename, updated)
+	if err != nil {
+		return false, fmt.Errorf("failed to save password: %v", err)
+	}
+	m.commitChange("Edit %s", filename)
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Password updated successfully!\n\n")
+	fmt.Printf("Site: %s\n", siteName)
+	fmt.Printf("File: %s.gpg\n\n", filename)
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
+
+// requiresPassword returns whether entries of a template must have a password
+func requiresPassword(template templates.Template) bool {
+	field, ok := template.Standard(templates.KeyPassword)
+	return ok && field.Required
+}
+
+// loadPasswordTree retrieves the password entries and the folders of the store
+func (m *Menu) loadPasswordTree() ([]list.PasswordEntry, []list.Folder, error) {
+	entries, err := m.getAllPasswordEntries()
+	if err != nil {
+		return nil, nil, err
+	}
+	folders, err := m.getFolders()
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, folders, nil
+}
+
+// getAllPasswordEntries retrieves and decrypts all password entries from the store,
+// including sub-folders. Entries in folders that are still locked are left out.
 func (m *Menu) getAllPasswordEntries() ([]list.PasswordEntry, error) {
 	var entries []list.PasswordEntry
 	
-	// Refresh directory listing to ensure we have the latest files
-	err := m.passwordFolder.RefreshDirectoryListing()
+	// List every entry in the store and its sub-folders, skipping .checker
+	filenames, err := m.passwordFolder.ListEntryFilenames()
 	if err != nil {
 		return nil, fmt.Errorf("failed to refresh directory listing: %v", err)
 	}
 	
-	// Iterate through all files in the password store
-	for _, dirEntry := range m.passwordFolder.Dirs {
-		// Skip directories and non-GPG files
-		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), ".gpg") {
-			continue
-		}
-		
-		// Skip the init.gpg file used for validation
-		if dirEntry.Name() == "init.gpg" {
-			continue
+	// When each entry was last opened, which only orders the list, so a damaged record is ignored
+	usage, err := m.passwordFolder.LoadUsage()
+	if err != nil {
+		usage = nil
+	}
+	
+	unlocked := make(map[string]bool) // Whether each folder can be read
+	for _, filename := range filenames {
+		folder := encryption.ParentFolder(filename)
+		readable, ok := unlocked[folder]
+		if !ok {
+			scope, err := m.encryptionFunctions.FolderScope(folder)
+			readable = err == nil && m.encryptionFunctions.ScopeUnlocked(scope)
+			unlocked[folder] = readable
 		}
-		
-		// Skip files in .checker directory
-		if strings.Contains(dirEntry.Name(), ".checker") {
+		if !readable {
 			continue
 		}
 		
-		// Get filename without .gpg extension
-		filename := strings.TrimSuffix(dirEntry.Name(), ".gpg")
-		
 		// Try to decrypt the entry to get its details
 		passwordData, err := m.encryptionFunctions.DecryptPasswordFromFile(filename)
 		if err != nil {
@@ -410,11 +823,18 @@ func (m *Menu) getAllPasswordEntries() ([]list.PasswordEntry, error) {
 		
 		// Create list entry
 		entry := list.PasswordEntry{
+			Type:      passwordData.Type,
 			Filename:  filename,
+			Folder:    folder,
 			SiteName:  siteName,
 			Username:  passwordData.Username,
 			Email:     passwordData.Email,
+			URL:       passwordData.URL,
+			Tags:      passwordData.Tags,
+			Favourite: passwordData.Favourite,
 			CreatedAt: passwordData.CreatedAt,
+			UpdatedAt: passwordData.UpdatedAt,
+			LastUsed:  usage[filename],
 		}
 		
 		entries = append(entries, entry)
@@ -423,6 +843,33 @@ func (m *Menu) getAllPasswordEntries() ([]list.PasswordEntry, error) {
 	return entries, nil
 }
 
+// getFolders lists the sub-folders of the store with whether each is locked and how it is encrypted
+func (m *Menu) getFolders() ([]list.Folder, error) {
+	paths, err := m.passwordFolder.ListFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	folders := make([]list.Folder, 0, len(paths))
+	for _, path := range paths {
+		scope, err := m.encryptionFunctions.FolderScope(path)
+		if err != nil {
+			return nil, err
+		}
+		folder := list.Folder{
+			Path:   path,
+			Locked: !m.encryptionFunctions.ScopeUnlocked(scope),
+		}
+		if scope.Folder == path && scope.Mode == encryption.RecipientScope {
+			folder.Note = "👥 shared"
+		} else if scope.Folder == path && scope.Mode == encryption.PassphraseScope {
+			folder.Note = "🔑 passphrase"
+		}
+		folders = append(folders, folder)
+	}
+	return folders, nil
+}
+
 // ChangeMasterPassword handles the master password change workflow.
 // Returns true if password was changed successfully, false if cancelled or failed.
 func (m *Menu) ChangeMasterPassword() (bool, error) {
@@ -431,9 +878,7 @@ func (m *Menu) ChangeMasterPassword() (bool, error) {
 
 	// Show the change password form
 	changeForm := change.NewChangePasswordForm(m.Options)
-	p := tea.NewProgram(changeForm)
-	
-	finalModel, err := p.Run()
+	finalModel, err := m.run(changeForm)
 	if err != nil {
 		return false, fmt.Errorf("error running change password form: %v", err)
 	}
@@ -453,33 +898,16 @@ func (m *Menu) ChangeMasterPassword() (bool, error) {
 	// Get form data
 	currentPass, newPass, _ := formModel.GetFormData()
 	
-	// Step 1: Verify current password by trying to decrypt init.gpg
-	oldPassword := m.passwordFolder.Password // Store original password
-	m.passwordFolder.Password = currentPass  // Temporarily set to verify
-	
-	validationData, err := m.encryptionFunctions.DecryptPasswordFromFile(".checker/init")
-	if err != nil {
-		// Restore original password
-		m.passwordFolder.Password = oldPassword
-		
+	// Re-wrap the data key with the new master password; entries keep their encryption
+	err = m.encryptionFunctions.ChangeMasterPassword(currentPass, newPass)
+	if errors.Is(err, encryption.ErrWrongPassword) {
 		fmt.Print("\033[2J\033[H") // Clear screen
 		fmt.Printf("❌ Error: Current password is incorrect\n\n")
 		fmt.Println("Press Enter to continue...")
 		fmt.Scanln()
 		return false, nil
 	}
-	
-	// Step 2: Re-encrypt the validation data with new master password
-	m.passwordFolder.Password = newPass // Set new password for encryption
-	
-	// Update the timestamp to reflect the password change
-	validationData.UpdatedAt = time.Now()
-	
-	err = m.encryptionFunctions.EncryptPasswordAndWriteToFile(".checker/init", validationData)
 	if err != nil {
-		// Restore original password on failure
-		m.passwordFolder.Password = oldPassword
-		
 		fmt.Print("\033[2J\033[H") // Clear screen
 		fmt.Printf("❌ Error saving new master password: %v\n\n", err)
 		fmt.Println("Press Enter to continue...")
@@ -487,27 +915,9 @@ func (m *Menu) ChangeMasterPassword() (bool, error) {
 		return false, nil
 	}
 	
-	// Step 3: Test that we can decrypt with the new password
-	testData, err := m.encryptionFunctions.DecryptPasswordFromFile(".checker/init")
-	if err != nil {
-		// This shouldn't happen, but if it does, we're in trouble
-		fmt.Print("\033[2J\033[H") // Clear screen
-		fmt.Printf("❌ Critical Error: Cannot decrypt with new password. Please check your password store manually.\n\n")
-		fmt.Println("Press Enter to continue...")
-		fmt.Scanln()
-		return false, fmt.Errorf("critical error: new password verification failed: %v", err)
-	}
-	
-	// Verify the validation phrase is still correct
-	if testData.Password != validationData.Password {
-		fmt.Print("\033[2J\033[H") // Clear screen
-		fmt.Printf("❌ Critical Error: Validation data corrupted during password change.\n\n")
-		fmt.Println("Press Enter to continue...")
-		fmt.Scanln()
-		return false, fmt.Errorf("validation data integrity check failed")
-	}
+	m.commitChange("Change master password")
 	
-	// Step 4: Success! Show confirmation message
+	// Success! Show confirmation message
 	fmt.Print("\033[2J\033[H") // Clear screen
 	fmt.Printf("✅ Master password changed successfully!\n\n")
 	fmt.Printf("Your new master password is now active.\n")
@@ -515,7 +925,7 @@ func (m *Menu) ChangeMasterPassword() (bool, error) {
 	fmt.Println("Press Enter to continue...")
 	fmt.Scanln()
 	
-	// The new password is already set in m.passwordFolder.Password
+	// The data key in m.passwordFolder.Password is unchanged,
 	// so the current session continues to work normally
 	
 	return true, nil
@@ -0,0 +1,48 @@
+package menus
+
+import (
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/clipboard"
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/otp"
+)
+
+// NextOTPCode generates the next code of an entry's HOTP key and shows it. The counter is
+// saved back to the entry before the code is shown, so a code is never given out twice.
+// Returns true if a code was generated.
+func (m *Menu) NextOTPCode(filename, siteName string, entry encryption.Data) (bool, error) {
+	key, err := otp.Parse(entry.OTP)
+	if err != nil {
+		return false, err
+	}
+	code, key, err := key.Next()
+	if err != nil {
+		return false, err
+	}
+
+	entry.OTP = key.URI()
+	err = m.encryptionFunctions.RewriteEntry(filename, entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to save the HOTP counter: %v", err)
+	}
+	m.commitChange("Advance HOTP counter of %s", filename)
+
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("🔑 2FA code for %s\n\n", siteName)
+	fmt.Printf("    %s\n\n", otp.Format(code))
+	fmt.Printf("Counter: %d\n", key.Counter-1)
+	manager := clipboard.Default()
+	if err := manager.Copy(code); err != nil {
+		fmt.Printf("❌ %v\n", err)
+	} else if manager.Timeout() > 0 {
+		fmt.Printf("📋 Copied - clipboard clears in %s\n", manager.Timeout())
+	} else {
+		fmt.Printf("📋 Copied\n")
+	}
+	fmt.Println()
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
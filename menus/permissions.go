@@ -0,0 +1,63 @@
+package menus
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxListedIssues is how many permission problems are listed in the warning before summarising
+const maxListedIssues = 5
+
+// CheckPermissions warns when files or directories in the store can be accessed by other
+// users and offers to restrict them to the owner. It runs whenever a store is opened, before
+// logging in, so it does not go through the session's idle timer.
+func (m *Menu) CheckPermissions() error {
+	issues, err := m.passwordFolder.CheckPermissions()
+	if err != nil || len(issues) == 0 {
+		return err
+	}
+
+	var details strings.Builder
+	for i, issue := range issues {
+		if i == maxListedIssues {
+			details.WriteString(fmt.Sprintf("...and %d more", len(issues)-maxListedIssues))
+			break
+		}
+		name, err := filepath.Rel(m.passwordFolder.FolderLocation, issue.Path)
+		if err != nil {
+			name = issue.Path
+		}
+		details.WriteString(fmt.Sprintf("%s  %04o → %04o\n", name, issue.Mode, issue.Want))
+	}
+
+	confirmDialog := confirm.NewWarningDialog(
+		"fix permissions",
+		fmt.Sprintf("%d files or folders in the password store can be accessed by other users.\nRestrict them to your user only?", len(issues)),
+		strings.TrimSuffix(details.String(), "\n"),
+		m.Options,
+	)
+	finalModel, err := tea.NewProgram(confirmDialog).Run()
+	if err != nil {
+		return fmt.Errorf("error running confirmation dialog: %v", err)
+	}
+	if !finalModel.(confirm.ConfirmModel).IsConfirmed() {
+		return nil
+	}
+
+	err = fileio.FixPermissions(issues)
+	if err != nil {
+		return err
+	}
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Permissions fixed for %d files and folders\n\n", len(issues))
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+	return nil
+}
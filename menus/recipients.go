@@ -0,0 +1,357 @@
+package menus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	"github.com/Fozzyack/password-manager/ui/progress"
+	"github.com/Fozzyack/password-manager/ui/recipients"
+	"github.com/Fozzyack/password-manager/ui/textinput"
+)
+
+// ManageRecipients shows the public keys the vault is encrypted to and lets the user add or
+// remove one. Every entry is then re-encrypted for the new set of recipients.
+// Returns true if the recipients changed, false if cancelled.
+func (m *Menu) ManageRecipients() (bool, error) {
+	return m.ManageFolder("")
+}
+
+// ManageFolder shows the encryption of a folder, empty for the whole vault. Recipients can be
+// added or removed, and a sub-folder can be given its own passphrase or made to inherit the
+// encryption of its parent. The entries in the folder's scope are then re-encrypted.
+// Returns true if the folder's encryption changed, false if cancelled.
+func (m *Menu) ManageFolder(folder string) (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	// Entries can only be re-encrypted once they can be read
+	unlocked, err := m.UnlockFolder(folder)
+	if err != nil || !unlocked {
+		return false, err
+	}
+
+	current, err := m.encryptionFunctions.Recipients(folder)
+	if err != nil {
+		return false, err
+	}
+	listed := make([]recipients.Recipient, 0, len(current))
+	for _, r := range current {
+		listed = append(listed, recipients.Recipient{Name: r.Name, Fingerprint: r.Fingerprint})
+	}
+	own := ""
+	if identity, err := m.encryptionFunctions.Identity(); err == nil {
+		own = identity.Fingerprint
+	}
+
+	screen := recipients.NewRecipientsScreen(listed, own, m.Options)
+	if folder != "" {
+		status, err := m.folderStatus(folder)
+		if err != nil {
+			return false, err
+		}
+		screen = recipients.NewFolderScreen(folder, status, listed, own, m.Options)
+	}
+	finalModel, err := m.run(screen)
+	if err != nil {
+		return false, fmt.Errorf("error running recipients screen: %v", err)
+	}
+	screen = finalModel.(recipients.RecipientsModel)
+	if screen.IsCancelled() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	// The entries to re-encrypt are the same before and after the change
+	filenames, err := m.encryptionFunctions.ScopeFilenames(folder)
+	if err != nil {
+		return false, err
+	}
+
+	var changed bool
+	if path := screen.GetAddPath(); path != "" {
+		changed, err = m.addRecipient(folder, export.ExpandPath(path), len(current) == 0)
+	} else if fingerprint := screen.GetRemoved(); fingerprint != "" {
+		changed, err = m.removeRecipient(folder, current, fingerprint)
+	} else if screen.IsPassphraseRequested() {
+		changed, err = m.setFolderPassphrase(folder)
+	} else if screen.IsInheritRequested() {
+		changed, err = m.inheritFolderEncryption(folder)
+	}
+	if err != nil || !changed {
+		return false, err
+	}
+	if folder == "" && len(current) == 0 {
+		err = m.shareVault(filenames)
+	} else {
+		err = m.reencryptEntries(filenames)
+	}
+	if err != nil {
+		return false, err
+	}
+	if folder == "" {
+		m.commitChange("Change encryption of the vault")
+	} else {
+		m.commitChange("Change encryption of %s", folder)
+	}
+	return true, nil
+}
+
+// UnlockFolder asks for the secret of a folder whose entries cannot be read yet: its own
+// passphrase, or the private key passphrase for folders shared with recipients.
+// Returns true if the folder is unlocked, false if cancelled.
+func (m *Menu) UnlockFolder(folder string) (bool, error) {
+	scope, err := m.encryptionFunctions.FolderScope(folder)
+	if err != nil {
+		return false, err
+	}
+	if m.encryptionFunctions.ScopeUnlocked(scope) {
+		return true, nil
+	}
+
+	header := fmt.Sprintf("📁 %s is protected - enter its passphrase", scope.Folder)
+	if scope.Mode == encryption.RecipientScope {
+		header = fmt.Sprintf("📁 %s is shared - enter the passphrase of your private key", scope.Folder)
+	}
+	secret := ""
+	_, err = m.run(textinput.InitialModel(header, "Passphrase", &secret, m.Options))
+	if err != nil {
+		return false, err
+	}
+	if m.Options.Quit {
+		// Escape cancels rather than quitting the application
+		m.Options.Quit = false
+		return false, nil
+	}
+
+	err = m.encryptionFunctions.UnlockFolder(folder, secret)
+	if errors.Is(err, encryption.ErrWrongPassword) {
+		return false, fmt.Errorf("incorrect passphrase for %s", scope.Folder)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// folderStatus describes how the entries in a sub-folder are encrypted
+func (m *Menu) folderStatus(folder string) (string, error) {
+	scope, err := m.encryptionFunctions.FolderScope(folder)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case scope.Folder == folder && scope.Mode == encryption.RecipientScope:
+		return "👥 Shared with its own recipients", nil
+	case scope.Folder == folder && scope.Mode == encryption.PassphraseScope:
+		return "🔑 Protected by its own passphrase", nil
+	case scope.Folder != "":
+		return fmt.Sprintf("Inherits the encryption of %s", scope.Folder), nil
+	}
+	return "Inherits the encryption of the vault", nil
+}
+
+// addRecipient adds the public key in path to a folder, empty for the vault. The first
+// recipient switches the folder to public keys, which needs the local private key unlocked first.
+func (m *Menu) addRecipient(folder string, path string, first bool) (bool, error) {
+	recipient, err := encryption.ReadPublicKey(path)
+	if err != nil {
+		return false, err
+	}
+
+	if first {
+		identity, err := m.encryptionFunctions.Identity()
+		if err != nil {
+			return false, err
+		}
+		message := "Every entry will be encrypted to public keys instead of the master password.\nYou will log in to this vault with your private key passphrase."
+		if folder != "" {
+			message = fmt.Sprintf("Entries in %s will be encrypted to public keys.\nYou will open the folder with your private key passphrase.", folder)
+		}
+		confirmDialog := confirm.NewWarningDialog(
+			"share",
+			message,
+			fmt.Sprintf("Your key: %s\nAdding: %s", identity.Name, recipient.Name),
+			m.Options,
+		)
+		finalModel, err := m.run(confirmDialog)
+		if err != nil {
+			return false, fmt.Errorf("error running confirmation dialog: %v", err)
+		}
+		if !finalModel.(confirm.ConfirmModel).IsConfirmed() {
+			return false, nil
+		}
+
+		if !m.encryptionFunctions.IdentityUnlocked() {
+			passphrase := ""
+			_, err = m.run(textinput.InitialModel("Enter the passphrase of your private key", "Passphrase", &passphrase, m.Options))
+			if err != nil {
+				return false, err
+			}
+			if m.Options.Quit {
+				// Escape cancels rather than quitting the application
+				m.Options.Quit = false
+				return false, nil
+			}
+			err = m.encryptionFunctions.UnlockIdentity(passphrase)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	err = m.encryptionFunctions.AddRecipient(folder, recipient)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// removeRecipient asks for confirmation and removes a recipient from a folder, empty for the vault
+func (m *Menu) removeRecipient(folder string, current []encryption.Recipient, fingerprint string) (bool, error) {
+	name := fingerprint
+	for _, r := range current {
+		if r.Fingerprint == fingerprint {
+			name = r.Name
+		}
+	}
+
+	confirmDialog := confirm.NewWarningDialog(
+		"remove",
+		"Entries will be re-encrypted without this key.\nCopies they already made stay readable, so change any shared passwords.",
+		fmt.Sprintf("Recipient: %s\nFingerprint: %s", name, fingerprint),
+		m.Options,
+	)
+	finalModel, err := m.run(confirmDialog)
+	if err != nil {
+		return false, fmt.Errorf("error running confirmation dialog: %v", err)
+	}
+	if !finalModel.(confirm.ConfirmModel).IsConfirmed() {
+		return false, nil
+	}
+
+	err = m.encryptionFunctions.RemoveRecipient(folder, fingerprint)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// setFolderPassphrase asks for a new passphrase twice and protects the sub-folder with it
+func (m *Menu) setFolderPassphrase(folder string) (bool, error) {
+	passphrase := ""
+	_, err := m.run(textinput.InitialModel(fmt.Sprintf("Enter a new passphrase for %s", folder), "Passphrase", &passphrase, m.Options))
+	if err != nil {
+		return false, err
+	}
+	if m.Options.Quit {
+		// Escape cancels rather than quitting the application
+		m.Options.Quit = false
+		return false, nil
+	}
+	if valid, _ := validatePassword(passphrase); !valid {
+		return false, fmt.Errorf("folder passphrase must be at least 8 characters long")
+	}
+
+	confirmation := ""
+	_, err = m.run(textinput.InitialModel("Confirm the passphrase", "Passphrase", &confirmation, m.Options))
+	if err != nil {
+		return false, err
+	}
+	if m.Options.Quit {
+		m.Options.Quit = false
+		return false, nil
+	}
+	if confirmation != passphrase {
+		return false, fmt.Errorf("passphrases do not match")
+	}
+
+	err = m.encryptionFunctions.SetFolderPassphrase(folder, passphrase)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// inheritFolderEncryption asks for confirmation and removes the recipients and passphrase of a sub-folder
+func (m *Menu) inheritFolderEncryption(folder string) (bool, error) {
+	confirmDialog := confirm.NewWarningDialog(
+		"inherit",
+		"Entries will be re-encrypted with the encryption of the parent folder.\nAnyone who can open the parent will be able to read them.",
+		fmt.Sprintf("Folder: %s", folder),
+		m.Options,
+	)
+	finalModel, err := m.run(confirmDialog)
+	if err != nil {
+		return false, fmt.Errorf("error running confirmation dialog: %v", err)
+	}
+	if !finalModel.(confirm.ConfirmModel).IsConfirmed() {
+		return false, nil
+	}
+
+	err = m.encryptionFunctions.ClearFolderEncryption(folder)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reencryptEntries re-encrypts entries after the encryption of their folder changed
+func (m *Menu) reencryptEntries(filenames []string) error {
+	rekey := progress.NewProgress("🔐 Re-encrypting Entries", filenames, m.encryptionFunctions.ReencryptFile, m.Options)
+	finalModel, err := m.run(rekey)
+	if err != nil {
+		return fmt.Errorf("error running re-encryption: %v", err)
+	}
+	err = m.encryptionFunctions.ReencryptTrash()
+	if err != nil {
+		return fmt.Errorf("failed to re-encrypt entries in the trash: %v", err)
+	}
+
+	progressModel := finalModel.(progress.ProgressModel)
+	if remaining := len(filenames) - progressModel.Completed(); remaining > 0 {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("⚠️  Re-encryption stopped early: %d entries still use the old encryption.\n\n", remaining)
+		fmt.Printf("Apply 🔐 Encryption Settings before locking the vault to re-encrypt the rest.\n\n")
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+	}
+	return nil
+}
+
+// shareVault re-encrypts every entry after the first recipient was added to the vault. Once the
+// vault is shared it is opened without the data key, so if any entry could not be moved to the
+// recipients the vault goes back to the master password instead of keeping entries nobody can open.
+func (m *Menu) shareVault(filenames []string) error {
+	rekey := progress.NewProgress("🔐 Re-encrypting Entries", filenames, m.encryptionFunctions.ReencryptFile, m.Options)
+	finalModel, err := m.run(rekey)
+	progressModel, _ := finalModel.(progress.ProgressModel)
+	failures := progressModel.GetFailures()
+	if err == nil && progressModel.Completed() == len(filenames) && len(failures) == 0 {
+		err = m.encryptionFunctions.ReencryptTrash()
+		if err == nil {
+			return nil
+		}
+	}
+
+	// Only the entries that were re-encrypted need to go back
+	failed := make(map[string]bool)
+	for _, failure := range failures {
+		failed[failure.Item] = true
+	}
+	var moved []string
+	for _, filename := range filenames[:progressModel.Completed()] {
+		if !failed[filename] {
+			moved = append(moved, filename)
+		}
+	}
+	undoErr := m.encryptionFunctions.UnshareVault(moved)
+	if undoErr != nil {
+		return fmt.Errorf("failed to undo sharing the vault: %v", undoErr)
+	}
+	if err != nil {
+		return fmt.Errorf("the vault was not shared: %v", err)
+	}
+	return fmt.Errorf("the vault was not shared: %d of %d entries could not be re-encrypted", len(filenames)-len(moved), len(filenames))
+}
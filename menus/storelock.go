@@ -0,0 +1,51 @@
+package menus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// AcquireStoreLock takes the lock of the open store so this instance may write to it. When
+// another instance holds the lock the user is told, and the store is opened read-only:
+// entries can be viewed and copied but not changed. It runs before logging in, so it does
+// not go through the session's idle timer.
+func (m *Menu) AcquireStoreLock() error {
+	err := m.passwordFolder.AcquireLock()
+	var locked *fileio.LockedError
+	if errors.As(err, &locked) {
+		m.passwordFolder.ReadOnly = true
+		m.Options.ReadOnly = true
+
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("\033[93m\033[1m⚠️  %v\033[0m\n\n", locked)
+		fmt.Printf("Opening read-only: you can view entries but not change them.\n")
+		fmt.Printf("Close the other instance to make changes. If it crashed, the lock is\n")
+		fmt.Printf("cleared automatically the next time the store is opened.\n\n")
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	m.Options.ReadOnly = false
+	return nil
+}
+
+// ReleaseStoreLock removes the lock of the open store if this instance holds it
+func (m *Menu) ReleaseStoreLock() error {
+	return m.passwordFolder.ReleaseLock()
+}
+
+// retryStoreLock tries again to take the lock of a store opened read-only, so the store
+// becomes writable as soon as the other instance exits
+func (m *Menu) retryStoreLock() {
+	if !m.passwordFolder.ReadOnly {
+		return
+	}
+	if m.passwordFolder.AcquireLock() == nil {
+		m.Options.ReadOnly = false
+	}
+}
@@ -0,0 +1,198 @@
+package menus
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/gitstore"
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	"github.com/Fozzyack/password-manager/ui/conflict"
+	"github.com/Fozzyack/password-manager/ui/textinput"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// Sync pulls changes from the vault's git remote and pushes local ones. The first time it
+// offers to keep the vault in a git repository and asks for the remote. Files changed on
+// both sides are shown so the user can keep either version of each. A key file changed on
+// another machine, e.g. by a new master password, logs the user out to unlock it again.
+// Returns true if the vault was synced, false if cancelled.
+func (m *Menu) Sync() (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	repo := m.repo()
+	if !repo.Enabled() {
+		confirmDialog := confirm.NewWarningDialog(
+			"use git",
+			"Keep the history of this vault in a git repository?\nEvery change will be committed, encrypted as it is on disk.",
+			fmt.Sprintf("Store: %s", m.passwordFolder.FolderLocation),
+			m.Options,
+		)
+		finalModel, err := m.run(confirmDialog)
+		if err != nil {
+			return false, fmt.Errorf("error running confirmation dialog: %v", err)
+		}
+		if !finalModel.(confirm.ConfirmModel).IsConfirmed() {
+			return false, nil
+		}
+		err = repo.Init()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	remote, err := repo.Remote()
+	if err != nil {
+		return false, err
+	}
+	if remote == "" {
+		_, err = m.run(textinput.InitialModel("Enter the git remote to sync with", "git@github.com:you/vault.git", &remote, m.Options))
+		if err != nil {
+			return false, err
+		}
+		remote = strings.TrimSpace(remote)
+		if m.Options.Quit || remote == "" {
+			// Escape cancels rather than quitting the application; history is still kept
+			m.Options.Quit = false
+			return false, nil
+		}
+		err = repo.SetRemote(remote)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("🔃 Syncing with %s...\n", remote)
+	keyFile := m.readKeyFile()
+	conflicts, err := repo.Pull()
+	if err != nil {
+		return false, err
+	}
+	if len(conflicts) > 0 {
+		resolved, err := m.resolveConflicts(repo, conflicts)
+		if err != nil || !resolved {
+			return false, err
+		}
+	}
+
+	err = m.passwordFolder.RefreshDirectoryListing()
+	if err != nil {
+		return false, err
+	}
+
+	err = repo.Push()
+	if err != nil {
+		return false, err
+	}
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Vault synced successfully!\n\n")
+	fmt.Printf("Remote: %s\n", remote)
+	if len(conflicts) > 0 {
+		fmt.Printf("Conflicts resolved: %d\n", len(conflicts))
+	}
+	// The data key in memory was unwrapped from the old key file, which may no longer match
+	keyChanged := !bytes.Equal(keyFile, m.readKeyFile())
+	if keyChanged {
+		fmt.Printf("🔑 The vault's key file was changed on another machine, log in again to unlock it.\n")
+	}
+	fmt.Println()
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	if keyChanged {
+		m.lockNotice = "🔑 The key file changed in the last sync, log in with the vault's current master password"
+		m.Lock()
+	}
+	return true, nil
+}
+
+// readKeyFile returns the vault's key file as it is on disk, nil if it has none
+func (m *Menu) readKeyFile() []byte {
+	path := filepath.Join(m.passwordFolder.FolderLocation, filepath.FromSlash(encryption.InitFilename)+".gpg")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// resolveConflicts lets the user keep our or their version of each conflicting file and
+// completes the merge. Cancelling aborts the merge, leaving the vault as it was.
+// Returns true if the conflicts were resolved, false if cancelled.
+func (m *Menu) resolveConflicts(repo *gitstore.Repo, paths []string) (bool, error) {
+	conflicts := make([]conflict.Conflict, 0, len(paths))
+	for _, path := range paths {
+		conflicts = append(conflicts, conflict.Conflict{
+			Path:   path,
+			Ours:   m.describeVersion(repo, path, gitstore.Ours),
+			Theirs: m.describeVersion(repo, path, gitstore.Theirs),
+		})
+	}
+
+	conflictView := conflict.NewConflictView(conflicts, m.Options)
+	finalModel, err := m.run(conflictView)
+	if err != nil {
+		return false, fmt.Errorf("error running conflict view: %v", err)
+	}
+	conflictModel := finalModel.(conflict.ConflictModel)
+	if !conflictModel.IsConfirmed() {
+		return false, repo.AbortMerge()
+	}
+
+	for path, side := range conflictModel.GetResolutions() {
+		err = repo.Resolve(path, side)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, repo.FinishMerge()
+}
+
+// describeVersion summarises one side of a conflicting file, reading entries the vault can decrypt
+func (m *Menu) describeVersion(repo *gitstore.Repo, path string, side gitstore.Side) string {
+	data, exists, err := repo.Version(path, side)
+	if err != nil {
+		return fmt.Sprintf("Unknown (%v)", err)
+	}
+	if !exists {
+		return "Deleted"
+	}
+
+	name, isEntry := strings.CutSuffix(path, ".gpg")
+	if !isEntry || strings.HasPrefix(path, ".") || strings.Contains(path, "/.") {
+		return "Changed"
+	}
+	entry, err := m.encryptionFunctions.DecryptEntry(name, data)
+	if err != nil {
+		return "Changed (cannot be read here)"
+	}
+	description := "Updated " + utils.FormatTimestampForDisplay(entry.UpdatedAt)
+	if entry.Username != "" {
+		description += ", username " + entry.Username
+	}
+	return description
+}
+
+// repo returns the git repository of the open vault, which may not have been set up
+func (m *Menu) repo() *gitstore.Repo {
+	return gitstore.Open(m.passwordFolder.FolderLocation)
+}
+
+// commitChange records a change to the vault in its git repository, if it has one. The change
+// itself has already been saved, so a failed commit is only reported.
+func (m *Menu) commitChange(format string, args ...any) {
+	err := m.repo().Commit(fmt.Sprintf(format, args...))
+	if err != nil {
+		fmt.Print("\033[2J\033[H") // Clear screen
+		fmt.Printf("⚠️  The change was saved but could not be committed to git: %v\n\n", err)
+		fmt.Println("Press Enter to continue...")
+		fmt.Scanln()
+	}
+}
@@ -0,0 +1,183 @@
+package menus
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	"github.com/Fozzyack/password-manager/ui/list"
+	"github.com/Fozzyack/password-manager/ui/tags"
+	"github.com/Fozzyack/password-manager/ui/textinput"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// ManageTags lists the tags used across the store until the user goes back. A tag can be
+// renamed, or merged into another, on every entry that has it in one go. Entries in folders
+// that are still locked are left as they are.
+// Returns true if any tag was changed.
+func (m *Menu) ManageTags() (bool, error) {
+	changed := false
+	for {
+		// Clear any previous error messages
+		m.Options.ErrorMessage = ""
+
+		entries, err := m.getAllPasswordEntries()
+		if err != nil {
+			return changed, fmt.Errorf("failed to load password entries: %v", err)
+		}
+		tagList := countTags(entries)
+
+		tagsView := tags.NewTagsView(tagList, m.Options)
+		finalModel, err := m.run(tagsView)
+		if err != nil {
+			return changed, fmt.Errorf("error running tags view: %v", err)
+		}
+		tagsModel := finalModel.(tags.TagsModel)
+
+		var done bool
+		switch tagsModel.GetAction() {
+		case tags.Rename:
+			done, err = m.renameTag(entries, tagList, tagList[tagsModel.GetSelectedIndex()].Name, false)
+		case tags.Merge:
+			done, err = m.renameTag(entries, tagList, tagList[tagsModel.GetSelectedIndex()].Name, true)
+		default:
+			return changed, nil
+		}
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || done
+	}
+}
+
+// countTags returns every tag of the entries with the number of entries that have it, by name
+func countTags(entries []list.PasswordEntry) []tags.Tag {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		for _, tag := range entry.Tags {
+			counts[tag]++
+		}
+	}
+	var tagList []tags.Tag
+	for name, count := range counts {
+		tagList = append(tagList, tags.Tag{Name: name, Count: count})
+	}
+	slices.SortFunc(tagList, func(a, b tags.Tag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tagList
+}
+
+// renameTag asks for a new name for a tag and gives it to every entry with the tag. Naming it
+// after an existing tag merges the two, after confirmation; merge asks for the tag to merge into.
+func (m *Menu) renameTag(entries []list.PasswordEntry, tagList []tags.Tag, from string, merge bool) (bool, error) {
+	var name string
+	header := fmt.Sprintf("Rename #%s to", from)
+	placeholder := "new-name"
+	if merge {
+		header = fmt.Sprintf("Merge #%s into which tag?", from)
+		placeholder = "existing tag"
+	}
+	_, err := m.run(textinput.InitialModel(header, placeholder, &name, m.Options))
+	if err != nil {
+		return false, err
+	}
+	if m.Options.Quit {
+		// Escape cancels rather than quitting the application
+		m.Options.Quit = false
+		return false, nil
+	}
+
+	to := utils.CleanTag(name)
+	if to == "" || to == from {
+		return false, nil
+	}
+	exists := slices.ContainsFunc(tagList, func(tag tags.Tag) bool {
+		return tag.Name == to
+	})
+	if merge && !exists {
+		return false, fmt.Errorf("there is no tag #%s to merge into", to)
+	}
+
+	// Merging cannot be undone, as the entries of the two tags can no longer be told apart
+	if exists {
+		confirmDialog := confirm.NewWarningDialog(
+			"merge",
+			fmt.Sprintf("Merge #%s into #%s?\nEntries with either tag will only have #%s.", from, to, to),
+			fmt.Sprintf("Entries tagged #%s: %d", from, countTagged(entries, from)),
+			m.Options,
+		)
+		finalConfirmModel, err := m.run(confirmDialog)
+		if err != nil {
+			return false, fmt.Errorf("error running confirmation dialog: %v", err)
+		}
+		if !finalConfirmModel.(confirm.ConfirmModel).IsConfirmed() {
+			return false, nil
+		}
+	}
+
+	renamed := 0
+	for _, entry := range entries {
+		if !slices.Contains(entry.Tags, from) {
+			continue
+		}
+		data, err := m.encryptionFunctions.DecryptPasswordFromFile(entry.Filename)
+		if err != nil {
+			return renamed > 0, fmt.Errorf("failed to read %s: %v", entry.Filename, err)
+		}
+
+		// Tags are bookkeeping rather than an edit, so no earlier version is kept
+		data.Tags = replaceTag(data.Tags, from, to)
+		err = m.encryptionFunctions.RewriteEntry(entry.Filename, data)
+		if err != nil {
+			return renamed > 0, fmt.Errorf("failed to save %s: %v", entry.Filename, err)
+		}
+		renamed++
+	}
+	if exists {
+		m.commitChange("Merge tag %s into %s in %d entries", from, to, renamed)
+	} else {
+		m.commitChange("Rename tag %s to %s in %d entries", from, to, renamed)
+	}
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	if exists {
+		fmt.Printf("✅ Tags merged successfully!\n\n")
+	} else {
+		fmt.Printf("✅ Tag renamed successfully!\n\n")
+	}
+	fmt.Printf("#%s → #%s\n", from, to)
+	fmt.Printf("Entries changed: %d\n\n", renamed)
+
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
+
+// countTagged returns how many of the entries have a tag
+func countTagged(entries []list.PasswordEntry, tag string) int {
+	count := 0
+	for _, entry := range entries {
+		if slices.Contains(entry.Tags, tag) {
+			count++
+		}
+	}
+	return count
+}
+
+// replaceTag returns tags with from renamed to to, keeping each tag once and in its place
+func replaceTag(entryTags []string, from, to string) []string {
+	var replaced []string
+	for _, tag := range entryTags {
+		if tag == from {
+			tag = to
+		}
+		if !slices.Contains(replaced, tag) {
+			replaced = append(replaced, tag)
+		}
+	}
+	return replaced
+}
@@ -0,0 +1,50 @@
+package menus
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Fozzyack/password-manager/ui/list"
+	"github.com/Fozzyack/password-manager/ui/tags"
+)
+
+func TestCountTags(t *testing.T) {
+	entries := []list.PasswordEntry{
+		{Filename: "github", Tags: []string{"work", "dev"}},
+		{Filename: "gitlab", Tags: []string{"dev"}},
+		{Filename: "bank"},
+		{Filename: "work/aws", Tags: []string{"work", "cloud"}},
+	}
+	want := []tags.Tag{
+		{Name: "cloud", Count: 1},
+		{Name: "dev", Count: 2},
+		{Name: "work", Count: 2},
+	}
+	if got := countTags(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("countTags = %+v, want %+v", got, want)
+	}
+	if got := countTags(nil); len(got) != 0 {
+		t.Errorf("countTags(nil) = %+v, want no tags", got)
+	}
+}
+
+func TestReplaceTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		from, to string
+		want     []string
+	}{
+		{"rename in place", []string{"home", "dev", "work"}, "dev", "code", []string{"home", "code", "work"}},
+		{"merge into an existing tag", []string{"dev", "work", "code"}, "dev", "code", []string{"code", "work"}},
+		{"merge keeps the earlier position", []string{"code", "work", "dev"}, "dev", "code", []string{"code", "work"}},
+		{"tag not present", []string{"home"}, "dev", "code", []string{"home"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := replaceTag(test.tags, test.from, test.to); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("replaceTag(%v, %s, %s) = %v, want %v", test.tags, test.from, test.to, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package menus
+
+import (
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/ui/confirm"
+	"github.com/Fozzyack/password-manager/ui/trash"
+	"github.com/Fozzyack/password-manager/utils"
+)
+
+// ShowTrash lists the deleted entries until the user goes back. An entry can be restored,
+// with its history, or purged for good after confirmation, as can the whole trash.
+// Returns true if anything was restored or purged.
+func (m *Menu) ShowTrash() (bool, error) {
+	changed := false
+	for {
+		// Clear any previous error messages
+		m.Options.ErrorMessage = ""
+
+		entries, err := m.passwordFolder.ListTrash()
+		if err != nil {
+			return changed, err
+		}
+
+		trashView := trash.NewTrashView(entries, m.config.TrashRetentionPeriod(), m.Options)
+		finalModel, err := m.run(trashView)
+		if err != nil {
+			return changed, fmt.Errorf("error running trash view: %v", err)
+		}
+		trashModel := finalModel.(trash.TrashModel)
+
+		var done bool
+		switch trashModel.GetAction() {
+		case trash.Restore:
+			done, err = m.restoreFromTrash(entries[trashModel.GetSelectedIndex()])
+		case trash.Purge:
+			done, err = m.purgeFromTrash(entries[trashModel.GetSelectedIndex():trashModel.GetSelectedIndex()+1])
+		case trash.Empty:
+			done, err = m.purgeFromTrash(entries)
+		default:
+			return changed, nil
+		}
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || done
+	}
+}
+
+// restoreFromTrash moves a trashed entry back into the store and shows where it went
+func (m *Menu) restoreFromTrash(entry fileio.TrashedEntry) (bool, error) {
+	restored, err := m.passwordFolder.RestoreFromTrash(entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore from the trash: %v", err)
+	}
+	m.commitChange("Restore %s from the trash", restored)
+
+	err = m.passwordFolder.RefreshDirectoryListing()
+	if err != nil {
+		return true, err
+	}
+
+	// Show success message
+	fmt.Print("\033[2J\033[H") // Clear screen
+	fmt.Printf("✅ Password restored successfully!\n\n")
+	fmt.Printf("Site: %s\n", utils.ParseFilenameToSiteName(restored))
+	fmt.Printf("File: %s.gpg\n", restored)
+	if restored != entry.Filename {
+		fmt.Printf("\n%s.gpg has been taken since it was deleted, so it was restored under a new name.\n", entry.Filename)
+	}
+	fmt.Println()
+	fmt.Println("Press Enter to continue...")
+	fmt.Scanln()
+
+	return true, nil
+}
+
+// purgeFromTrash permanently deletes trashed entries after confirmation
+func (m *Menu) purgeFromTrash(entries []fileio.TrashedEntry) (bool, error) {
+	details := fmt.Sprintf("Entries: %d", len(entries))
+	if len(entries) == 1 {
+		details = fmt.Sprintf("Site: %s\nFile: %s.gpg", utils.ParseFilenameToSiteName(entries[0].Filename), entries[0].Filename)
+	}
+	warning := "Permanently delete from the trash, along with the history?\nThis action cannot be undone!"
+	if m.repo().Enabled() {
+		// Purging only removes the files, every version committed before stays in git
+		warning += "\nEarlier versions stay in the vault's git history and on its remote."
+	}
+	confirmDialog := confirm.NewWarningDialog(
+		"purge",
+		warning,
+		details,
+		m.Options,
+	)
+	finalConfirmModel, err := m.run(confirmDialog)
+	if err != nil {
+		return false, fmt.Errorf("error running confirmation dialog: %v", err)
+	}
+	if !finalConfirmModel.(confirm.ConfirmModel).IsConfirmed() {
+		return false, nil
+	}
+
+	for _, entry := range entries {
+		err = m.passwordFolder.PurgeFromTrash(entry)
+		if err != nil {
+			return true, err
+		}
+	}
+	if len(entries) == 1 {
+		m.commitChange("Purge %s from the trash", entries[0].Filename)
+	} else {
+		m.commitChange("Empty the trash")
+	}
+	return true, nil
+}
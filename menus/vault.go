@@ -0,0 +1,84 @@
+package menus
+
+import (
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/ui/vault"
+)
+
+// SwitchVault lets the user pick another named vault, or register a new one, and opens it.
+// The current vault is locked: its key is wiped, the clipboard is cleared and the user is
+// logged out so the main loop asks for the new vault's master password.
+// Returns true if a different vault was opened, false if cancelled.
+func (m *Menu) SwitchVault() (bool, error) {
+	// Clear any previous error messages
+	m.Options.ErrorMessage = ""
+
+	names := m.config.VaultNames()
+	vaults := make([]vault.Vault, 0, len(names))
+	for _, name := range names {
+		location, err := m.config.VaultLocation(name)
+		if err != nil {
+			return false, err
+		}
+		vaults = append(vaults, vault.Vault{Name: name, Location: location})
+	}
+
+	picker := vault.NewVaultPicker(vaults, m.Options.Vault, m.Options)
+	finalModel, err := m.run(picker)
+	if err != nil {
+		return false, fmt.Errorf("error running vault picker: %v", err)
+	}
+
+	pickerModel := finalModel.(vault.VaultModel)
+	if pickerModel.IsCancelled() {
+		return false, nil // Not an error, just cancelled
+	}
+
+	name := pickerModel.GetSelected()
+	if pickerModel.IsAdded() {
+		var location string
+		name, location = pickerModel.GetNewVault()
+		err = m.config.AddVault(name, location)
+		if err != nil {
+			return false, err
+		}
+		err = m.config.Save()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if name == "" || name == m.Options.Vault {
+		return false, nil
+	}
+
+	location, err := m.config.VaultLocation(name)
+	if err != nil {
+		return false, err
+	}
+	passwordFolder, err := fileio.OpenPasswordFolder(location)
+	if err != nil {
+		return false, fmt.Errorf("could not open vault '%s': %v", name, err)
+	}
+
+	// Lock the current vault before handing over to the new one
+	m.Lock()
+	err = m.ReleaseStoreLock()
+	if err != nil {
+		return false, err
+	}
+
+	m.passwordFolder = passwordFolder
+	m.encryptionFunctions = encryption.NewEncryption(passwordFolder)
+	m.encryptionFunctions.KeyringPath = m.config.KeyringPath()
+	m.Options.Vault = name
+	m.failedAttempts = 0
+	err = m.AcquireStoreLock()
+	if err != nil {
+		return true, err
+	}
+	return true, m.CheckPermissions()
+}
@@ -0,0 +1,225 @@
+// Package otp generates the one-time codes used for two-factor authentication: time-based
+// codes (TOTP, RFC 6238) and counter-based codes (HOTP, RFC 4226). Keys are read and
+// stored as otpauth:// URIs, the format sites encode in the QR code shown when 2FA is set up.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the kind of one-time password a key generates
+type Type string
+
+const (
+	TOTP Type = "totp" // A new code every period
+	HOTP Type = "hotp" // A new code each time the counter moves on
+)
+
+// Defaults used when a URI or bare secret does not say otherwise, as authenticator apps do
+const (
+	DefaultAlgorithm = "SHA1"
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+)
+
+// Key is a shared 2FA secret with the settings needed to generate its codes
+type Key struct {
+	Type      Type
+	Label     string // Account the key belongs to, e.g. "GitHub:alice"
+	Issuer    string // Service that issued the key, optional
+	Secret    string // Base32 encoded shared secret, upper case without padding
+	Algorithm string // SHA1, SHA256 or SHA512
+	Digits    int    // Length of each code
+	Period    int    // Seconds each TOTP code is valid for
+	Counter   uint64 // Counter of the next HOTP code
+}
+
+// Parse reads an otpauth:// URI, or a bare base32 secret which is taken as a TOTP key with
+// the default settings
+func Parse(value string) (Key, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(strings.ToLower(value), "otpauth://") {
+		return newKey(TOTP, value)
+	}
+
+	uri, err := url.Parse(value)
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid otpauth URI: %v", err)
+	}
+	query := uri.Query()
+	key, err := newKey(Type(strings.ToLower(uri.Host)), query.Get("secret"))
+	if err != nil {
+		return Key{}, err
+	}
+	key.Label = strings.TrimPrefix(uri.Path, "/")
+	key.Issuer = query.Get("issuer")
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+		if _, err := key.hash(); err != nil {
+			return Key{}, err
+		}
+	}
+	if digits := query.Get("digits"); digits != "" {
+		key.Digits, err = strconv.Atoi(digits)
+		if err != nil || key.Digits < 6 || key.Digits > 8 {
+			return Key{}, fmt.Errorf("codes must have 6 to 8 digits, not %q", digits)
+		}
+	}
+	if period := query.Get("period"); period != "" && key.Type == TOTP {
+		key.Period, err = strconv.Atoi(period)
+		if err != nil || key.Period <= 0 {
+			return Key{}, fmt.Errorf("invalid code period %q", period)
+		}
+	}
+	if counter := query.Get("counter"); counter != "" && key.Type == HOTP {
+		key.Counter, err = strconv.ParseUint(counter, 10, 64)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid counter %q", counter)
+		}
+	}
+	return key, nil
+}
+
+// newKey creates a key of the given type with the default settings, checking the secret decodes
+func newKey(keyType Type, secret string) (Key, error) {
+	if keyType != TOTP && keyType != HOTP {
+		return Key{}, fmt.Errorf("unsupported one-time password type %q, expected totp or hotp", keyType)
+	}
+	// Secrets are often shown in groups of four and without padding
+	secret = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(secret))
+	if secret == "" {
+		return Key{}, fmt.Errorf("the 2FA secret is empty")
+	}
+	key := Key{
+		Type:      keyType,
+		Secret:    secret,
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+	if _, err := key.secretBytes(); err != nil {
+		return Key{}, fmt.Errorf("the 2FA secret is not valid base32")
+	}
+	return key, nil
+}
+
+// Normalize parses value and returns it as an otpauth:// URI, or "" if value is empty
+func Normalize(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	key, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return key.URI(), nil
+}
+
+// URI returns the key as an otpauth:// URI, which Parse reads back unchanged
+func (k Key) URI() string {
+	query := url.Values{}
+	query.Set("secret", k.Secret)
+	if k.Issuer != "" {
+		query.Set("issuer", k.Issuer)
+	}
+	query.Set("algorithm", k.Algorithm)
+	query.Set("digits", strconv.Itoa(k.Digits))
+	if k.Type == HOTP {
+		query.Set("counter", strconv.FormatUint(k.Counter, 10))
+	} else {
+		query.Set("period", strconv.Itoa(k.Period))
+	}
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     string(k.Type),
+		Path:     "/" + k.Label,
+		RawQuery: query.Encode(),
+	}
+	return uri.String()
+}
+
+// Code returns the TOTP code valid at t
+func (k Key) Code(t time.Time) (string, error) {
+	return k.generate(uint64(t.Unix()) / uint64(k.Period))
+}
+
+// Remaining returns how long the TOTP code valid at t stays valid
+func (k Key) Remaining(t time.Time) time.Duration {
+	period := int64(k.Period)
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
+
+// Next returns the HOTP code for the current counter and the key with its counter moved on.
+// The returned key must be saved before the code is used so the code is never given twice.
+func (k Key) Next() (string, Key, error) {
+	code, err := k.generate(k.Counter)
+	if err != nil {
+		return "", k, err
+	}
+	k.Counter++
+	return code, k, nil
+}
+
+// generate computes the code for a counter value as described in RFC 4226
+func (k Key) generate(counter uint64) (string, error) {
+	secret, err := k.secretBytes()
+	if err != nil {
+		return "", err
+	}
+	newHash, err := k.hash()
+	if err != nil {
+		return "", err
+	}
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+	mac := hmac.New(newHash, secret)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation: the low nibble of the last byte picks four bytes of the MAC
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulus := uint32(1)
+	for range k.Digits {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", k.Digits, value%modulus), nil
+}
+
+// secretBytes decodes the base32 secret
+func (k Key) secretBytes() ([]byte, error) {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(k.Secret)
+}
+
+// hash returns the hash function of the key's algorithm
+func (k Key) hash() (func() hash.Hash, error) {
+	switch k.Algorithm {
+	case "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported 2FA algorithm %q, expected SHA1, SHA256 or SHA512", k.Algorithm)
+}
+
+// Format splits a code in two halves for reading, e.g. "123 456"
+func Format(code string) string {
+	half := len(code) / 2
+	return code[:half] + " " + code[half:]
+}
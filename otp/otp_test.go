@@ -0,0 +1,147 @@
+package otp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secret base32 encodes an ASCII seed from the RFCs
+func secret(seed string) string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(seed))
+}
+
+func TestHOTPVectors(t *testing.T) {
+	// RFC 4226 appendix D
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	key, err := newKey(HOTP, secret("12345678901234567890"))
+	if err != nil {
+		t.Fatalf("newKey: %v", err)
+	}
+	for counter, code := range want {
+		got, next, err := key.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if got != code {
+			t.Errorf("code for counter %d = %s, want %s", counter, got, code)
+		}
+		if next.Counter != uint64(counter+1) {
+			t.Errorf("counter after %d = %d, want it moved on by one", counter, next.Counter)
+		}
+		key = next
+	}
+}
+
+func TestTOTPVectors(t *testing.T) {
+	// RFC 6238 appendix B, the seed of each algorithm repeated to the length of its hash
+	seeds := map[string]string{
+		"SHA1":   "12345678901234567890",
+		"SHA256": "12345678901234567890123456789012",
+		"SHA512": "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		time      int64
+		algorithm string
+		want      string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1111111109, "SHA256", "68084774"},
+		{1111111109, "SHA512", "25091201"},
+		{1111111111, "SHA1", "14050471"},
+		{1111111111, "SHA256", "67062674"},
+		{1111111111, "SHA512", "99943326"},
+		{1234567890, "SHA1", "89005924"},
+		{1234567890, "SHA256", "91819424"},
+		{1234567890, "SHA512", "93441116"},
+		{2000000000, "SHA1", "69279037"},
+		{2000000000, "SHA256", "90698825"},
+		{2000000000, "SHA512", "38618901"},
+		{20000000000, "SHA1", "65353130"},
+		{20000000000, "SHA256", "77737706"},
+		{20000000000, "SHA512", "47863826"},
+	}
+	for _, test := range tests {
+		uri := "otpauth://totp/RFC?digits=8&algorithm=" + test.algorithm + "&secret=" + secret(seeds[test.algorithm])
+		key, err := Parse(uri)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		got, err := key.Code(time.Unix(test.time, 0))
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		if got != test.want {
+			t.Errorf("%s code at %d = %s, want %s", test.algorithm, test.time, got, test.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	key, err := Parse("jbsw y3dp-ehpk 3pxp")
+	if err != nil {
+		t.Fatalf("Parse of a bare secret: %v", err)
+	}
+	if key.Type != TOTP || key.Secret != "JBSWY3DPEHPK3PXP" || key.Digits != DefaultDigits || key.Period != DefaultPeriod {
+		t.Errorf("Parse of a bare secret = %+v, want a default TOTP key", key)
+	}
+
+	key, err = Parse("otpauth://hotp/Example:alice?secret=JBSWY3DPEHPK3PXP&issuer=Example&counter=7&digits=8&algorithm=sha256")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := Key{Type: HOTP, Label: "Example:alice", Issuer: "Example", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA256", Digits: 8, Period: DefaultPeriod, Counter: 7}
+	if key != want {
+		t.Errorf("Parse = %+v, want %+v", key, want)
+	}
+	// URI is read back unchanged
+	if again, err := Parse(key.URI()); err != nil || again != key {
+		t.Errorf("Parse(URI()) = %+v, %v, want %+v", again, err, key)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	values := []string{
+		"",
+		"not base32!",
+		"otpauth://motp/alice?secret=JBSWY3DPEHPK3PXP",
+		"otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+		"otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&digits=4",
+		"otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&period=0",
+		"otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&counter=-1",
+	}
+	for _, value := range values {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("Parse accepted %q", value)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	uri, err := Normalize("  ")
+	if err != nil || uri != "" {
+		t.Errorf("Normalize of an empty value = %q, %v", uri, err)
+	}
+	uri, err = Normalize("JBSWY3DPEHPK3PXP")
+	if err != nil || !strings.HasPrefix(uri, "otpauth://totp/") {
+		t.Errorf("Normalize = %q, %v, want an otpauth:// URI", uri, err)
+	}
+}
+
+func TestRemainingAndFormat(t *testing.T) {
+	key := Key{Period: 30}
+	if got := key.Remaining(time.Unix(59, 0)); got != time.Second {
+		t.Errorf("Remaining at 59s = %v, want 1s", got)
+	}
+	if got := key.Remaining(time.Unix(60, 0)); got != 30*time.Second {
+		t.Errorf("Remaining at 60s = %v, want 30s", got)
+	}
+	if got := Format("123456"); got != "123 456" {
+		t.Errorf("Format = %q", got)
+	}
+}
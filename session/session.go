@@ -0,0 +1,189 @@
+// Package session tracks user activity across the Bubble Tea programs launched by the menus
+// and locks the vault once the user has been idle for too long. Every program is run through
+// a Locker, which records key and mouse input and stops the program when the session expires.
+// Between programs, such as while a message waits for Enter, an expiry callback locks the vault
+// in the background instead.
+package session
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultTimeout is how long the vault stays unlocked without input unless configured otherwise
+const DefaultTimeout = 5 * time.Minute
+
+// ErrLocked is returned by Run when the session locked while, or before, the program ran
+var ErrLocked = errors.New("session locked")
+
+// checkInterval is how often a running program checks whether the session has expired
+const checkInterval = time.Second
+
+// Locker decides when the session is locked. It is shared by every screen so time spent idle
+// in one program counts towards the same timeout as time spent idle in the next.
+type Locker struct {
+	timeout time.Duration
+
+	mu           sync.Mutex
+	lastActivity time.Time
+	locked       bool
+	running      bool        // Whether a program started by Run is open
+	onExpire     func()      // Called when the session expires while no program is open
+	timer        *time.Timer // Fires when the session may have expired, nil while locked
+}
+
+// NewLocker creates a locker that expires after the given idle time. A timeout of zero or
+// less disables automatic locking; Lock still works.
+func NewLocker(timeout time.Duration) *Locker {
+	return &Locker{
+		timeout:      timeout,
+		lastActivity: time.Now(),
+	}
+}
+
+// Touch records user activity and postpones the automatic lock
+func (l *Locker) Touch() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastActivity = time.Now()
+}
+
+// OnExpire sets the function called when the session expires while no program is open, for
+// example while a message waits for Enter on the terminal. It is called once per session, on
+// its own goroutine, and should wipe anything the session has unlocked.
+func (l *Locker) OnExpire(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onExpire = fn
+}
+
+// Lock locks the session immediately
+func (l *Locker) Lock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locked = true
+	l.stopTimer()
+}
+
+// Reset unlocks the session after a successful login and restarts the idle timer
+func (l *Locker) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.locked = false
+	l.lastActivity = time.Now()
+	l.stopTimer()
+	if l.timeout > 0 {
+		l.timer = time.AfterFunc(l.timeout, l.expire)
+	}
+}
+
+// stopTimer stops the background expiry check. The caller must hold l.mu.
+func (l *Locker) stopTimer() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+}
+
+// expire runs when the idle timer fires. Activity since the timer was set pushes it back;
+// otherwise the session locks and, unless a program is open to notice, onExpire is called.
+func (l *Locker) expire() {
+	l.mu.Lock()
+	if l.locked || l.timer == nil {
+		l.mu.Unlock()
+		return
+	}
+	if remaining := l.timeout - time.Since(l.lastActivity); remaining > 0 {
+		l.timer.Reset(remaining)
+		l.mu.Unlock()
+		return
+	}
+	l.locked = true
+	l.timer = nil
+	onExpire := l.onExpire
+	running := l.running
+	l.mu.Unlock()
+
+	// A running program is stopped by watch and the caller locks the vault once Run returns
+	if onExpire != nil && !running {
+		onExpire()
+	}
+}
+
+// IsLocked reports whether the session is locked, locking it first if it has been idle too long
+func (l *Locker) IsLocked() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.locked && l.timeout > 0 && time.Since(l.lastActivity) >= l.timeout {
+		l.locked = true
+	}
+	return l.locked
+}
+
+// Timeout returns the idle time after which the session locks
+func (l *Locker) Timeout() time.Duration {
+	return l.timeout
+}
+
+// Run runs the model as a Bubble Tea program, treating key and mouse input as activity.
+// If the session expires while the program is open the program is stopped and ErrLocked
+// is returned; a locked session does not start the program at all.
+func (l *Locker) Run(model tea.Model) (tea.Model, error) {
+	if l.IsLocked() {
+		return model, ErrLocked
+	}
+
+	p := tea.NewProgram(model, tea.WithFilter(l.filter))
+	done := make(chan struct{})
+	if l.timeout > 0 {
+		go l.watch(p, done)
+	}
+
+	l.setRunning(true)
+	finalModel, err := p.Run()
+	l.setRunning(false)
+	close(done)
+	if err != nil {
+		return finalModel, err
+	}
+	if l.IsLocked() {
+		return finalModel, ErrLocked
+	}
+	return finalModel, nil
+}
+
+// setRunning records whether a program is open
+func (l *Locker) setRunning(running bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running = running
+}
+
+// filter records activity for every input message before it reaches the model
+func (l *Locker) filter(_ tea.Model, msg tea.Msg) tea.Msg {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		l.Touch()
+	}
+	return msg
+}
+
+// watch stops the program once the session expires
+func (l *Locker) watch(p *tea.Program, done <-chan struct{}) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if l.IsLocked() {
+				p.Quit()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockerExpires(t *testing.T) {
+	l := NewLocker(50 * time.Millisecond)
+	if l.IsLocked() {
+		t.Fatal("a new locker is already locked")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if !l.IsLocked() {
+		t.Fatal("the locker did not lock after the timeout")
+	}
+
+	l.Reset()
+	if l.IsLocked() {
+		t.Error("Reset did not unlock the session")
+	}
+}
+
+func TestLockerTouchPostponesLock(t *testing.T) {
+	l := NewLocker(80 * time.Millisecond)
+	for range 4 {
+		time.Sleep(30 * time.Millisecond)
+		l.Touch()
+	}
+	if l.IsLocked() {
+		t.Error("the locker locked although there was activity")
+	}
+}
+
+func TestLockerWithoutTimeout(t *testing.T) {
+	l := NewLocker(0)
+	l.Reset()
+	time.Sleep(10 * time.Millisecond)
+	if l.IsLocked() {
+		t.Error("a locker without a timeout locked by itself")
+	}
+	l.Lock()
+	if !l.IsLocked() {
+		t.Error("Lock did not lock the session")
+	}
+}
+
+func TestLockerOnExpire(t *testing.T) {
+	l := NewLocker(200 * time.Millisecond)
+	expired := make(chan struct{}, 2)
+	l.OnExpire(func() { expired <- struct{}{} })
+	l.Reset()
+
+	// Activity pushes the expiry back rather than being ignored
+	time.Sleep(120 * time.Millisecond)
+	l.Touch()
+	time.Sleep(120 * time.Millisecond)
+	select {
+	case <-expired:
+		t.Fatal("OnExpire was called although there was activity")
+	default:
+	}
+
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatal("OnExpire was not called once the session expired")
+	}
+	if !l.IsLocked() {
+		t.Error("the session is not locked after expiring")
+	}
+	time.Sleep(250 * time.Millisecond)
+	if len(expired) != 0 {
+		t.Error("OnExpire was called more than once")
+	}
+}
+
+func TestLockerOnExpireNotCalledAfterLock(t *testing.T) {
+	l := NewLocker(30 * time.Millisecond)
+	called := make(chan struct{}, 1)
+	l.OnExpire(func() { called <- struct{}{} })
+	l.Reset()
+	l.Lock()
+	time.Sleep(60 * time.Millisecond)
+	if len(called) != 0 {
+		t.Error("OnExpire was called for a session that was locked by hand")
+	}
+}
+
+func TestLockerOnExpireWaitsForRunningProgram(t *testing.T) {
+	l := NewLocker(30 * time.Millisecond)
+	called := make(chan struct{}, 1)
+	l.OnExpire(func() { called <- struct{}{} })
+	l.Reset()
+	l.setRunning(true)
+	time.Sleep(60 * time.Millisecond)
+	if len(called) != 0 {
+		t.Error("OnExpire was called while a program was open")
+	}
+	if !l.IsLocked() {
+		t.Error("the session did not lock while a program was open")
+	}
+}
@@ -0,0 +1,184 @@
+// Package templates describes the kinds of entry the store holds besides website logins:
+// credit cards, secure notes, Wi-Fi networks, SSH keys, database connections and API tokens.
+// A template decides which of an entry's standard fields are used and what they are called,
+// which extra fields it has, how each value is checked and how it is shown.
+package templates
+
+import (
+	"github.com/Fozzyack/password-manager/encryption"
+)
+
+// Keys of the standard fields, as the form reports them in GetFormData
+const (
+	KeyUsername = "username"
+	KeyEmail    = "email"
+	KeyURL      = "url"
+	KeyPassword = "password"
+	KeyOTP      = "2fa_secret"
+)
+
+// Field is an input of a template
+type Field struct {
+	Key         string               // Standard field the value is stored in, or "" to store it as a custom field
+	Name        string               // Label, and the name of the custom field the value is stored as
+	Type        encryption.FieldType // How the value is entered and shown
+	Placeholder string
+	Required    bool
+	Validate    func(string) error  // Optional check of a non-empty value
+	ShowLast    int                 // Characters left visible while a hidden value is masked, e.g. 4 for card numbers
+	Format      func(string) string // Optional layout of the value when it is shown, e.g. digits in groups
+}
+
+// Template is a kind of entry with its own set of fields
+type Template struct {
+	Type            encryption.EntryType
+	Name            string // e.g. "Credit Card"
+	Icon            string
+	Description     string
+	NameLabel       string // Label of the name the entry is saved under, e.g. "Card Name"
+	NamePlaceholder string
+	Fields          []Field
+	Strength        bool // Whether the password's strength is shown, for passwords the user chooses
+}
+
+// All lists the templates in the order they are offered, logins first
+var All = []Template{
+	{
+		Type:            encryption.EntryLogin,
+		Name:            "Login",
+		Icon:            "🔑",
+		Description:     "Website or application account",
+		NameLabel:       "Site/Service Name",
+		NamePlaceholder: "e.g., Gmail, GitHub, Banking",
+		Fields: []Field{
+			{Key: KeyUsername, Name: "Username", Placeholder: "your_username"},
+			{Key: KeyEmail, Name: "Email", Placeholder: "user@example.com"},
+			{Key: KeyURL, Name: "URL", Type: encryption.FieldURL, Placeholder: "https://example.com"},
+			{Key: KeyPassword, Name: "Password", Type: encryption.FieldHidden, Placeholder: "Enter password or generate one", Required: true},
+			{Key: KeyOTP, Name: "2FA Secret", Type: encryption.FieldHidden, Placeholder: "otpauth:// URI or base32 secret (optional)", Validate: validateOTP},
+		},
+		Strength: true,
+	},
+	{
+		Type:            encryption.EntryCard,
+		Name:            "Credit Card",
+		Icon:            "💳",
+		Description:     "Card number, expiry date, security code and PIN",
+		NameLabel:       "Card Name",
+		NamePlaceholder: "e.g., Visa Everyday, Amex Business",
+		Fields: []Field{
+			{Name: "Cardholder", Placeholder: "Name as printed on the card", Required: true},
+			{Name: "Card Number", Type: encryption.FieldHidden, Placeholder: "1234 5678 9012 3456", Required: true, Validate: validateCardNumber, ShowLast: 4, Format: formatCardNumber},
+			{Name: "Expiry", Placeholder: "MM/YY", Required: true, Validate: validateExpiry},
+			{Name: "Security Code", Type: encryption.FieldHidden, Placeholder: "3 or 4 digits on the card", Validate: validateSecurityCode},
+			{Key: KeyPassword, Name: "PIN", Type: encryption.FieldHidden, Placeholder: "Card PIN (optional)", Validate: validatePIN},
+			{Key: KeyURL, Name: "Bank Website", Type: encryption.FieldURL, Placeholder: "https://bank.example.com (optional)"},
+		},
+	},
+	{
+		Type:            encryption.EntryNote,
+		Name:            "Secure Note",
+		Icon:            "📝",
+		Description:     "Free text kept encrypted, such as recovery phrases",
+		NameLabel:       "Title",
+		NamePlaceholder: "e.g., Passport details, Wallet recovery",
+		Fields: []Field{
+			{Name: "Note", Type: encryption.FieldNotes, Required: true},
+		},
+	},
+	{
+		Type:            encryption.EntryWiFi,
+		Name:            "Wi-Fi Network",
+		Icon:            "📶",
+		Description:     "Network name, password and security type",
+		NameLabel:       "Network Name",
+		NamePlaceholder: "The SSID, e.g., HomeNetwork",
+		Fields: []Field{
+			{Key: KeyPassword, Name: "Password", Type: encryption.FieldHidden, Placeholder: "Leave empty for an open network", Validate: validateWiFiPassword},
+			{Name: "Security", Placeholder: "WPA3, WPA2, WPA or WEP", Validate: validateWiFiSecurity},
+		},
+		Strength: true,
+	},
+	{
+		Type:            encryption.EntrySSH,
+		Name:            "SSH Key",
+		Icon:            "🔐",
+		Description:     "Private key with its passphrase and host",
+		NameLabel:       "Key Name",
+		NamePlaceholder: "e.g., Work laptop, Home server",
+		Fields: []Field{
+			{Name: "Host", Placeholder: "e.g., server.example.com:22", Validate: validateHost},
+			{Key: KeyUsername, Name: "Username", Placeholder: "e.g., root"},
+			{Name: "Private Key", Type: encryption.FieldNotes, Required: true, Validate: validatePrivateKey},
+			{Name: "Public Key", Placeholder: "ssh-ed25519 AAAA... (optional)", Validate: validatePublicKey},
+			{Key: KeyPassword, Name: "Passphrase", Type: encryption.FieldHidden, Placeholder: "Passphrase of the key (optional)"},
+		},
+	},
+	{
+		Type:            encryption.EntryDatabase,
+		Name:            "Database",
+		Icon:            "💾",
+		Description:     "Connection details of a database server",
+		NameLabel:       "Connection Name",
+		NamePlaceholder: "e.g., Production Postgres",
+		Fields: []Field{
+			{Name: "Engine", Placeholder: "e.g., PostgreSQL, MySQL, MongoDB"},
+			{Name: "Host", Placeholder: "e.g., db.example.com", Required: true, Validate: validateHost},
+			{Name: "Port", Placeholder: "e.g., 5432", Validate: validatePort},
+			{Name: "Database", Placeholder: "Database name (optional)"},
+			{Key: KeyUsername, Name: "Username", Placeholder: "e.g., app_user"},
+			{Key: KeyPassword, Name: "Password", Type: encryption.FieldHidden, Placeholder: "Enter password or generate one", Required: true},
+		},
+		Strength: true,
+	},
+	{
+		Type:            encryption.EntryAPI,
+		Name:            "API Token",
+		Icon:            "🔌",
+		Description:     "Token or key pair for a service's API",
+		NameLabel:       "Service",
+		NamePlaceholder: "e.g., Stripe, OpenWeather",
+		Fields: []Field{
+			{Name: "Key ID", Placeholder: "Public key or client ID (optional)"},
+			{Key: KeyPassword, Name: "Token", Type: encryption.FieldHidden, Placeholder: "Secret token or key", Required: true, Validate: validateToken},
+			{Key: KeyURL, Name: "Endpoint", Type: encryption.FieldURL, Placeholder: "https://api.example.com (optional)"},
+			{Name: "Expires", Placeholder: "YYYY-MM-DD (optional)", Validate: validateDate},
+		},
+	},
+}
+
+// For returns the template of an entry type. Entries saved before types existed, and types
+// this version does not know, are shown as logins.
+func For(entryType encryption.EntryType) Template {
+	for _, t := range All {
+		if t.Type == entryType {
+			return t
+		}
+	}
+	return All[0]
+}
+
+// Standard returns the template's field stored in the standard field key, if it has one
+func (t Template) Standard(key string) (Field, bool) {
+	for _, field := range t.Fields {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Custom returns the template's field stored as the custom field name, if it has one
+func (t Template) Custom(name string) (Field, bool) {
+	for _, field := range t.Fields {
+		if field.Key == "" && field.Name == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Title returns the icon and name of the template, e.g. "💳 Credit Card"
+func (t Template) Title() string {
+	return t.Icon + " " + t.Name
+}
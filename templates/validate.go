@@ -0,0 +1,171 @@
+package templates
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/otp"
+)
+
+// validateOTP checks a 2FA secret can generate codes
+func validateOTP(value string) error {
+	_, err := otp.Parse(value)
+	return err
+}
+
+// validateCardNumber checks a card number has 12 to 19 digits and passes the Luhn check
+// every card number carries, which catches a mistyped or swapped digit
+func validateCardNumber(value string) error {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(value)
+	if !isDigits(digits) {
+		return fmt.Errorf("card numbers only contain digits")
+	}
+	if len(digits) < 12 || len(digits) > 19 {
+		return fmt.Errorf("card numbers have 12 to 19 digits, not %d", len(digits))
+	}
+
+	sum := 0
+	for i := range len(digits) {
+		digit := int(digits[len(digits)-1-i] - '0')
+		// Every second digit from the right is doubled
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return fmt.Errorf("not a valid card number, check it for typos")
+	}
+	return nil
+}
+
+// formatCardNumber shows a card number in groups of four digits, as printed on most cards
+func formatCardNumber(value string) string {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(value)
+	var groups []string
+	for len(digits) > 4 {
+		groups = append(groups, digits[:4])
+		digits = digits[4:]
+	}
+	return strings.Join(append(groups, digits), " ")
+}
+
+// validateExpiry checks an expiry date is written MM/YY or MM/YYYY
+func validateExpiry(value string) error {
+	month, year, ok := strings.Cut(value, "/")
+	if !ok || len(month) != 2 || (len(year) != 2 && len(year) != 4) || !isDigits(month) || !isDigits(year) {
+		return fmt.Errorf("expected MM/YY, e.g. 08/29")
+	}
+	if m, _ := strconv.Atoi(month); m < 1 || m > 12 {
+		return fmt.Errorf("month must be 01 to 12")
+	}
+	return nil
+}
+
+// validateSecurityCode checks a card security code has 3 or 4 digits
+func validateSecurityCode(value string) error {
+	if !isDigits(value) || len(value) < 3 || len(value) > 4 {
+		return fmt.Errorf("expected 3 or 4 digits")
+	}
+	return nil
+}
+
+// validatePIN checks a card PIN has 4 to 12 digits
+func validatePIN(value string) error {
+	if !isDigits(value) || len(value) < 4 || len(value) > 12 {
+		return fmt.Errorf("expected 4 to 12 digits")
+	}
+	return nil
+}
+
+// validateWiFiPassword checks a WPA passphrase has the 8 to 63 characters networks accept
+func validateWiFiPassword(value string) error {
+	if len(value) < 8 || len(value) > 63 {
+		return fmt.Errorf("Wi-Fi passwords have 8 to 63 characters")
+	}
+	return nil
+}
+
+// validateWiFiSecurity checks the security type is one routers offer
+func validateWiFiSecurity(value string) error {
+	switch strings.ToUpper(strings.ReplaceAll(value, " ", "")) {
+	case "WPA3", "WPA2", "WPA", "WEP", "WPA2/WPA3", "NONE", "OPEN":
+		return nil
+	}
+	return fmt.Errorf("expected WPA3, WPA2, WPA, WEP or None")
+}
+
+// validateHost checks a host name, optionally followed by a port
+func validateHost(value string) error {
+	if strings.ContainsAny(value, " /") {
+		return fmt.Errorf("expected a host name such as server.example.com, without spaces or a path")
+	}
+	if host, port, err := net.SplitHostPort(value); err == nil {
+		if host == "" {
+			return fmt.Errorf("the host name is missing")
+		}
+		return validatePort(port)
+	}
+	return nil
+}
+
+// validatePort checks a port number is in range
+func validatePort(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("ports are numbers from 1 to 65535")
+	}
+	return nil
+}
+
+// validatePrivateKey checks the value looks like a PEM or OpenSSH private key
+func validatePrivateKey(value string) error {
+	if !strings.Contains(value, "PRIVATE KEY-----") {
+		return fmt.Errorf("paste the whole key, from -----BEGIN to the END ... PRIVATE KEY----- line")
+	}
+	return nil
+}
+
+// validatePublicKey checks the value looks like an OpenSSH public key
+func validatePublicKey(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) < 2 || !(strings.HasPrefix(fields[0], "ssh-") || strings.HasPrefix(fields[0], "ecdsa-") || strings.HasPrefix(fields[0], "sk-")) {
+		return fmt.Errorf("expected an OpenSSH public key, e.g. ssh-ed25519 AAAA...")
+	}
+	return nil
+}
+
+// validateToken checks a token has no spaces, which usually come from copying it with its label
+func validateToken(value string) error {
+	if strings.ContainsAny(value, " \t\n") {
+		return fmt.Errorf("tokens do not contain spaces")
+	}
+	return nil
+}
+
+// validateDate checks a date is written YYYY-MM-DD
+func validateDate(value string) error {
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
+		return fmt.Errorf("expected a date as YYYY-MM-DD")
+	}
+	return nil
+}
+
+// isDigits returns whether value is a non-empty string of ASCII digits
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package templates
+
+import "testing"
+
+func TestValidateCardNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		valid  bool
+	}{
+		{"4111 1111 1111 1111", true},
+		{"4111-1111-1111-1111", true},
+		{"5500005555555559", true},
+		{"378282246310005", true},       // 15 digit American Express
+		{"4111 1111 1111 1112", false},  // Last digit mistyped
+		{"4111 1111 1111 1211", false},  // Swapped digits
+		{"4111 1111 1111 111a", false},  // Not a digit
+		{"41111111112", false},          // Too short, although it passes the Luhn check
+		{"41111111111111111115", false}, // Too long, although it passes the Luhn check
+		{"", false},
+	}
+	for _, test := range tests {
+		if err := validateCardNumber(test.number); (err == nil) != test.valid {
+			t.Errorf("validateCardNumber(%q) = %v, want valid %v", test.number, err, test.valid)
+		}
+	}
+}
+
+func TestFormatCardNumber(t *testing.T) {
+	tests := []struct {
+		number, want string
+	}{
+		{"4111111111111111", "4111 1111 1111 1111"},
+		{"4111-1111 1111-1111", "4111 1111 1111 1111"},
+		{"378282246310005", "3782 8224 6310 005"},
+		{"4111", "4111"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := formatCardNumber(test.number); got != test.want {
+			t.Errorf("formatCardNumber(%q) = %q, want %q", test.number, got, test.want)
+		}
+	}
+}
+
+func TestValidateExpiry(t *testing.T) {
+	tests := []struct {
+		expiry string
+		valid  bool
+	}{
+		{"08/29", true},
+		{"12/2031", true},
+		{"01/00", true},
+		{"13/29", false},
+		{"00/29", false},
+		{"8/29", false},
+		{"08/029", false},
+		{"0829", false},
+		{"ab/cd", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if err := validateExpiry(test.expiry); (err == nil) != test.valid {
+			t.Errorf("validateExpiry(%q) = %v, want valid %v", test.expiry, err, test.valid)
+		}
+	}
+}
+
+func TestValidateHost(t *testing.T) {
+	tests := []struct {
+		host  string
+		valid bool
+	}{
+		{"server.example.com", true},
+		{"server.example.com:2222", true},
+		{"192.168.1.10", true},
+		{"[2001:db8::1]:22", true},
+		{"server.example.com:0", false},
+		{"server.example.com:70000", false},
+		{"server.example.com:ssh", false},
+		{":22", false},
+		{"server example.com", false},
+		{"server.example.com/path", false},
+	}
+	for _, test := range tests {
+		if err := validateHost(test.host); (err == nil) != test.valid {
+			t.Errorf("validateHost(%q) = %v, want valid %v", test.host, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package throttle slows down master password guessing. Failed unlock attempts are counted in
+// .checker/attempts.json next to the validation file, so the count survives restarts, and each
+// failure doubles the wait before the next attempt. An optional limit locks the store out entirely.
+package throttle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Fozzyack/password-manager/fileio"
+)
+
+// Filename is the store-relative path of the attempt counter
+const Filename = ".checker/attempts.json"
+
+// Backoff limits: the first failure waits BaseDelay and each further failure doubles it up to MaxDelay
+const (
+	BaseDelay = time.Second
+	MaxDelay  = 5 * time.Minute
+)
+
+// recentLimit is how many failure times are kept for the notice shown after a successful login
+const recentLimit = 10
+
+// ErrLockedOut is returned when the store has reached its failed attempt limit
+var ErrLockedOut = errors.New("too many failed login attempts")
+
+// Attempts is the persistent record of failed unlock attempts for one store
+type Attempts struct {
+	Failures    int         `json:"failures"`     // Consecutive failures since the last successful unlock
+	LastFailure time.Time   `json:"last_failure"` // Time of the most recent failure
+	Recent      []time.Time `json:"recent"`       // Times of the most recent failures, oldest first
+
+	path        string // Absolute path of the attempts file
+	maxFailures int    // Failures allowed before locking out, 0 for no limit
+}
+
+// Load reads the attempt counter of the store at storeLocation. A missing file means no failures.
+// maxFailures enables the hard lockout after that many consecutive failures; 0 disables it.
+func Load(storeLocation string, maxFailures int) (*Attempts, error) {
+	attempts := &Attempts{
+		path:        filepath.Join(storeLocation, Filename),
+		maxFailures: maxFailures,
+	}
+
+	data, err := os.ReadFile(attempts.path)
+	if os.IsNotExist(err) {
+		return attempts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", attempts.path, err)
+	}
+
+	err = json.Unmarshal(data, attempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", attempts.path, err)
+	}
+	return attempts, nil
+}
+
+// Delay returns how long to wait before the next attempt is allowed, zero if it is allowed now
+func (a *Attempts) Delay() time.Duration {
+	if a.Failures == 0 {
+		return 0
+	}
+
+	backoff := MaxDelay
+	if a.Failures <= 20 {
+		backoff = min(BaseDelay<<(a.Failures-1), MaxDelay)
+	}
+	remaining := time.Until(a.LastFailure.Add(backoff))
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// LockedOut reports whether the failure limit has been reached
+func (a *Attempts) LockedOut() bool {
+	return a.maxFailures > 0 && a.Failures >= a.maxFailures
+}
+
+// Remaining returns how many attempts are left before the lockout, or -1 without a limit
+func (a *Attempts) Remaining() int {
+	if a.maxFailures <= 0 {
+		return -1
+	}
+	return max(a.maxFailures-a.Failures, 0)
+}
+
+// LockoutError describes the lockout and how to lift it
+func (a *Attempts) LockoutError() error {
+	return fmt.Errorf("%w: the store is locked after %d failures, delete %s to allow logins again", ErrLockedOut, a.Failures, a.path)
+}
+
+// RecordFailure counts a failed attempt and saves the counter
+func (a *Attempts) RecordFailure() error {
+	now := time.Now()
+	a.Failures++
+	a.LastFailure = now
+	a.Recent = append(a.Recent, now)
+	if len(a.Recent) > recentLimit {
+		a.Recent = a.Recent[len(a.Recent)-recentLimit:]
+	}
+	return a.save()
+}
+
+// RecordSuccess clears the counter after a successful unlock
+func (a *Attempts) RecordSuccess() error {
+	if a.Failures == 0 {
+		return nil
+	}
+	a.Failures = 0
+	a.Recent = nil
+	err := os.Remove(a.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to reset %s: %v", a.path, err)
+	}
+	return nil
+}
+
+// save writes the counter with owner-only permissions
+func (a *Attempts) save() error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode attempts: %v", err)
+	}
+	err = fileio.WriteFileAtomic(a.path, data, fileio.FilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", a.path, err)
+	}
+	return nil
+}
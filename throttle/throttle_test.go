@@ -0,0 +1,137 @@
+package throttle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newStore creates an empty store directory with its .checker folder
+func newStore(t *testing.T) string {
+	t.Helper()
+	location := t.TempDir()
+	err := os.Mkdir(filepath.Join(location, ".checker"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestDelayBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, BaseDelay},
+		{2, 2 * BaseDelay},
+		{4, 8 * BaseDelay},
+		{9, 256 * BaseDelay},
+		{10, MaxDelay},
+		{64, MaxDelay},
+	}
+	for _, test := range tests {
+		attempts := &Attempts{Failures: test.failures, LastFailure: time.Now()}
+		got := attempts.Delay()
+		// Delay counts down from the last failure, so allow for the time the test takes
+		if got > test.want || got < test.want-time.Second {
+			t.Errorf("Delay after %d failures = %v, want %v", test.failures, got, test.want)
+		}
+	}
+}
+
+func TestDelayExpires(t *testing.T) {
+	attempts := &Attempts{Failures: 3, LastFailure: time.Now().Add(-time.Minute)}
+	if delay := attempts.Delay(); delay != 0 {
+		t.Errorf("Delay = %v after the backoff passed, want 0", delay)
+	}
+}
+
+func TestLockout(t *testing.T) {
+	attempts := &Attempts{maxFailures: 3, Failures: 2}
+	if attempts.LockedOut() || attempts.Remaining() != 1 {
+		t.Errorf("after 2 of 3 failures: LockedOut = %v, Remaining = %d", attempts.LockedOut(), attempts.Remaining())
+	}
+	attempts.Failures = 3
+	if !attempts.LockedOut() || attempts.Remaining() != 0 {
+		t.Errorf("after 3 of 3 failures: LockedOut = %v, Remaining = %d", attempts.LockedOut(), attempts.Remaining())
+	}
+	if !errors.Is(attempts.LockoutError(), ErrLockedOut) {
+		t.Errorf("LockoutError = %v, want it to wrap ErrLockedOut", attempts.LockoutError())
+	}
+
+	unlimited := &Attempts{Failures: 100}
+	if unlimited.LockedOut() || unlimited.Remaining() != -1 {
+		t.Errorf("without a limit: LockedOut = %v, Remaining = %d", unlimited.LockedOut(), unlimited.Remaining())
+	}
+}
+
+func TestRecordFailurePersists(t *testing.T) {
+	location := newStore(t)
+
+	attempts, err := Load(location, 5)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if attempts.Failures != 0 {
+		t.Fatalf("Failures = %d in a new store, want 0", attempts.Failures)
+	}
+	for range recentLimit + 2 {
+		err = attempts.RecordFailure()
+		if err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+	}
+
+	// The count survives a restart, and only the most recent failure times are kept
+	loaded, err := Load(location, 5)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Failures != recentLimit+2 || len(loaded.Recent) != recentLimit {
+		t.Errorf("loaded %d failures with %d recent times, want %d and %d", loaded.Failures, len(loaded.Recent), recentLimit+2, recentLimit)
+	}
+	if !loaded.LockedOut() {
+		t.Error("the loaded counter is not locked out")
+	}
+	info, err := os.Stat(filepath.Join(location, Filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("attempts file has permissions %o, want 600", perm)
+	}
+}
+
+func TestRecordSuccessResets(t *testing.T) {
+	location := newStore(t)
+
+	attempts, err := Load(location, 0)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	attempts.RecordFailure()
+	err = attempts.RecordSuccess()
+	if err != nil {
+		t.Fatalf("RecordSuccess: %v", err)
+	}
+	if attempts.Failures != 0 || attempts.Delay() != 0 {
+		t.Errorf("after a success: Failures = %d, Delay = %v", attempts.Failures, attempts.Delay())
+	}
+	if _, err := os.Stat(filepath.Join(location, Filename)); !os.IsNotExist(err) {
+		t.Errorf("the attempts file was not removed: %v", err)
+	}
+}
+
+func TestLoadRejectsMalformedFile(t *testing.T) {
+	location := newStore(t)
+	err := os.WriteFile(filepath.Join(location, Filename), []byte("{"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(location, 0); err == nil {
+		t.Error("Load accepted a malformed attempts file")
+	}
+}
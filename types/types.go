@@ -13,4 +13,10 @@ type Options struct {
 	
 	// ErrorMessage holds validation or authentication error messages to display to the user
 	ErrorMessage string
+
+	// Vault is the name of the open password store, empty when it was opened by path
+	Vault string
+
+	// ReadOnly indicates the store is open read-only because another instance holds its lock
+	ReadOnly bool
 }
\ No newline at end of file
@@ -0,0 +1,259 @@
+// Package backupform provides the forms used to create and restore encrypted backups.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package backupform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Input field indexes
+const (
+	PathField = iota
+	PassphraseField
+	ConfirmField
+)
+
+// minPassphraseLength is the minimum length of a new backup passphrase
+const minPassphraseLength = 8
+
+// BackupFormModel represents the state of the backup or restore form
+type BackupFormModel struct {
+	title        string
+	labels       []string
+	inputs       []textinput.Model
+	currentField int
+	restore      bool
+	submitted    bool
+	cancelled    bool
+	options      *types.Options
+}
+
+// Form styling
+var (
+	backupTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	backupContainerStyle = lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	backupFieldLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Margin(0, 0, 0, 1)
+
+	backupRequiredStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true)
+
+	backupHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+
+	backupErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true).
+		Align(lipgloss.Left).
+		Margin(0, 0, 1, 1)
+)
+
+// NewBackupForm creates the form for writing a new backup: destination, passphrase and confirmation
+func NewBackupForm(options *types.Options) BackupFormModel {
+	defaultPath := filepath.Join(os.Getenv("HOME"), fmt.Sprintf("password-backup-%s.pmbak", time.Now().Format("20060102")))
+	return newForm(
+		"💾 Create Encrypted Backup",
+		[]string{"Backup File", "Backup Passphrase", "Confirm Passphrase"},
+		[]string{"Destination file path", "Passphrase to encrypt the backup (8+ chars)", "Confirm passphrase"},
+		defaultPath,
+		false,
+		options,
+	)
+}
+
+// NewRestoreForm creates the form for restoring a backup: source file and passphrase
+func NewRestoreForm(options *types.Options) BackupFormModel {
+	return newForm(
+		"♻️  Restore From Backup",
+		[]string{"Backup File", "Backup Passphrase"},
+		[]string{"Path to backup file", "Passphrase used when the backup was created"},
+		"",
+		true,
+		options,
+	)
+}
+
+// newForm builds the shared form layout; every field after the first is masked
+func newForm(title string, labels, placeholders []string, defaultPath string, restore bool, options *types.Options) BackupFormModel {
+	// Clear screen for clean form display
+	fmt.Print("\033[2J\033[H")
+
+	inputs := make([]textinput.Model, len(labels))
+	for i := range inputs {
+		ti := textinput.New()
+		ti.Placeholder = placeholders[i]
+		ti.CharLimit = 300
+		ti.Width = 50
+
+		// Style the textinput
+		ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+		ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+		ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+
+		if i == PathField {
+			ti.SetValue(defaultPath)
+			ti.Focus()
+		} else {
+			ti.EchoMode = textinput.EchoPassword
+			ti.EchoCharacter = '•'
+		}
+		inputs[i] = ti
+	}
+
+	return BackupFormModel{
+		title:   title,
+		labels:  labels,
+		inputs:  inputs,
+		restore: restore,
+		options: options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m BackupFormModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles user input and form navigation
+func (m BackupFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.cancelled = true
+			m.options.Quit = false // Don't quit the entire app, just cancel the form
+			return m, tea.Quit
+
+		case "enter":
+			// Move to next field or submit if on last field
+			if m.currentField < len(m.inputs)-1 {
+				return m.moveTo(m.currentField + 1)
+			}
+			if m.getValidationError() == "" {
+				m.submitted = true
+				return m, tea.Quit
+			}
+			return m, nil
+
+		case "tab", "down":
+			if m.currentField < len(m.inputs)-1 {
+				return m.moveTo(m.currentField + 1)
+			}
+
+		case "shift+tab", "up":
+			if m.currentField > 0 {
+				return m.moveTo(m.currentField - 1)
+			}
+		}
+	}
+
+	// Update the current input field
+	var cmd tea.Cmd
+	m.inputs[m.currentField], cmd = m.inputs[m.currentField].Update(msg)
+	return m, cmd
+}
+
+// moveTo moves focus to the given field
+func (m BackupFormModel) moveTo(field int) (tea.Model, tea.Cmd) {
+	m.inputs[m.currentField].Blur()
+	m.currentField = field
+	m.inputs[m.currentField].Focus()
+	return m, m.inputs[m.currentField].Cursor.BlinkCmd()
+}
+
+// View renders the form interface
+func (m BackupFormModel) View() string {
+	var content strings.Builder
+
+	// Title
+	content.WriteString(backupTitleStyle.Render(m.title) + "\n\n")
+
+	formContent := ""
+	for i, label := range m.labels {
+		formContent += backupFieldLabelStyle.Render(label)
+		formContent += backupRequiredStyle.Render(" *") + "\n"
+		formContent += "  " + m.inputs[i].View() + "\n\n"
+	}
+
+	// Validation errors once the user reaches the last field
+	if m.currentField == len(m.inputs)-1 {
+		if errorMsg := m.getValidationError(); errorMsg != "" {
+			formContent += backupErrorStyle.Render("❌ "+errorMsg) + "\n\n"
+		}
+	}
+
+	content.WriteString(backupContainerStyle.Render(formContent))
+
+	// Help text
+	help := backupHelpStyle.Render("Tab/↑↓: Navigate • Enter: Next/Submit • Esc: Cancel")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// getValidationError returns the current validation error message
+func (m BackupFormModel) getValidationError() string {
+	if strings.TrimSpace(m.inputs[PathField].Value()) == "" {
+		return "Backup file is required"
+	}
+
+	passphrase := m.inputs[PassphraseField].Value()
+	if passphrase == "" {
+		return "Backup passphrase is required"
+	}
+	if m.restore {
+		return ""
+	}
+
+	if len(passphrase) < minPassphraseLength {
+		return fmt.Sprintf("Backup passphrase must be at least %d characters long", minPassphraseLength)
+	}
+	if passphrase != m.inputs[ConfirmField].Value() {
+		return "Passphrases do not match"
+	}
+	return ""
+}
+
+// GetFormData returns the entered path and passphrase
+func (m BackupFormModel) GetFormData() (string, string) {
+	return strings.TrimSpace(m.inputs[PathField].Value()), m.inputs[PassphraseField].Value()
+}
+
+// IsSubmitted returns whether the form was successfully submitted
+func (m BackupFormModel) IsSubmitted() bool {
+	return m.submitted
+}
+
+// IsCancelled returns whether the form was cancelled
+func (m BackupFormModel) IsCancelled() bool {
+	return m.cancelled
+}
@@ -0,0 +1,182 @@
+// Package collision provides a view for resolving filename collisions when restoring a backup.
+// Each colliding entry can be skipped, overwritten or restored under a new name.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package collision
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/backup"
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/Fozzyack/password-manager/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CollisionModel represents the state of the collision resolution view
+type CollisionModel struct {
+	names       []string
+	resolutions []backup.Resolution
+	cursor      int
+	confirmed   bool
+	cancelled   bool
+	options     *types.Options
+}
+
+// Collision view styling
+var (
+	collisionTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FFD700")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FFD700")).
+		Align(lipgloss.Center)
+
+	collisionContainerStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(80).
+		Align(lipgloss.Left)
+
+	collisionItemStyle = lipgloss.NewStyle().
+		Padding(0, 2)
+
+	collisionSelectedStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	collisionInfoStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFD700")).
+		Margin(0, 0, 1, 0)
+
+	collisionHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(4).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewCollisionView creates a collision view for the given entry filenames, defaulting every entry to Skip
+func NewCollisionView(names []string, options *types.Options) CollisionModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	return CollisionModel{
+		names:       names,
+		resolutions: make([]backup.Resolution, len(names)),
+		options:     options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m CollisionModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles user input for the collision view
+func (m CollisionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			m.cancelled = true
+			return m, tea.Quit
+
+		case "enter":
+			m.confirmed = true
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.names)-1 {
+				m.cursor++
+			}
+
+		case "right", "l", " ":
+			m.resolutions[m.cursor] = (m.resolutions[m.cursor] + 1) % 3
+
+		case "left", "h":
+			m.resolutions[m.cursor] = (m.resolutions[m.cursor] + 2) % 3
+
+		case "s":
+			m.resolutions[m.cursor] = backup.Skip
+		case "o":
+			m.resolutions[m.cursor] = backup.Overwrite
+		case "r":
+			m.resolutions[m.cursor] = backup.Rename
+
+		case "S":
+			m.setAll(backup.Skip)
+		case "O":
+			m.setAll(backup.Overwrite)
+		case "R":
+			m.setAll(backup.Rename)
+		}
+	}
+
+	return m, nil
+}
+
+// setAll applies the resolution to every colliding entry
+func (m *CollisionModel) setAll(resolution backup.Resolution) {
+	for i := range m.resolutions {
+		m.resolutions[i] = resolution
+	}
+}
+
+// View renders the collision view
+func (m CollisionModel) View() string {
+	var content strings.Builder
+
+	// Title
+	content.WriteString(collisionTitleStyle.Render("⚠️  Existing Entries Found") + "\n\n")
+
+	listContent := collisionInfoStyle.Render(fmt.Sprintf("%d entries in the backup already exist in your store.\nChoose what to do with each one.", len(m.names))) + "\n"
+
+	for i, name := range m.names {
+		entryText := fmt.Sprintf("%-45s [%s]", utils.TruncateString(name, 44), m.resolutions[i])
+		if i == m.cursor {
+			listContent += collisionSelectedStyle.Render("► "+entryText) + "\n"
+		} else {
+			listContent += collisionItemStyle.Render("  "+entryText) + "\n"
+		}
+	}
+
+	content.WriteString(collisionContainerStyle.Render(listContent))
+
+	// Help text
+	help := collisionHelpStyle.Render("↑↓: Navigate • ←→/Space: Change • s/o/r: Skip/Overwrite/Rename • S/O/R: All • Enter: Restore • Esc: Cancel")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// GetResolutions returns the chosen resolution for each colliding entry
+func (m CollisionModel) GetResolutions() map[string]backup.Resolution {
+	resolutions := make(map[string]backup.Resolution, len(m.names))
+	for i, name := range m.names {
+		resolutions[name] = m.resolutions[i]
+	}
+	return resolutions
+}
+
+// IsConfirmed returns whether the user confirmed the resolutions
+func (m CollisionModel) IsConfirmed() bool {
+	return m.confirmed
+}
+
+// IsCancelled returns whether the restore was cancelled
+func (m CollisionModel) IsCancelled() bool {
+	return m.cancelled
+}
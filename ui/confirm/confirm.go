@@ -16,6 +16,8 @@ type ConfirmModel struct {
 	siteName  string
 	filename  string
 	action    string  // e.g., "delete", "remove"
+	warning   string  // Custom warning message, replaces the default entry warning when set
+	details   string  // Custom information shown in the boxed area, replaces the entry info when set
 	confirmed bool
 	cancelled bool
 	cursor    int     // 0 for No, 1 for Yes
@@ -94,6 +96,15 @@ func NewConfirmDialog(siteName, filename, action string, options *types.Options)
 	}
 }
 
+// NewWarningDialog creates a confirmation dialog for actions that are not tied to a single entry.
+// The warning is shown in place of the default deletion text and details are shown in the boxed area.
+func NewWarningDialog(action, warning, details string, options *types.Options) ConfirmModel {
+	dialog := NewConfirmDialog("", "", action, options)
+	dialog.warning = warning
+	dialog.details = details
+	return dialog
+}
+
 // Init implements the tea.Model interface
 func (m ConfirmModel) Init() tea.Cmd {
 	return nil
@@ -152,12 +163,20 @@ func (m ConfirmModel) View() string {
 	dialogContent := ""
 
 	// Warning message
-	dialogContent += warningStyle.Render(fmt.Sprintf("Are you sure you want to %s this password entry?", m.action)) + "\n\n"
-	dialogContent += warningStyle.Render("This action cannot be undone!") + "\n\n"
+	if m.warning != "" {
+		dialogContent += warningStyle.Render(m.warning) + "\n\n"
+	} else {
+		dialogContent += warningStyle.Render(fmt.Sprintf("Are you sure you want to %s this password entry?", m.action)) + "\n\n"
+		dialogContent += warningStyle.Render("This action cannot be undone!") + "\n\n"
+	}
 
 	// Entry information
-	entryInfo := fmt.Sprintf("Site: %s\nFile: %s.gpg", m.siteName, m.filename)
-	dialogContent += entryInfoStyle.Render(entryInfo) + "\n\n"
+	if m.details != "" {
+		dialogContent += entryInfoStyle.Render(m.details) + "\n\n"
+	} else if m.filename != "" {
+		entryInfo := fmt.Sprintf("Site: %s\nFile: %s.gpg", m.siteName, m.filename)
+		dialogContent += entryInfoStyle.Render(entryInfo) + "\n\n"
+	}
 
 	// Buttons
 	var noButton, yesButton string
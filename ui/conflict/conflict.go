@@ -0,0 +1,189 @@
+// Package conflict provides a view for resolving files changed on both sides while syncing the store.
+// Each conflicting file keeps either the local version or the one pulled from the remote.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package conflict
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/gitstore"
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/Fozzyack/password-manager/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Conflict is a file changed on both sides, with a short description of each version
+type Conflict struct {
+	Path   string // Store-relative path of the file
+	Ours   string // Description of the local version, e.g. when it was last updated
+	Theirs string // Description of the remote version
+}
+
+// ConflictModel represents the state of the conflict resolution view
+type ConflictModel struct {
+	conflicts []Conflict
+	sides     []gitstore.Side
+	cursor    int
+	confirmed bool
+	cancelled bool
+	options   *types.Options
+}
+
+// Conflict view styling
+var (
+	conflictTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FFD700")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FFD700")).
+		Align(lipgloss.Center)
+
+	conflictContainerStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(80).
+		Align(lipgloss.Left)
+
+	conflictItemStyle = lipgloss.NewStyle().
+		Padding(0, 2)
+
+	conflictSelectedStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	conflictVersionStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(6)
+
+	conflictInfoStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFD700")).
+		Margin(0, 0, 1, 0)
+
+	conflictHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(4).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewConflictView creates a conflict view for the given files, defaulting every file to the local version
+func NewConflictView(conflicts []Conflict, options *types.Options) ConflictModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	return ConflictModel{
+		conflicts: conflicts,
+		sides:     make([]gitstore.Side, len(conflicts)),
+		options:   options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m ConflictModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles user input for the conflict view
+func (m ConflictModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			m.cancelled = true
+			m.options.Quit = false // Don't quit the entire app, just go back to the menu
+			return m, tea.Quit
+
+		case "enter":
+			m.confirmed = true
+			return m, tea.Quit
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.conflicts)-1 {
+				m.cursor++
+			}
+
+		case "right", "l", "left", "h", " ":
+			m.sides[m.cursor] = 1 - m.sides[m.cursor]
+
+		case "o":
+			m.sides[m.cursor] = gitstore.Ours
+		case "t":
+			m.sides[m.cursor] = gitstore.Theirs
+
+		case "O":
+			m.setAll(gitstore.Ours)
+		case "T":
+			m.setAll(gitstore.Theirs)
+		}
+	}
+
+	return m, nil
+}
+
+// setAll keeps the same side of every conflicting file
+func (m *ConflictModel) setAll(side gitstore.Side) {
+	for i := range m.sides {
+		m.sides[i] = side
+	}
+}
+
+// View renders the conflict view
+func (m ConflictModel) View() string {
+	var content strings.Builder
+
+	// Title
+	content.WriteString(conflictTitleStyle.Render("⚠️  Sync Conflicts") + "\n\n")
+
+	listContent := conflictInfoStyle.Render(fmt.Sprintf("%d files were changed both here and on the remote.\nChoose which version of each one to keep.", len(m.conflicts))) + "\n"
+
+	for i, c := range m.conflicts {
+		entryText := fmt.Sprintf("%-45s [%s]", utils.TruncateString(c.Path, 44), m.sides[i])
+		if i == m.cursor {
+			listContent += conflictSelectedStyle.Render("► "+entryText) + "\n"
+			listContent += conflictVersionStyle.Render("Ours:   "+c.Ours) + "\n"
+			listContent += conflictVersionStyle.Render("Theirs: "+c.Theirs) + "\n"
+		} else {
+			listContent += conflictItemStyle.Render("  "+entryText) + "\n"
+		}
+	}
+
+	content.WriteString(conflictContainerStyle.Render(listContent))
+
+	// Help text
+	help := conflictHelpStyle.Render("↑↓: Navigate • ←→/Space: Change • o/t: Ours/Theirs • O/T: All • Enter: Resolve • Esc: Cancel Sync")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// GetResolutions returns the side chosen for each conflicting file
+func (m ConflictModel) GetResolutions() map[string]gitstore.Side {
+	resolutions := make(map[string]gitstore.Side, len(m.conflicts))
+	for i, c := range m.conflicts {
+		resolutions[c.Path] = m.sides[i]
+	}
+	return resolutions
+}
+
+// IsConfirmed returns whether the user confirmed the resolutions
+func (m ConflictModel) IsConfirmed() bool {
+	return m.confirmed
+}
+
+// IsCancelled returns whether the sync was cancelled
+func (m ConflictModel) IsCancelled() bool {
+	return m.cancelled
+}
@@ -0,0 +1,250 @@
+// Package cryptoform provides the screen used to choose a store's encryption settings.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package cryptoform
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rows of the settings screen
+const (
+	profileRow = iota
+	cipherRow
+	aeadRow
+	s2kRow
+	memoryRow
+	passesRow
+	parallelismRow
+	rowCount
+)
+
+// Argon2 cost limits offered in the settings screen
+const (
+	maxPasses      = 10
+	maxParallelism = 16
+)
+
+// CryptoFormModel represents the state of the encryption settings screen
+type CryptoFormModel struct {
+	config    encryption.CryptoConfig
+	current   encryption.CryptoConfig
+	cursor    int
+	err       error
+	submitted bool
+	cancelled bool
+	options   *types.Options
+}
+
+// Settings screen styling
+var (
+	cryptoTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	cryptoContainerStyle = lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	rowStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	selectedRowStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	disabledRowStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		Foreground(lipgloss.Color("#626262"))
+
+	noteStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Margin(0, 0, 0, 1)
+
+	cryptoErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true).
+		Margin(0, 0, 0, 1)
+
+	cryptoHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewCryptoForm creates a settings screen starting from the store's current settings
+func NewCryptoForm(current encryption.CryptoConfig, options *types.Options) CryptoFormModel {
+	// Clear screen for clean form display
+	fmt.Print("\033[2J\033[H")
+
+	return CryptoFormModel{
+		config:  current,
+		current: current,
+		options: options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m CryptoFormModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles user input for the settings screen
+func (m CryptoFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc":
+		m.cancelled = true
+		m.options.Quit = false // Don't quit the entire app, just cancel the form
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j", "tab":
+		if m.cursor < rowCount-1 {
+			m.cursor++
+		}
+
+	case "left", "h":
+		m.config = m.cycle(-1)
+		m.err = m.config.Validate()
+
+	case "right", "l", " ":
+		m.config = m.cycle(1)
+		m.err = m.config.Validate()
+
+	case "d":
+		// Reset everything to the defaults of the selected profile
+		m.config = encryption.DefaultCryptoConfig(m.config.Profile)
+		m.err = nil
+
+	case "enter":
+		m.err = m.config.Validate()
+		if m.err == nil {
+			m.submitted = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// cycle moves the value of the selected row by delta
+func (m CryptoFormModel) cycle(delta int) encryption.CryptoConfig {
+	config := m.config
+	switch m.cursor {
+	case profileRow:
+		// A new profile starts from that profile's defaults
+		config = encryption.DefaultCryptoConfig(cycleValue(encryption.Profiles, config.Profile, delta))
+	case cipherRow:
+		config.Cipher = cycleValue(encryption.Ciphers, config.Cipher, delta)
+	case aeadRow:
+		config.AEAD = cycleValue(encryption.AEADModes, config.AEAD, delta)
+	case s2kRow:
+		config.S2K = cycleValue(encryption.S2KModes, config.S2K, delta)
+	case memoryRow:
+		config.Argon2MemoryKiB = cycleValue(encryption.Argon2Sizes, config.Argon2MemoryKiB, delta)
+	case passesRow:
+		config.Argon2Passes = uint8(clamp(int(config.Argon2Passes)+delta, 1, maxPasses))
+	case parallelismRow:
+		config.Argon2Parallelism = uint8(clamp(int(config.Argon2Parallelism)+delta, 1, maxParallelism))
+	}
+	return config
+}
+
+// cycleValue returns the value delta steps away from current, wrapping around
+func cycleValue[T comparable](values []T, current T, delta int) T {
+	index := slices.Index(values, current)
+	if index == -1 {
+		return values[0]
+	}
+	return values[(index+delta+len(values))%len(values)]
+}
+
+// clamp limits value to the range [low, high]
+func clamp(value, low, high int) int {
+	return max(low, min(value, high))
+}
+
+// View renders the settings screen
+func (m CryptoFormModel) View() string {
+	var content strings.Builder
+
+	// Title
+	title := cryptoTitleStyle.Render("🔐 Encryption Settings")
+	content.WriteString(title + "\n\n")
+
+	body := noteStyle.Render("Current: "+m.current.Describe()) + "\n\n"
+
+	rows := []string{
+		fmt.Sprintf("Profile             ◄ %-8s ►", m.config.Profile),
+		fmt.Sprintf("Cipher              ◄ %-8s ►", m.config.Cipher),
+		fmt.Sprintf("AEAD Mode           ◄ %-8s ►", m.config.AEAD),
+		fmt.Sprintf("Key Derivation      ◄ %-8s ►", m.config.S2K),
+		fmt.Sprintf("Argon2 Memory       ◄ %-8s ►", encryption.FormatKiB(m.config.Argon2MemoryKiB)),
+		fmt.Sprintf("Argon2 Passes       ◄ %-8d ►", m.config.Argon2Passes),
+		fmt.Sprintf("Argon2 Parallelism  ◄ %-8d ►", m.config.Argon2Parallelism),
+	}
+	for i, row := range rows {
+		switch {
+		case i == m.cursor:
+			body += selectedRowStyle.Render("► "+row) + "\n"
+		case i >= memoryRow && m.config.S2K != "argon2":
+			body += disabledRowStyle.Render("  "+row) + "\n"
+		default:
+			body += rowStyle.Render("  "+row) + "\n"
+		}
+	}
+	body += "\n"
+
+	if m.err != nil {
+		body += cryptoErrorStyle.Render("❌ "+m.err.Error()) + "\n\n"
+	}
+	body += noteStyle.Render("Applying re-encrypts every entry. Argon2 memory is needed on every\nunlock and save, so large values make the store slower to use.")
+
+	content.WriteString(cryptoContainerStyle.Render(body))
+
+	// Help text
+	help := cryptoHelpStyle.Render("↑↓: Setting • ←→: Change • d: Profile Defaults • Enter: Apply & Rekey • Esc: Cancel")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// GetConfig returns the chosen encryption settings
+func (m CryptoFormModel) GetConfig() encryption.CryptoConfig {
+	return m.config
+}
+
+// IsSubmitted returns whether the settings were applied
+func (m CryptoFormModel) IsSubmitted() bool {
+	return m.submitted
+}
+
+// IsCancelled returns whether the screen was cancelled
+func (m CryptoFormModel) IsCancelled() bool {
+	return m.cancelled
+}
@@ -1,12 +1,18 @@
 // Package detail provides a detailed view for displaying individual password entries.
+// The fields shown and their labels follow the template of the entry's type.
+// Custom fields and attachments are listed below the password; hidden fields are revealed one at a time.
 // It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
 package detail
 
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/Fozzyack/password-manager/clipboard"
 	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/otp"
+	"github.com/Fozzyack/password-manager/templates"
 	"github.com/Fozzyack/password-manager/types"
 	"github.com/Fozzyack/password-manager/utils"
 	tea "github.com/charmbracelet/bubbletea"
@@ -15,12 +21,32 @@ import (
 
 // DetailModel represents the state of the password detail view
 type DetailModel struct {
-	entry           encryption.Data
-	filename        string
-	siteName        string
-	showPassword    bool
-	deleteRequested bool
-	options         *types.Options
+	entry             encryption.Data
+	template          templates.Template // Template of the entry's type, which decides the labels
+	filename          string
+	siteName          string
+	showPassword      bool
+	revealed          []bool   // Which hidden custom fields are shown
+	cursor            int      // Selected item: 0 is the password, then custom fields, then attachments
+	saveRequested     bool
+	deleteRequested   bool
+	editRequested     bool
+	historyRequested  bool
+	nextCodeRequested bool
+	otpKey            *otp.Key // The entry's 2FA key, nil if it has none or it cannot be read
+	otpError          string   // Why the 2FA key cannot be read
+	statusMessage     string   // Feedback shown after copying to the clipboard
+	options           *types.Options
+}
+
+// tickMsg refreshes the TOTP code and its countdown every second
+type tickMsg time.Time
+
+// tick schedules the next refresh of the TOTP code
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
 }
 
 // Detail view styling
@@ -73,10 +99,38 @@ var (
 		Align(lipgloss.Center).
 		Margin(1, 0)
 
+	statusStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#90EE90")).
+		Bold(true).
+		Padding(0, 1)
+
 	timestampStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#626262")).
 		Italic(true).
 		Padding(0, 1)
+
+	otpCodeStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#90EE90")).
+		Bold(true).
+		Padding(0, 1)
+
+	otpExpiringStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true).
+		Padding(0, 1)
+
+	selectedLabelStyle = fieldLabelStyle.Copy().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#7D56F4"))
+
+	tagStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#87CEEB")).
+		Padding(0, 1)
+
+	customValueStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Padding(0, 1).
+		Width(45)
 )
 
 // NewPasswordDetail creates a new password detail view
@@ -84,24 +138,46 @@ func NewPasswordDetail(entry encryption.Data, filename, siteName string, options
 	// Clear screen for clean detail display
 	fmt.Print("\033[2J\033[H")
 
-	return DetailModel{
+	m := DetailModel{
 		entry:           entry,
+		template:        templates.For(entry.Type),
 		filename:        filename,
 		siteName:        siteName,
 		showPassword:    false,
+		revealed:        make([]bool, len(entry.Fields)),
 		deleteRequested: false,
 		options:         options,
 	}
+	// Entries without a password start on their first field
+	if !m.hasPassword() && len(entry.Fields)+len(entry.Attachments) > 0 {
+		m.cursor = 1
+	}
+	if entry.OTP != "" {
+		key, err := otp.Parse(entry.OTP)
+		if err != nil {
+			m.otpError = err.Error()
+		} else {
+			m.otpKey = &key
+		}
+	}
+	return m
 }
 
 // Init implements the tea.Model interface
 func (m DetailModel) Init() tea.Cmd {
+	// Time-based codes change while the entry is open
+	if m.otpKey != nil && m.otpKey.Type == otp.TOTP {
+		return tick()
+	}
 	return nil
 }
 
 // Update handles user input for the detail view
 func (m DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tickMsg:
+		return m, tick()
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "esc", "q", "backspace":
@@ -109,14 +185,81 @@ func (m DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case "v", " ":
-			// Toggle password visibility
-			m.showPassword = !m.showPassword
+			// Toggle the visibility of the password or the selected hidden field
+			if field, ok := m.selectedField(); ok {
+				m.revealed[field] = !m.revealed[field]
+			} else {
+				m.showPassword = !m.showPassword
+			}
+
+		case "up", "k", "shift+tab":
+			if m.cursor > 0 && (m.cursor > 1 || m.hasPassword()) {
+				m.cursor--
+				m.statusMessage = ""
+			}
+
+		case "down", "j", "tab":
+			if m.cursor < len(m.entry.Fields)+len(m.entry.Attachments) {
+				m.cursor++
+				m.statusMessage = ""
+			}
+
+		case "s", "S":
+			// Request saving the selected attachment to a file
+			if _, ok := m.selectedAttachment(); ok {
+				m.saveRequested = true
+				return m, tea.Quit
+			}
 
 		case "d", "D":
 			// Request deletion
 			m.deleteRequested = true
 			return m, tea.Quit
 
+		case "c", "C":
+			// Copy the password, or the selected custom field, to the clipboard
+			if field, ok := m.selectedField(); ok {
+				m.statusMessage = copyToClipboard(m.entry.Fields[field].Name, m.entry.Fields[field].Value)
+			} else if _, ok := m.selectedAttachment(); !ok && m.hasPassword() {
+				m.statusMessage = copyToClipboard(m.passwordName(), m.entry.Password)
+			}
+
+		case "u", "U":
+			// Copy the username (or email if there is no username) to the clipboard
+			if m.entry.Username != "" {
+				m.statusMessage = copyToClipboard("Username", m.entry.Username)
+			} else if m.entry.Email != "" {
+				m.statusMessage = copyToClipboard("Email", m.entry.Email)
+			}
+
+		case "e", "E":
+			// Request editing
+			m.editRequested = true
+			return m, tea.Quit
+
+		case "h", "H":
+			// Request the history of earlier versions
+			m.historyRequested = true
+			return m, tea.Quit
+
+		case "o", "O":
+			// Copy the current 2FA code to the clipboard
+			if m.otpKey != nil && m.otpKey.Type == otp.TOTP {
+				code, err := m.otpKey.Code(time.Now())
+				if err != nil {
+					m.statusMessage = fmt.Sprintf("❌ %v", err)
+				} else {
+					m.statusMessage = copyToClipboard("2FA code", code)
+				}
+			}
+
+		case "n", "N":
+			// Request the next HOTP code, which moves the counter on
+			if m.otpKey != nil && m.otpKey.Type == otp.HOTP {
+				m.nextCodeRequested = true
+				return m, tea.Quit
+			}
+
 		case "enter":
 			// Return to list (same as escape)
 			return m, tea.Quit
@@ -126,68 +269,158 @@ func (m DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// selectedField returns the position of the selected custom field, or false if the cursor is elsewhere
+func (m DetailModel) selectedField() (int, bool) {
+	field := m.cursor - 1
+	return field, field >= 0 && field < len(m.entry.Fields)
+}
+
+// selectedAttachment returns the position of the selected attachment, or false if the cursor is elsewhere
+func (m DetailModel) selectedAttachment() (int, bool) {
+	attachment := m.cursor - 1 - len(m.entry.Fields)
+	return attachment, attachment >= 0 && attachment < len(m.entry.Attachments)
+}
+
+// hasPassword returns whether the entry's template has a password and it is shown
+func (m DetailModel) hasPassword() bool {
+	field, ok := m.template.Standard(templates.KeyPassword)
+	return ok && (field.Required || m.entry.Password != "")
+}
+
+// passwordName returns what the entry's template calls the password, e.g. "PIN"
+func (m DetailModel) passwordName() string {
+	if field, ok := m.template.Standard(templates.KeyPassword); ok {
+		return field.Name
+	}
+	return "Password"
+}
+
+// standardLabel returns the label of a standard field, named as the entry's template names it
+func (m DetailModel) standardLabel(key, name string) string {
+	if field, ok := m.template.Standard(key); ok {
+		name = field.Name
+	}
+	return utils.TruncateString(name, 14) + ":"
+}
+
+// maskedValue returns the placeholder shown for a hidden custom field, keeping the end of the
+// value visible when the template says so, e.g. the last four digits of a card number
+func (m DetailModel) maskedValue(field encryption.Field) string {
+	masked := "••••••••••••••••"
+	spec, ok := m.template.Custom(field.Name)
+	value := strings.ReplaceAll(field.Value, " ", "")
+	if ok && spec.ShowLast > 0 && len(value) > spec.ShowLast {
+		masked = "•••• " + value[len(value)-spec.ShowLast:]
+	}
+	return masked
+}
+
+// label renders a field label, highlighted when the item at position is selected and
+// there is more than the password to choose from
+func (m DetailModel) label(text string, position int) string {
+	if position == m.cursor && len(m.entry.Fields)+len(m.entry.Attachments) > 0 {
+		return selectedLabelStyle.Render(text)
+	}
+	return fieldLabelStyle.Render(text)
+}
+
+// copyToClipboard copies the value using the shared clipboard manager and returns a status message
+func copyToClipboard(label, value string) string {
+	manager := clipboard.Default()
+	err := manager.Copy(value)
+	if err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	if manager.Timeout() > 0 {
+		return fmt.Sprintf("📋 %s copied - clipboard clears in %s", label, manager.Timeout())
+	}
+	return fmt.Sprintf("📋 %s copied", label)
+}
+
 // View renders the password detail interface
 func (m DetailModel) View() string {
 	var content strings.Builder
 
 	// Title
-	title := detailTitleStyle.Render("🔍 Password Details")
+	titleText := "🔍 Password Details"
+	if m.template.Type != encryption.EntryLogin {
+		titleText = m.template.Title()
+	}
+	if m.entry.Favourite {
+		titleText += " ⭐"
+	}
+	title := detailTitleStyle.Render(titleText)
 	content.WriteString(title + "\n\n")
 
 	// Detail content
 	detailContent := ""
 
 	// Site/Service Name
-	detailContent += fieldLabelStyle.Render("Site/Service:") + 
+	nameLabel := "Site/Service:"
+	if m.template.Type != encryption.EntryLogin {
+		nameLabel = "Name:"
+	}
+	detailContent += fieldLabelStyle.Render(nameLabel) + 
 		fieldValueStyle.Render(m.siteName) + "\n\n"
 
 	// Username
 	if m.entry.Username != "" {
-		detailContent += fieldLabelStyle.Render("Username:") + 
+		detailContent += fieldLabelStyle.Render(m.standardLabel(templates.KeyUsername, "Username")) + 
 			fieldValueStyle.Render(m.entry.Username) + "\n\n"
 	}
 
 	// Email
 	if m.entry.Email != "" {
-		detailContent += fieldLabelStyle.Render("Email:") + 
+		detailContent += fieldLabelStyle.Render(m.standardLabel(templates.KeyEmail, "Email")) + 
 			fieldValueStyle.Render(m.entry.Email) + "\n\n"
 	}
 
 	// URL
 	if m.entry.URL != "" {
-		detailContent += fieldLabelStyle.Render("URL:") + 
+		detailContent += fieldLabelStyle.Render(m.standardLabel(templates.KeyURL, "URL")) + 
 			fieldValueStyle.Render(m.entry.URL) + "\n\n"
 	}
 
-	// Password
-	passwordLabel := fieldLabelStyle.Render("Password:")
-	if m.showPassword {
-		passwordValue := passwordVisibleStyle.Render(m.entry.Password)
-		detailContent += passwordLabel + passwordValue + "\n"
-		
-		// Show password strength
-		strength, description := utils.EvaluatePasswordStrength(m.entry.Password)
-		var strengthColor string
-		switch strength {
-		case 0, 1:
-			strengthColor = "#FF5F87" // Red
-		case 2:
-			strengthColor = "#FFD700" // Yellow
-		case 3:
-			strengthColor = "#87CEEB" // Light Blue
-		case 4:
-			strengthColor = "#90EE90" // Light Green
+	// Tags
+	if len(m.entry.Tags) > 0 {
+		detailContent += fieldLabelStyle.Render("Tags:") + 
+			tagStyle.Render("#"+strings.Join(m.entry.Tags, " #")) + "\n\n"
+	}
+
+	// Password, named as the template names it; secure notes and the like have none
+	if m.hasPassword() {
+		detailContent += m.passwordView()
+	}
+
+	// 2FA code
+	if m.entry.OTP != "" {
+		detailContent += fieldLabelStyle.Render("2FA Code:") + m.otpView() + "\n\n"
+	}
+
+	// Custom fields
+	for i, field := range m.entry.Fields {
+		label := m.label(utils.TruncateString(field.Name, 13)+":", i+1)
+		shown := field.Value
+		if spec, ok := m.template.Custom(field.Name); ok && spec.Format != nil {
+			shown = spec.Format(shown)
 		}
-		
-		strengthText := strengthStyle.Copy().
-			Foreground(lipgloss.Color(strengthColor)).
-			Render(fmt.Sprintf("Strength: %s", description))
-		detailContent += fieldLabelStyle.Render("") + strengthText + "\n\n"
-	} else {
-		passwordValue := passwordHiddenStyle.Render("••••••••••••••••")
-		detailContent += passwordLabel + passwordValue + "\n"
-		detailContent += fieldLabelStyle.Render("") + 
-			passwordHiddenStyle.Render("Press 'v' or Space to reveal password") + "\n\n"
+		value := customValueStyle.Render(shown)
+		if field.Type == encryption.FieldHidden && !m.revealed[i] {
+			value = passwordHiddenStyle.Render(m.maskedValue(field))
+			if m.cursor == i+1 {
+				value += passwordHiddenStyle.Render("(v to reveal)")
+			}
+		} else if field.Type == encryption.FieldHidden {
+			value = passwordVisibleStyle.Render(shown)
+		}
+		detailContent += lipgloss.JoinHorizontal(lipgloss.Top, label, value) + "\n\n"
+	}
+
+	// Attachments
+	for i, attachment := range m.entry.Attachments {
+		label := m.label("Attachment:", i+1+len(m.entry.Fields))
+		value := fmt.Sprintf("📎 %s (%s)", attachment.Name, utils.FormatSize(len(attachment.Data)))
+		detailContent += label + fieldValueStyle.Render(value) + "\n\n"
 	}
 
 	// File information
@@ -205,14 +438,34 @@ func (m DetailModel) View() string {
 			timestampStyle.Render(m.entry.UpdatedAt.Format("Monday, January 2, 2006 at 3:04 PM")) + "\n\n"
 	}
 
+	// Clipboard feedback
+	if m.statusMessage != "" {
+		detailContent += "\n" + statusStyle.Render(m.statusMessage) + "\n"
+	}
+
 	content.WriteString(detailContainerStyle.Render(detailContent))
 
 	// Help text
 	var helpText string
 	if m.showPassword {
-		helpText = "v/Space: Hide Password • d: Delete • Esc/q/Backspace: Back to List • Enter: Back to List"
+		helpText = "v/Space: Hide Password • c: Copy Password • u: Copy Username • e: Edit • h: History • d: Delete • Esc/q/Backspace: Back to List"
 	} else {
-		helpText = "v/Space: Show Password • d: Delete • Esc/q/Backspace: Back to List • Enter: Back to List"
+		helpText = "v/Space: Show Password • c: Copy Password • u: Copy Username • e: Edit • h: History • d: Delete • Esc/q/Backspace: Back to List"
+	}
+	
+	if m.template.Type != encryption.EntryLogin {
+		helpText = strings.ReplaceAll(helpText, "Password", m.passwordName())
+	}
+	if len(m.entry.Fields)+len(m.entry.Attachments) > 0 {
+		helpText = "↑↓: Select • v/Space: Show/Hide • c: Copy Selected • u: Copy Username • e: Edit • h: History • d: Delete • Esc/q: Back to List"
+		if _, ok := m.selectedAttachment(); ok {
+			helpText = strings.Replace(helpText, " • e: Edit", " • s: Save File • e: Edit", 1)
+		}
+	}
+	if m.otpKey != nil && m.otpKey.Type == otp.TOTP {
+		helpText = strings.Replace(helpText, " • e: Edit", " • o: Copy 2FA Code • e: Edit", 1)
+	} else if m.otpKey != nil {
+		helpText = strings.Replace(helpText, " • e: Edit", " • n: Next 2FA Code • e: Edit", 1)
 	}
 	
 	help := detailHelpStyle.Render(helpText)
@@ -221,6 +474,69 @@ func (m DetailModel) View() string {
 	return content.String()
 }
 
+// passwordView renders the password, hidden until revealed, with its strength when the
+// user chose it
+func (m DetailModel) passwordView() string {
+	content := ""
+	passwordLabel := m.label(m.standardLabel(templates.KeyPassword, "Password"), 0)
+	if m.showPassword && !m.template.Strength {
+		passwordValue := passwordVisibleStyle.Render(m.entry.Password)
+		content += passwordLabel + passwordValue + "\n\n"
+	} else if m.showPassword {
+		passwordValue := passwordVisibleStyle.Render(m.entry.Password)
+		content += passwordLabel + passwordValue + "\n"
+		
+		// Show password strength
+		strength, description := utils.EvaluatePasswordStrength(m.entry.Password)
+		var strengthColor string
+		switch strength {
+		case 0, 1:
+			strengthColor = "#FF5F87" // Red
+		case 2:
+			strengthColor = "#FFD700" // Yellow
+		case 3:
+			strengthColor = "#87CEEB" // Light Blue
+		case 4:
+			strengthColor = "#90EE90" // Light Green
+		}
+		
+		strengthText := strengthStyle.Copy().
+			Foreground(lipgloss.Color(strengthColor)).
+			Render(fmt.Sprintf("Strength: %s", description))
+		content += fieldLabelStyle.Render("") + strengthText + "\n\n"
+	} else {
+		passwordValue := passwordHiddenStyle.Render("••••••••••••••••")
+		content += passwordLabel + passwordValue + "\n"
+		content += fieldLabelStyle.Render("") + 
+			passwordHiddenStyle.Render("Press 'v' or Space to reveal "+strings.ToLower(m.passwordName())) + "\n\n"
+	}
+	return content
+}
+
+// otpView renders the current TOTP code with the seconds it stays valid for, or how to get
+// the next HOTP code
+func (m DetailModel) otpView() string {
+	if m.otpKey == nil {
+		return passwordHiddenStyle.Render("🔒 " + m.otpError)
+	}
+	if m.otpKey.Type == otp.HOTP {
+		return passwordHiddenStyle.Render(fmt.Sprintf("Press 'n' for the next code (counter %d)", m.otpKey.Counter))
+	}
+
+	now := time.Now()
+	code, err := m.otpKey.Code(now)
+	if err != nil {
+		return passwordHiddenStyle.Render("🔒 " + err.Error())
+	}
+	remaining := int(m.otpKey.Remaining(now).Seconds())
+	filled := remaining * 10 / m.otpKey.Period
+	countdown := fmt.Sprintf("%s%s %2ds", strings.Repeat("█", filled), strings.Repeat("░", 10-filled), remaining)
+	if remaining <= 5 {
+		return otpExpiringStyle.Render(otp.Format(code)) + otpExpiringStyle.Render(countdown)
+	}
+	return otpCodeStyle.Render(otp.Format(code)) + timestampStyle.Render(countdown)
+}
+
 // IsPasswordVisible returns whether the password is currently visible
 func (m DetailModel) IsPasswordVisible() bool {
 	return m.showPassword
@@ -230,3 +546,30 @@ func (m DetailModel) IsPasswordVisible() bool {
 func (m DetailModel) IsDeletionRequested() bool {
 	return m.deleteRequested
 }
+
+
+// IsEditRequested returns whether editing was requested for this entry
+func (m DetailModel) IsEditRequested() bool {
+	return m.editRequested
+}
+
+// IsHistoryRequested returns whether the history of this entry was requested
+func (m DetailModel) IsHistoryRequested() bool {
+	return m.historyRequested
+}
+
+// IsSaveRequested returns whether the selected attachment should be saved to a file
+func (m DetailModel) IsSaveRequested() bool {
+	return m.saveRequested
+}
+
+// GetSelectedAttachment returns the position of the selected attachment in the entry's attachments
+func (m DetailModel) GetSelectedAttachment() int {
+	attachment, _ := m.selectedAttachment()
+	return attachment
+}
+
+// IsNextCodeRequested returns whether the next code of the entry's HOTP key was requested
+func (m DetailModel) IsNextCodeRequested() bool {
+	return m.nextCodeRequested
+}
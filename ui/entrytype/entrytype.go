@@ -0,0 +1,137 @@
+// Package entrytype provides the view choosing what kind of entry to add, such as a login,
+// a credit card or a secure note. Each kind has its own form from the templates package.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package entrytype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/templates"
+	"github.com/Fozzyack/password-manager/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EntryTypeModel represents the state of the entry type picker
+type EntryTypeModel struct {
+	cursor   int
+	selected bool
+	options  *types.Options
+}
+
+// Entry type picker styling
+var (
+	typeTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	typeContainerStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	typeItemStyle = lipgloss.NewStyle().
+		Padding(0, 2)
+
+	typeSelectedStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	typeDescriptionStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(7)
+
+	typeHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(4).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewEntryTypePicker creates the picker with logins selected
+func NewEntryTypePicker(options *types.Options) EntryTypeModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	return EntryTypeModel{options: options}
+}
+
+// Init implements the tea.Model interface
+func (m EntryTypeModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles user input for the picker
+func (m EntryTypeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc", "q":
+		m.options.Quit = false // Don't quit the entire app, just go back to the menu
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(templates.All)-1 {
+			m.cursor++
+		}
+
+	case "enter", " ":
+		m.selected = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// View renders the picker
+func (m EntryTypeModel) View() string {
+	var content strings.Builder
+
+	// Title
+	content.WriteString(typeTitleStyle.Render("➕ Add New Password") + "\n\n")
+
+	body := ""
+	for i, template := range templates.All {
+		if i != m.cursor {
+			body += typeItemStyle.Render("  "+template.Title()) + "\n"
+			continue
+		}
+		body += typeSelectedStyle.Render("► "+template.Title()) + "\n"
+		body += typeDescriptionStyle.Render(template.Description) + "\n"
+	}
+	content.WriteString(typeContainerStyle.Render(body))
+
+	// Help text
+	help := typeHelpStyle.Render("↑↓: Navigate • Enter: Choose • Esc: Back")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// IsSelected returns whether a type was chosen rather than the picker closed
+func (m EntryTypeModel) IsSelected() bool {
+	return m.selected
+}
+
+// GetSelectedTemplate returns the template of the chosen type
+func (m EntryTypeModel) GetSelectedTemplate() templates.Template {
+	return templates.All[m.cursor]
+}
@@ -0,0 +1,242 @@
+// Package exportform provides the form used to choose the format and destination of a password export.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package exportform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Focusable sections of the export form
+const (
+	formatSection = iota
+	pathSection
+)
+
+// ExportFormModel represents the state of the export form
+type ExportFormModel struct {
+	formats   []export.Format
+	format    int
+	pathInput textinput.Model
+	focus     int
+	submitted bool
+	cancelled bool
+	options   *types.Options
+}
+
+// Export form styling
+var (
+	exportTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	exportContainerStyle = lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	exportLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Margin(0, 0, 0, 1)
+
+	optionStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#626262")).
+		Padding(0, 2).
+		Margin(0, 1)
+
+	selectedOptionStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#7D56F4")).
+		Padding(0, 2).
+		Margin(0, 1).
+		Bold(true)
+
+	exportHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+
+	exportErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true).
+		Margin(0, 0, 1, 1)
+)
+
+// NewExportForm creates a new export form defaulting to CSV in the user's home directory
+func NewExportForm(options *types.Options) ExportFormModel {
+	// Clear screen for clean form display
+	fmt.Print("\033[2J\033[H")
+
+	ti := textinput.New()
+	ti.Placeholder = "Destination file path"
+	ti.CharLimit = 300
+	ti.Width = 50
+	ti.SetValue(export.DefaultPath(export.FormatCSV))
+
+	// Style the textinput
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+
+	return ExportFormModel{
+		formats:   []export.Format{export.FormatCSV, export.FormatJSON},
+		format:    0,
+		pathInput: ti,
+		focus:     formatSection,
+		options:   options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m ExportFormModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles user input for the export form
+func (m ExportFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.cancelled = true
+			m.options.Quit = false // Don't quit the entire app, just cancel the form
+			return m, tea.Quit
+
+		case "tab", "shift+tab", "up", "down":
+			// Switch focus between the format selector and the path input
+			return m.toggleFocus()
+
+		case "enter":
+			if m.focus == formatSection {
+				return m.toggleFocus()
+			}
+			if strings.TrimSpace(m.pathInput.Value()) != "" {
+				m.submitted = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		if m.focus == formatSection {
+			switch msg.String() {
+			case "left", "h":
+				m.setFormat(0)
+			case "right", "l":
+				m.setFormat(1)
+			case " ":
+				m.setFormat(1 - m.format)
+			}
+			return m, nil
+		}
+	}
+
+	// Update the path input when it has focus
+	var cmd tea.Cmd
+	if m.focus == pathSection {
+		m.pathInput, cmd = m.pathInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// toggleFocus moves focus to the other section of the form
+func (m ExportFormModel) toggleFocus() (tea.Model, tea.Cmd) {
+	if m.focus == formatSection {
+		m.focus = pathSection
+		m.pathInput.Focus()
+		return m, m.pathInput.Cursor.BlinkCmd()
+	}
+	m.focus = formatSection
+	m.pathInput.Blur()
+	return m, nil
+}
+
+// setFormat selects a format and swaps the extension of the destination path to match
+func (m *ExportFormModel) setFormat(index int) {
+	previous := m.formats[m.format]
+	m.format = index
+	current := m.formats[m.format]
+
+	path := m.pathInput.Value()
+	if strings.HasSuffix(path, "."+string(previous)) {
+		m.pathInput.SetValue(strings.TrimSuffix(path, string(previous)) + string(current))
+	}
+}
+
+// View renders the export form interface
+func (m ExportFormModel) View() string {
+	var content strings.Builder
+
+	// Title
+	title := exportTitleStyle.Render("📤 Export Passwords")
+	content.WriteString(title + "\n\n")
+
+	formContent := ""
+
+	// Format selector
+	formContent += exportLabelStyle.Render("Format") + "\n"
+	formContent += "  "
+	for i, format := range m.formats {
+		label := strings.ToUpper(string(format))
+		if i == m.format {
+			if m.focus == formatSection {
+				label = "► " + label
+			}
+			formContent += selectedOptionStyle.Render(label)
+		} else {
+			formContent += optionStyle.Render(label)
+		}
+	}
+	formContent += "\n\n"
+
+	// Destination path
+	formContent += exportLabelStyle.Render("Destination") + "\n"
+	formContent += "  " + m.pathInput.View() + "\n\n"
+
+	if m.focus == pathSection && strings.TrimSpace(m.pathInput.Value()) == "" {
+		formContent += exportErrorStyle.Render("❌ 'Destination' is required") + "\n\n"
+	}
+
+	content.WriteString(exportContainerStyle.Render(formContent))
+
+	// Help text
+	help := exportHelpStyle.Render("←→: Choose Format • Tab/↑↓: Switch Field • Enter: Next/Export • Esc: Cancel")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// GetFormat returns the selected export format
+func (m ExportFormModel) GetFormat() export.Format {
+	return m.formats[m.format]
+}
+
+// GetPath returns the destination path entered by the user
+func (m ExportFormModel) GetPath() string {
+	return strings.TrimSpace(m.pathInput.Value())
+}
+
+// IsSubmitted returns whether the form was successfully submitted
+func (m ExportFormModel) IsSubmitted() bool {
+	return m.submitted
+}
+
+// IsCancelled returns whether the form was cancelled
+func (m ExportFormModel) IsCancelled() bool {
+	return m.cancelled
+}
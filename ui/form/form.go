@@ -1,4 +1,7 @@
 // Package form provides a multi-field input form for adding new password entries.
+// The fields come from the template of the entry's type, and custom fields and file
+// attachments can be added to and removed from an entry with the form, as can its tags and
+// whether it is a favourite.
 // It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
 package form
 
@@ -6,7 +9,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/templates"
 	"github.com/Fozzyack/password-manager/types"
+	"github.com/Fozzyack/password-manager/ui/generator"
+	"github.com/Fozzyack/password-manager/utils"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -14,20 +22,35 @@ import (
 
 // FormField represents a single input field in the form
 type FormField struct {
+	Key         string                 // Key of the value in GetFormData, derived from Label when empty
 	Label       string
 	Placeholder string
 	Required    bool
 	Masked      bool
+	ReadOnly    bool                   // Read-only fields are shown but skipped during navigation
+	Validate    func(string) error     // Optional check of a non-empty value
+	Custom      bool                   // Stored as a custom field; Label is the field's name
+	Fixed       bool                   // A custom field of the entry's template, which cannot be removed
+	Type        encryption.FieldType   // What a custom field holds
+	Attachment  *encryption.Attachment // The file an attachment row stands for, nil for other fields
 	Value       string
 }
 
 // FormModel represents the state of the multi-field form
 type FormModel struct {
+	title        string
+	template     templates.Template // Template of the entry's type, which the fields come from
 	fields       []FormField
 	inputs       []textinput.Model
+	areas        []textarea.Model // Multi-line input of notes fields, alongside inputs
 	currentField int
+	favourite    bool // Whether the entry is pinned to the top of the list, toggled with Ctrl+F
 	submitted    bool
 	cancelled    bool
+	generating   bool             // Whether the password generator panel is open
+	generator    generator.GeneratorModel
+	adding       bool             // Whether the new field panel is open
+	panel        fieldPanel
 	options      *types.Options
 }
 
@@ -58,6 +81,10 @@ var (
 		Foreground(lipgloss.Color("#FF5F87")).
 		Bold(true)
 
+	readOnlyStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true)
+
 	helpStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#626262")).
 		Italic(true).
@@ -73,71 +100,76 @@ var (
 
 // NewPasswordForm creates a new password entry form with predefined fields
 func NewPasswordForm(options *types.Options) FormModel {
+	return NewTemplateForm(templates.For(encryption.EntryLogin), options)
+}
+
+// NewTemplateForm creates a new entry form with the fields of a template
+func NewTemplateForm(template templates.Template, options *types.Options) FormModel {
 	// Clear screen for clean form display
 	fmt.Print("\033[2J\033[H")
 
 	fields := []FormField{
 		{
-			Label:       "Site/Service Name",
-			Placeholder: "e.g., Gmail, GitHub, Banking",
+			Key:         "site_service_name",
+			Label:       template.NameLabel,
+			Placeholder: template.NamePlaceholder,
 			Required:    true,
 			Masked:      false,
 		},
 		{
-			Label:       "Username",
-			Placeholder: "your_username",
+			Label:       "Folder",
+			Placeholder: "e.g., work/aws (optional)",
 			Required:    false,
 			Masked:      false,
 		},
 		{
-			Label:       "Email",
-			Placeholder: "user@example.com",
+			Label:       "Tags",
+			Placeholder: "e.g., work, finance (comma separated, optional)",
 			Required:    false,
 			Masked:      false,
 		},
-		{
-			Label:       "URL",
-			Placeholder: "https://example.com",
-			Required:    false,
-			Masked:      false,
-		},
-		{
-			Label:       "Password",
-			Placeholder: "Enter password or generate one",
-			Required:    true,
-			Masked:      true,
-		},
+	}
+	for _, field := range template.Fields {
+		fieldType := field.Type
+		if fieldType == "" {
+			fieldType = encryption.FieldText
+		}
+		fields = append(fields, FormField{
+			Key:         field.Key,
+			Label:       field.Name,
+			Placeholder: field.Placeholder,
+			Required:    field.Required,
+			Masked:      fieldType == encryption.FieldHidden,
+			Validate:    field.Validate,
+			Custom:      field.Key == "",
+			Fixed:       field.Key == "",
+			Type:        fieldType,
+		})
 	}
 
 	inputs := make([]textinput.Model, len(fields))
+	areas := make([]textarea.Model, len(fields))
 	for i := range inputs {
-		ti := textinput.New()
-		ti.Placeholder = fields[i].Placeholder
-		ti.CharLimit = 200
-		ti.Width = 50
-
-		// Style the textinput
-		ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
-		ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
-		ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
-
-		// Set password masking for password field
-		if fields[i].Masked {
-			ti.EchoMode = textinput.EchoPassword
-			ti.EchoCharacter = '•'
-		}
+		inputs[i] = newInput(fields[i])
+		areas[i] = newArea()
 
 		// Focus on the first field
 		if i == 0 {
-			ti.Focus()
+			inputs[i].Focus()
 		}
+	}
 
-		inputs[i] = ti
+	title := "➕ Add New Password Entry"
+	if template.Type != encryption.EntryLogin {
+		title = "➕ New " + template.Title()
 	}
 
 	return FormModel{
+		title:        title,
+		template:     template,
 		fields:       fields,
 		inputs:       inputs,
+		areas:        areas,
 		currentField: 0,
 		submitted:    false,
 		cancelled:    false,
@@ -145,6 +177,194 @@ func NewPasswordForm(options *types.Options) FormModel {
 	}
 }
 
+// newInput creates the single-line input of a field
+func newInput(field FormField) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = field.Placeholder
+	ti.CharLimit = 200
+	if field.Validate != nil || field.Custom {
+		ti.CharLimit = 500 // otpauth URIs and API keys can be long
+	}
+	ti.Width = 50
+
+	// Style the textinput
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+
+	// Set password masking for password and hidden fields
+	if field.Masked {
+		ti.EchoMode = textinput.EchoPassword
+		ti.EchoCharacter = '•'
+	}
+	return ti
+}
+
+// newArea creates the multi-line input used by notes fields
+func newArea() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "Type or paste notes, Enter for a new line"
+	ta.CharLimit = 0
+	ta.SetWidth(54)
+	ta.SetHeight(4)
+	ta.ShowLineNumbers = false
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.FocusedStyle.Placeholder = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+	ta.BlurredStyle.Placeholder = ta.FocusedStyle.Placeholder
+	return ta
+}
+
+// addCustomField adds a custom field at the end of the form and returns its position
+func (m *FormModel) addCustomField(name string, fieldType encryption.FieldType, value string) int {
+	field := FormField{
+		Label:       name,
+		Placeholder: fieldType.Label() + " value",
+		Masked:      fieldType == encryption.FieldHidden,
+		Custom:      true,
+		Type:        fieldType,
+		Value:       value,
+	}
+	input := newInput(field)
+	input.SetValue(value)
+	area := newArea()
+	area.SetValue(value)
+
+	m.fields = append(m.fields, field)
+	m.inputs = append(m.inputs, input)
+	m.areas = append(m.areas, area)
+	return len(m.fields) - 1
+}
+
+// addAttachment adds a row for an attached file at the end of the form and returns its position
+func (m *FormModel) addAttachment(attachment encryption.Attachment) int {
+	field := FormField{
+		Label:      "📎 Attachment",
+		Attachment: &attachment,
+		Value:      fmt.Sprintf("%s (%s)", attachment.Name, utils.FormatSize(len(attachment.Data))),
+	}
+	input := newInput(field)
+	input.SetValue(field.Value)
+
+	m.fields = append(m.fields, field)
+	m.inputs = append(m.inputs, input)
+	m.areas = append(m.areas, newArea())
+	return len(m.fields) - 1
+}
+
+// isRemovable returns whether a field was added to the entry rather than coming from its template
+func (m FormModel) isRemovable(field int) bool {
+	return (m.fields[field].Custom && !m.fields[field].Fixed) || m.fields[field].Attachment != nil
+}
+
+// removeField removes a custom field or attachment from the form
+func (m FormModel) removeField(field int) (tea.Model, tea.Cmd) {
+	m.fields = append(m.fields[:field:field], m.fields[field+1:]...)
+	m.inputs = append(m.inputs[:field:field], m.inputs[field+1:]...)
+	m.areas = append(m.areas[:field:field], m.areas[field+1:]...)
+	m.currentField = min(field, len(m.fields)-1)
+	m.focus()
+	return m, nil
+}
+
+// setValue sets the value of a field and its input or area
+func (m *FormModel) setValue(field int, value string) {
+	m.fields[field].Value = value
+	m.inputs[field].SetValue(value)
+	m.areas[field].SetValue(value)
+}
+
+// fixedField returns the position of the template's empty custom field with the given name, or -1
+func (m FormModel) fixedField(name string) int {
+	for i, field := range m.fields {
+		if field.Fixed && field.Label == name && field.Value == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// passwordField returns the position of the field stored as the password, or -1 if the
+// template has none
+func (m FormModel) passwordField() int {
+	for i, field := range m.fields {
+		if field.Key == templates.KeyPassword {
+			return i
+		}
+	}
+	return -1
+}
+
+// isNotes returns whether a field is entered in a multi-line area
+func (m FormModel) isNotes(field int) bool {
+	return m.fields[field].Custom && m.fields[field].Type == encryption.FieldNotes
+}
+
+// value returns the current value of a field from its input or area
+func (m FormModel) value(field int) string {
+	if m.isNotes(field) {
+		return m.areas[field].Value()
+	}
+	return m.inputs[field].Value()
+}
+
+// NewPasswordFormInFolder creates a new password entry form with the folder filled in
+func NewPasswordFormInFolder(folder string, options *types.Options) FormModel {
+	m := NewPasswordForm(options)
+	m.fields[1].Value = folder
+	m.inputs[1].SetValue(folder)
+	return m
+}
+
+// NewEditPasswordForm creates a form of the entry's template pre-populated with an existing
+// entry, including its custom fields and attachments. The site name and folder are read-only
+// because the entry keeps its filename when edited.
+func NewEditPasswordForm(siteName, folder string, entry encryption.Data, options *types.Options) FormModel {
+	template := templates.For(entry.Type)
+	m := NewTemplateForm(template, options)
+	m.title = "✏️  Edit Password Entry"
+	if template.Type != encryption.EntryLogin {
+		m.title = "✏️  Edit " + template.Title()
+	}
+
+	values := map[string]string{
+		"site_service_name":   siteName,
+		"folder":              folder,
+		"tags":                utils.FormatTags(entry.Tags),
+		templates.KeyUsername: entry.Username,
+		templates.KeyEmail:    entry.Email,
+		templates.KeyURL:      entry.URL,
+		templates.KeyPassword: entry.Password,
+		templates.KeyOTP:      entry.OTP,
+	}
+	for i := range m.fields {
+		if !m.fields[i].Custom {
+			m.setValue(i, values[m.fields[i].key()])
+		}
+	}
+
+	m.favourite = entry.Favourite
+
+	// Values of the template's own fields go in their rows, the others are added after them
+	for _, field := range entry.Fields {
+		if i := m.fixedField(field.Name); i >= 0 {
+			m.setValue(i, field.Value)
+			continue
+		}
+		m.addCustomField(field.Name, field.Type, field.Value)
+	}
+	for _, attachment := range entry.Attachments {
+		m.addAttachment(attachment)
+	}
+
+	// Lock the site name and folder and start on the first editable field
+	m.fields[0].ReadOnly = true
+	m.fields[1].ReadOnly = true
+	m.inputs[0].Blur()
+	m.currentField = 2
+	m.focus()
+	return m
+}
+
 // Init implements the tea.Model interface
 func (m FormModel) Init() tea.Cmd {
 	return textinput.Blink
@@ -152,81 +372,193 @@ func (m FormModel) Init() tea.Cmd {
 
 // Update handles user input and form navigation
 func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Route key presses to the generator panel while it is open
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.generating {
+		if keyMsg.String() == "ctrl+c" {
+			m.cancelled = true
+			m.options.Quit = false
+			return m, tea.Quit
+		}
+		m.generator = m.generator.Update(keyMsg)
+		if m.generator.IsClosed() {
+			m.generating = false
+			if m.generator.IsAccepted() {
+				m.inputs[m.passwordField()].SetValue(m.generator.GetPassword())
+				return m.moveTo(m.passwordField())
+			}
+		}
+		return m, nil
+	}
+
+	// Route key presses to the new field panel while it is open
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.adding {
+		if keyMsg.String() == "ctrl+c" {
+			m.cancelled = true
+			m.options.Quit = false
+			return m, tea.Quit
+		}
+		m.panel = m.panel.Update(keyMsg)
+		if m.panel.IsClosed() {
+			m.adding = false
+			if m.panel.IsAccepted() && m.panel.isFile() {
+				return m.moveTo(m.addAttachment(*m.panel.attachment))
+			}
+			if m.panel.IsAccepted() {
+				return m.moveTo(m.addCustomField(m.panel.name(), m.panel.fieldType(), ""))
+			}
+			m.focus()
+		}
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "ctrl+g":
+			// Open the password generator panel, unless the entry has no password
+			if m.passwordField() < 0 {
+				return m, nil
+			}
+			m.generator = generator.NewGenerator()
+			m.generating = true
+			return m, nil
+
+		case "ctrl+n":
+			// Open the panel to add a custom field or attach a file
+			m.inputs[m.currentField].Blur()
+			m.areas[m.currentField].Blur()
+			m.panel = newFieldPanel()
+			m.adding = true
+			return m, textinput.Blink
+
+		case "ctrl+x":
+			// Remove the current custom field or attachment
+			if m.isRemovable(m.currentField) {
+				return m.removeField(m.currentField)
+			}
+			return m, nil
+
+		case "ctrl+f":
+			m.favourite = !m.favourite
+			return m, nil
+
+		case "ctrl+s":
+			return m.submit()
+
 		case "ctrl+c", "esc":
 			m.cancelled = true
 			m.options.Quit = false // Don't quit the entire app, just cancel the form
 			return m, tea.Quit
 
 		case "enter":
+			// Notes take new lines
+			if m.isNotes(m.currentField) {
+				break
+			}
 			// Move to next field or submit if on last field
 			if m.currentField < len(m.inputs)-1 {
-				m.inputs[m.currentField].Blur()
-				m.currentField++
-				m.inputs[m.currentField].Focus()
-				return m, m.inputs[m.currentField].Cursor.BlinkCmd()
+				return m.moveTo(m.currentField + 1)
 			} else {
-				// Validate required fields before submitting
-				if m.validateForm() {
-					// Update field values
-					for i := range m.fields {
-						m.fields[i].Value = m.inputs[i].Value()
-					}
-					m.submitted = true
-					return m, tea.Quit
-				}
-				// If validation fails, stay on current field
-				return m, nil
+				return m.submit()
 			}
 
 		case "tab", "shift+tab", "up", "down":
+			// Arrow keys move between the lines of notes
+			if m.isNotes(m.currentField) && (msg.String() == "up" || msg.String() == "down") {
+				break
+			}
 			// Navigate between fields
 			if msg.String() == "up" || msg.String() == "shift+tab" {
-				if m.currentField > 0 {
-					m.inputs[m.currentField].Blur()
-					m.currentField--
-					m.inputs[m.currentField].Focus()
-					return m, m.inputs[m.currentField].Cursor.BlinkCmd()
+				if m.currentField > 0 && !m.fields[m.currentField-1].ReadOnly {
+					return m.moveTo(m.currentField - 1)
 				}
 			} else {
 				if m.currentField < len(m.inputs)-1 {
-					m.inputs[m.currentField].Blur()
-					m.currentField++
-					m.inputs[m.currentField].Focus()
-					return m, m.inputs[m.currentField].Cursor.BlinkCmd()
+					return m.moveTo(m.currentField + 1)
 				}
 			}
 		}
 	}
 
+	// Attachments are only added and removed, not typed into
+	if m.fields[m.currentField].Attachment != nil {
+		return m, nil
+	}
+
 	// Update the current input field
 	var cmd tea.Cmd
-	m.inputs[m.currentField], cmd = m.inputs[m.currentField].Update(msg)
+	if m.isNotes(m.currentField) {
+		m.areas[m.currentField], cmd = m.areas[m.currentField].Update(msg)
+	} else {
+		m.inputs[m.currentField], cmd = m.inputs[m.currentField].Update(msg)
+	}
 	return m, cmd
 }
 
+// submit saves the field values and closes the form if it is valid
+func (m FormModel) submit() (tea.Model, tea.Cmd) {
+	// Validate required fields before submitting
+	if !m.validateForm() {
+		// If validation fails, stay on current field
+		return m, nil
+	}
+	for i := range m.fields {
+		if m.fields[i].Attachment == nil {
+			m.fields[i].Value = m.value(i)
+		}
+	}
+	m.submitted = true
+	return m, tea.Quit
+}
+
+// moveTo moves focus to the given field
+func (m FormModel) moveTo(field int) (tea.Model, tea.Cmd) {
+	m.inputs[m.currentField].Blur()
+	m.areas[m.currentField].Blur()
+	m.currentField = field
+	return m, m.focus()
+}
+
+// focus focuses the input or area of the current field
+func (m *FormModel) focus() tea.Cmd {
+	if m.isNotes(m.currentField) {
+		return m.areas[m.currentField].Focus()
+	}
+	m.inputs[m.currentField].Focus()
+	return m.inputs[m.currentField].Cursor.BlinkCmd()
+}
+
 // View renders the form interface
 func (m FormModel) View() string {
 	var content strings.Builder
 
 	// Title
-	title := formTitleStyle.Render("➕ Add New Password Entry")
+	title := formTitleStyle.Render(m.title)
 	content.WriteString(title + "\n\n")
 
 	// Form fields
 	formContent := ""
+	if m.favourite {
+		formContent += fieldLabelStyle.Render("⭐ Favourite") + readOnlyStyle.Render(" - pinned to the top of the list") + "\n\n"
+	} else {
+		formContent += readOnlyStyle.Render(" ☆ Not a favourite - Ctrl+F to pin it to the top of the list") + "\n\n"
+	}
 	for i, field := range m.fields {
 		// Field label
 		label := field.Label
-		if field.Required {
+		if field.ReadOnly {
+			label += readOnlyStyle.Render(" (read-only)")
+		} else if field.Required {
 			label += requiredStyle.Render(" *")
+		} else if field.Custom && !field.Fixed {
+			label += readOnlyStyle.Render(" (" + field.Type.Label() + ")")
 		}
 		formContent += fieldLabelStyle.Render(label) + "\n"
 
 		// Input field with focus styling
-		if i == m.currentField {
+		if m.isNotes(i) {
+			formContent += lipgloss.NewStyle().PaddingLeft(2).Render(m.areas[i].View()) + "\n"
+		} else if i == m.currentField {
 			formContent += "  " + m.inputs[i].View() + "\n"
 		} else {
 			formContent += "  " + m.inputs[i].View() + "\n"
@@ -246,46 +578,127 @@ func (m FormModel) View() string {
 
 	content.WriteString(formContainerStyle.Render(formContent))
 
+	// Generator panel
+	if m.generating {
+		content.WriteString("\n" + m.generator.View() + "\n")
+		return content.String()
+	}
+
+	// New field panel
+	if m.adding {
+		content.WriteString("\n" + m.panel.View() + "\n")
+		return content.String()
+	}
+
 	// Help text
-	help := helpStyle.Render("Tab/Enter: Next field • ↑↓: Navigate • Enter on last field: Save • Esc: Cancel")
+	helpText := "Tab/Enter: Next field • ↑↓: Navigate • Ctrl+G: Generate Password • Ctrl+N: Add Field/File • Ctrl+F: Favourite • Enter on last field or Ctrl+S: Save • Esc: Cancel"
+	if m.passwordField() < 0 {
+		helpText = strings.Replace(helpText, " • Ctrl+G: Generate Password", "", 1)
+	}
+	if m.isRemovable(m.currentField) {
+		helpText = strings.Replace(helpText, " • Ctrl+N", " • Ctrl+X: Remove • Ctrl+N", 1)
+	}
+	if m.isNotes(m.currentField) {
+		helpText = "Enter: New line • Tab: Next field • Ctrl+F: Favourite • Ctrl+S: Save • Esc: Cancel"
+		if m.isRemovable(m.currentField) {
+			helpText = strings.Replace(helpText, " • Ctrl+S", " • Ctrl+X: Remove • Ctrl+S", 1)
+		}
+	}
+	help := helpStyle.Render(helpText)
 	content.WriteString(help)
 
 	return content.String()
 }
 
-// validateForm checks if all required fields are filled
+// validateForm checks if all required fields are filled and the others are valid
 func (m FormModel) validateForm() bool {
-	for i, field := range m.fields {
-		if field.Required && strings.TrimSpace(m.inputs[i].Value()) == "" {
-			return false
-		}
-	}
-	return true
+	return m.getValidationError() == ""
 }
 
 // getValidationError returns a validation error message
 func (m FormModel) getValidationError() string {
 	for i, field := range m.fields {
-		if field.Required && strings.TrimSpace(m.inputs[i].Value()) == "" {
+		if field.Attachment != nil {
+			continue
+		}
+		value := strings.TrimSpace(m.value(i))
+		if field.Required && value == "" {
 			return fmt.Sprintf("'%s' is required", field.Label)
 		}
+		if field.Validate != nil && value != "" {
+			if err := field.Validate(value); err != nil {
+				return fmt.Sprintf("'%s': %v", field.Label, err)
+			}
+		}
 	}
 	return ""
 }
 
-// GetFormData returns the form data as a map
+// GetFormData returns the standard fields' data as a map
 func (m FormModel) GetFormData() map[string]string {
 	data := make(map[string]string)
 	for _, field := range m.fields {
-		// Convert field label to lowercase and replace special characters/spaces with underscores
-		key := strings.ToLower(field.Label)
-		key = strings.ReplaceAll(key, "/", "_")
-		key = strings.ReplaceAll(key, " ", "_")
-		data[key] = field.Value
+		if field.Custom || field.Attachment != nil {
+			continue
+		}
+		data[field.key()] = field.Value
 	}
 	return data
 }
 
+// key returns the key of a standard field's value in GetFormData
+func (f FormField) key() string {
+	if f.Key != "" {
+		return f.Key
+	}
+	// Convert field label to lowercase and replace special characters/spaces with underscores
+	key := strings.ToLower(f.Label)
+	key = strings.ReplaceAll(key, "/", "_")
+	key = strings.ReplaceAll(key, " ", "_")
+	return key
+}
+
+// GetCustomFields returns the custom fields in the order they appear in the form. The
+// template's own fields are left out when they are empty.
+func (m FormModel) GetCustomFields() []encryption.Field {
+	var fields []encryption.Field
+	for _, field := range m.fields {
+		if field.Fixed && strings.TrimSpace(field.Value) == "" {
+			continue
+		}
+		if field.Custom {
+			fields = append(fields, encryption.Field{Name: field.Label, Type: field.Type, Value: field.Value})
+		}
+	}
+	return fields
+}
+
+// GetAttachments returns the files attached in the form, including those the entry already had
+func (m FormModel) GetAttachments() []encryption.Attachment {
+	var attachments []encryption.Attachment
+	for _, field := range m.fields {
+		if field.Attachment != nil {
+			attachments = append(attachments, *field.Attachment)
+		}
+	}
+	return attachments
+}
+
+// GetTags returns the tags entered in the form, cleaned as they are stored
+func (m FormModel) GetTags() []string {
+	return utils.ParseTags(m.GetFormData()["tags"])
+}
+
+// IsFavourite returns whether the entry was marked as a favourite
+func (m FormModel) IsFavourite() bool {
+	return m.favourite
+}
+
+// GetEntryType returns the type of entry the form is for
+func (m FormModel) GetEntryType() encryption.EntryType {
+	return m.template.Type
+}
+
 // IsSubmitted returns whether the form was successfully submitted
 func (m FormModel) IsSubmitted() bool {
 	return m.submitted
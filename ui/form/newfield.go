@@ -0,0 +1,163 @@
+package form
+
+import (
+	"strings"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/export"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fileKind is the last kind offered by the new field panel, after the custom field types
+const fileKind = "File"
+
+// fieldPanel is the panel shown below the form to add a custom field or attach a file
+type fieldPanel struct {
+	kind       int // Index into encryption.FieldTypes, or len(FieldTypes) for an attachment
+	input      textinput.Model
+	attachment *encryption.Attachment // The file read when an attachment is accepted
+	err        string
+	accepted   bool
+	closed     bool
+}
+
+// New field panel styling
+var (
+	panelStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Margin(0, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70)
+
+	panelKindStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	panelSelectedKindStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+)
+
+// newFieldPanel creates the panel with the name input focused and plain text selected
+func newFieldPanel() fieldPanel {
+	input := textinput.New()
+	input.CharLimit = 200
+	input.Width = 50
+	input.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	input.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+	input.Focus()
+
+	p := fieldPanel{input: input}
+	p.setPlaceholder()
+	return p
+}
+
+// Update handles a key press while the panel is open
+func (p fieldPanel) Update(msg tea.KeyMsg) fieldPanel {
+	switch msg.String() {
+	case "esc":
+		p.closed = true
+		return p
+
+	case "shift+tab":
+		p.kind = (p.kind + len(encryption.FieldTypes)) % (len(encryption.FieldTypes) + 1)
+		p.setPlaceholder()
+		return p
+
+	case "tab":
+		p.kind = (p.kind + 1) % (len(encryption.FieldTypes) + 1)
+		p.setPlaceholder()
+		return p
+
+	case "enter":
+		value := strings.TrimSpace(p.input.Value())
+		if value == "" {
+			p.err = "Enter a name for the field"
+			if p.isFile() {
+				p.err = "Enter the path of the file to attach"
+			}
+			return p
+		}
+		if p.isFile() {
+			attachment, err := encryption.ReadAttachment(export.ExpandPath(value))
+			if err != nil {
+				p.err = err.Error()
+				return p
+			}
+			p.attachment = &attachment
+		}
+		p.accepted = true
+		p.closed = true
+		return p
+	}
+
+	p.err = ""
+	p.input, _ = p.input.Update(msg)
+	return p
+}
+
+// setPlaceholder describes what the input expects for the selected kind
+func (p *fieldPanel) setPlaceholder() {
+	if p.isFile() {
+		p.input.Placeholder = "Path of the file, e.g. ~/keys/id_ed25519"
+	} else {
+		p.input.Placeholder = "Field name, e.g. Recovery codes"
+	}
+}
+
+// isFile returns whether the panel attaches a file rather than adding a field
+func (p fieldPanel) isFile() bool {
+	return p.kind == len(encryption.FieldTypes)
+}
+
+// fieldType returns the type of the custom field being added
+func (p fieldPanel) fieldType() encryption.FieldType {
+	return encryption.FieldTypes[p.kind]
+}
+
+// name returns the name of the new field
+func (p fieldPanel) name() string {
+	return strings.TrimSpace(p.input.Value())
+}
+
+// View renders the panel
+func (p fieldPanel) View() string {
+	kinds := ""
+	for i := range len(encryption.FieldTypes) + 1 {
+		label := fileKind
+		if i < len(encryption.FieldTypes) {
+			label = encryption.FieldTypes[i].Label()
+		}
+		if i == p.kind {
+			kinds += panelSelectedKindStyle.Render(label)
+		} else {
+			kinds += panelKindStyle.Render(label)
+		}
+	}
+
+	label := "Name"
+	if p.isFile() {
+		label = "File"
+	}
+	content := fieldLabelStyle.Render("Type") + "\n  " + kinds + "\n\n"
+	content += fieldLabelStyle.Render(label) + "\n  " + p.input.View() + "\n"
+	if p.err != "" {
+		content += "\n" + errorStyle.Render("❌ "+p.err)
+	}
+	content += "\n" + helpStyle.Render("Tab/Shift+Tab: Type • Enter: Add • Esc: Cancel")
+	return panelStyle.Render(content)
+}
+
+// IsAccepted returns whether the new field should be added
+func (p fieldPanel) IsAccepted() bool {
+	return p.accepted
+}
+
+// IsClosed returns whether the panel was closed
+func (p fieldPanel) IsClosed() bool {
+	return p.closed
+}
@@ -0,0 +1,237 @@
+// Package generator provides an interactive password generator panel built on utils.GeneratePassword.
+// The panel is embedded in other views (such as the add/edit form) rather than run as its own program.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package generator
+
+import (
+	"fmt"
+
+	"github.com/Fozzyack/password-manager/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rows of the generator panel
+const (
+	lengthRow = iota
+	uppercaseRow
+	lowercaseRow
+	numbersRow
+	symbolsRow
+	ambiguousRow
+	rowCount
+)
+
+// Length limits enforced by utils.GeneratePassword
+const (
+	minLength = 8
+	maxLength = 64
+)
+
+// GeneratorModel represents the state of the password generator panel
+type GeneratorModel struct {
+	opts     utils.PasswordOptions
+	password string
+	err      error
+	cursor   int
+	accepted bool
+	closed   bool
+}
+
+// Generator panel styling
+var (
+	panelStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(60).
+		Align(lipgloss.Left)
+
+	panelTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Margin(0, 0, 1, 0)
+
+	rowStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	selectedRowStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	generatedStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#333333")).
+		Padding(0, 1).
+		Bold(true)
+
+	strengthStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		Bold(true)
+
+	generatorErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true)
+
+	generatorHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Margin(1, 0, 0, 0)
+)
+
+// NewGenerator creates a generator panel with the default options and an initial password
+func NewGenerator() GeneratorModel {
+	m := GeneratorModel{
+		opts: utils.DefaultPasswordOptions(),
+	}
+	m.regenerate()
+	return m
+}
+
+// Update handles key presses while the panel is open
+func (m GeneratorModel) Update(msg tea.KeyMsg) GeneratorModel {
+	switch msg.String() {
+	case "esc":
+		m.closed = true
+
+	case "enter":
+		if m.err == nil {
+			m.accepted = true
+			m.closed = true
+		}
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j", "tab":
+		if m.cursor < rowCount-1 {
+			m.cursor++
+		}
+
+	case "left", "h", "-":
+		if m.cursor == lengthRow && m.opts.Length > minLength {
+			m.opts.Length--
+			m.regenerate()
+		}
+
+	case "right", "l", "+":
+		if m.cursor == lengthRow && m.opts.Length < maxLength {
+			m.opts.Length++
+			m.regenerate()
+		}
+
+	case " ", "x":
+		if m.toggle(m.cursor) {
+			m.regenerate()
+		}
+
+	case "r", "R":
+		m.regenerate()
+	}
+
+	return m
+}
+
+// toggle flips the option on the given row, returning false for rows that cannot be toggled
+func (m *GeneratorModel) toggle(row int) bool {
+	switch row {
+	case uppercaseRow:
+		m.opts.IncludeUppercase = !m.opts.IncludeUppercase
+	case lowercaseRow:
+		m.opts.IncludeLowercase = !m.opts.IncludeLowercase
+	case numbersRow:
+		m.opts.IncludeNumbers = !m.opts.IncludeNumbers
+	case symbolsRow:
+		m.opts.IncludeSymbols = !m.opts.IncludeSymbols
+	case ambiguousRow:
+		m.opts.ExcludeAmbiguous = !m.opts.ExcludeAmbiguous
+	default:
+		return false
+	}
+	return true
+}
+
+// regenerate creates a new password from the current options
+func (m *GeneratorModel) regenerate() {
+	m.password, m.err = utils.GeneratePassword(m.opts)
+}
+
+// View renders the generator panel
+func (m GeneratorModel) View() string {
+	content := panelTitleStyle.Render("🎲 Password Generator") + "\n"
+
+	rows := []string{
+		fmt.Sprintf("Length              ◄ %2d ►", m.opts.Length),
+		checkbox("Uppercase (A-Z)", m.opts.IncludeUppercase),
+		checkbox("Lowercase (a-z)", m.opts.IncludeLowercase),
+		checkbox("Numbers (0-9)", m.opts.IncludeNumbers),
+		checkbox("Symbols (!@#...)", m.opts.IncludeSymbols),
+		checkbox("Exclude Ambiguous", m.opts.ExcludeAmbiguous),
+	}
+	for i, row := range rows {
+		if i == m.cursor {
+			content += selectedRowStyle.Render("► "+row) + "\n"
+		} else {
+			content += rowStyle.Render("  "+row) + "\n"
+		}
+	}
+	content += "\n"
+
+	if m.err != nil {
+		content += generatorErrorStyle.Render("❌ "+m.err.Error()) + "\n"
+	} else {
+		content += generatedStyle.Render(m.password) + "\n"
+
+		// Show password strength
+		strength, description := utils.EvaluatePasswordStrength(m.password)
+		content += strengthStyle.Copy().
+			Foreground(lipgloss.Color(strengthColor(strength))).
+			Render(fmt.Sprintf("Strength: %s", description)) + "\n"
+	}
+
+	content += generatorHelpStyle.Render("↑↓: Option • Space: Toggle • ←→: Length • r: Regenerate • Enter: Use • Esc: Close")
+
+	return panelStyle.Render(content)
+}
+
+// checkbox renders a labelled on/off option
+func checkbox(label string, checked bool) string {
+	mark := " "
+	if checked {
+		mark = "x"
+	}
+	return fmt.Sprintf("[%s] %s", mark, label)
+}
+
+// strengthColor maps a strength score to the colour used across the application
+func strengthColor(strength int) string {
+	switch strength {
+	case 0, 1:
+		return "#FF5F87" // Red
+	case 2:
+		return "#FFD700" // Yellow
+	case 3:
+		return "#87CEEB" // Light Blue
+	default:
+		return "#90EE90" // Light Green
+	}
+}
+
+// IsClosed returns whether the panel has been closed
+func (m GeneratorModel) IsClosed() bool {
+	return m.closed
+}
+
+// IsAccepted returns whether the generated password was accepted
+func (m GeneratorModel) IsAccepted() bool {
+	return m.accepted
+}
+
+// GetPassword returns the most recently generated password
+func (m GeneratorModel) GetPassword() string {
+	return m.password
+}
@@ -0,0 +1,297 @@
+// Package history provides the view listing earlier revisions of a password entry.
+// A revision can be compared with the current version, copied from or restored.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/clipboard"
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/Fozzyack/password-manager/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Revision is an earlier version of the entry as shown in the list
+type Revision struct {
+	SavedAt time.Time       // When the revision was replaced by a newer version
+	Entry   encryption.Data // Decrypted contents, empty if Err is set
+	Err     string          // Why the revision could not be decrypted, empty if it was
+}
+
+// HistoryModel represents the state of the history view
+type HistoryModel struct {
+	siteName         string
+	current          encryption.Data
+	revisions        []Revision
+	cursor           int
+	diffing          bool // Whether the selected revision is compared with the current version
+	showPassword     bool
+	restoreRequested bool
+	statusMessage    string // Feedback shown after copying to the clipboard
+	options          *types.Options
+}
+
+// History view styling
+var (
+	historyTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	historyContainerStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(80).
+		Align(lipgloss.Left)
+
+	historyItemStyle = lipgloss.NewStyle().
+		Padding(0, 2)
+
+	historySelectedStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	diffLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Width(12).
+		Align(lipgloss.Right)
+
+	diffRemovedStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Padding(0, 1)
+
+	diffAddedStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#90EE90")).
+		Padding(0, 1)
+
+	diffSameStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Padding(0, 1)
+
+	historyNoteStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Margin(0, 0, 0, 1)
+
+	historyStatusStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#90EE90")).
+		Bold(true).
+		Padding(0, 1)
+
+	historyHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(4).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewHistoryView creates the history view of an entry. revisions are listed newest first
+// and compared with current, the entry as it is now.
+func NewHistoryView(siteName string, current encryption.Data, revisions []Revision, options *types.Options) HistoryModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	return HistoryModel{
+		siteName:  siteName,
+		current:   current,
+		revisions: revisions,
+		options:   options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m HistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles user input for the history view
+func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc", "q", "backspace":
+		// Return to the entry
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.statusMessage = ""
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.revisions)-1 {
+			m.cursor++
+			m.statusMessage = ""
+		}
+
+	case "enter", "d":
+		m.diffing = !m.diffing
+
+	case "v", " ":
+		m.showPassword = !m.showPassword
+
+	case "c":
+		if revision, ok := m.selected(); ok {
+			m.statusMessage = copyToClipboard(revision.Entry.Password)
+		}
+
+	case "r":
+		if _, ok := m.selected(); ok {
+			m.restoreRequested = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// selected returns the revision under the cursor, or false if there is none or it cannot be read
+func (m HistoryModel) selected() (Revision, bool) {
+	if len(m.revisions) == 0 || m.revisions[m.cursor].Err != "" {
+		return Revision{}, false
+	}
+	return m.revisions[m.cursor], true
+}
+
+// copyToClipboard copies an earlier password using the shared clipboard manager and returns a status message
+func copyToClipboard(password string) string {
+	manager := clipboard.Default()
+	err := manager.Copy(password)
+	if err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	if manager.Timeout() > 0 {
+		return fmt.Sprintf("📋 Earlier password copied - clipboard clears in %s", manager.Timeout())
+	}
+	return "📋 Earlier password copied"
+}
+
+// View renders the history view
+func (m HistoryModel) View() string {
+	var content strings.Builder
+
+	// Title
+	content.WriteString(historyTitleStyle.Render("🕘 History of "+m.siteName) + "\n\n")
+
+	body := ""
+	if len(m.revisions) == 0 {
+		body += historyNoteStyle.Render("This entry has not been changed since it was created.\nEarlier versions are kept here each time it is edited.") + "\n"
+	}
+	for i, revision := range m.revisions {
+		line := fmt.Sprintf("%-32s %s", "Replaced "+utils.FormatTimestampForDisplay(revision.SavedAt), m.summary(revision))
+		if i == m.cursor {
+			body += historySelectedStyle.Render("► "+line) + "\n"
+		} else {
+			body += historyItemStyle.Render("  "+line) + "\n"
+		}
+	}
+
+	if revision, ok := m.selected(); ok && m.diffing {
+		body += "\n" + historyNoteStyle.Render("Changes from this version to the current one:") + "\n\n"
+		body += m.diffField("Username:", revision.Entry.Username, m.current.Username, false)
+		body += m.diffField("Email:", revision.Entry.Email, m.current.Email, false)
+		body += m.diffField("URL:", revision.Entry.URL, m.current.URL, false)
+		body += m.diffField("Password:", revision.Entry.Password, m.current.Password, true)
+		body += diffLabelStyle.Render("Updated:") + diffSameStyle.Render(revision.Entry.UpdatedAt.Format("Monday, January 2, 2006 at 3:04 PM")) + "\n"
+	}
+
+	if m.statusMessage != "" {
+		body += "\n" + historyStatusStyle.Render(m.statusMessage) + "\n"
+	}
+
+	content.WriteString(historyContainerStyle.Render(body))
+
+	// Help text
+	help := "↑↓: Navigate • Enter/d: Compare • v: Show Passwords • c: Copy Password • r: Restore • Esc: Back"
+	if m.showPassword {
+		help = "↑↓: Navigate • Enter/d: Compare • v: Hide Passwords • c: Copy Password • r: Restore • Esc: Back"
+	}
+	content.WriteString(historyHelpStyle.Render(help))
+
+	return content.String()
+}
+
+// summary describes what differs between a revision and the current version
+func (m HistoryModel) summary(revision Revision) string {
+	if revision.Err != "" {
+		return "🔒 " + revision.Err
+	}
+	var changed []string
+	if revision.Entry.Password != m.current.Password {
+		changed = append(changed, "password")
+	}
+	if revision.Entry.Username != m.current.Username {
+		changed = append(changed, "username")
+	}
+	if revision.Entry.Email != m.current.Email {
+		changed = append(changed, "email")
+	}
+	if revision.Entry.URL != m.current.URL {
+		changed = append(changed, "URL")
+	}
+	if !slices.Equal(revision.Entry.Fields, m.current.Fields) {
+		changed = append(changed, "custom fields")
+	}
+	if !slices.EqualFunc(revision.Entry.Attachments, m.current.Attachments, func(a, b encryption.Attachment) bool {
+		return a.Name == b.Name && bytes.Equal(a.Data, b.Data)
+	}) {
+		changed = append(changed, "attachments")
+	}
+	if !slices.Equal(revision.Entry.Tags, m.current.Tags) {
+		changed = append(changed, "tags")
+	}
+	if len(changed) == 0 {
+		return "same as current"
+	}
+	return "different " + strings.Join(changed, ", ")
+}
+
+// diffField renders one field of the comparison: the old and new value if it changed.
+// Passwords are only shown once revealed.
+func (m HistoryModel) diffField(label, old, current string, secret bool) string {
+	line := diffLabelStyle.Render(label)
+	if old == current {
+		return line + diffSameStyle.Render("unchanged") + "\n"
+	}
+	if secret && !m.showPassword {
+		return line + diffSameStyle.Render("changed (press v to reveal)") + "\n"
+	}
+	return line + diffRemovedStyle.Render("- "+orNone(old)) + "\n" + diffLabelStyle.Render("") + diffAddedStyle.Render("+ "+orNone(current)) + "\n"
+}
+
+// orNone shows empty values explicitly in the comparison
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// IsRestoreRequested returns whether the selected revision should replace the current version
+func (m HistoryModel) IsRestoreRequested() bool {
+	return m.restoreRequested
+}
+
+// GetSelectedIndex returns the position of the selected revision in the list given to NewHistoryView
+func (m HistoryModel) GetSelectedIndex() int {
+	return m.cursor
+}
@@ -0,0 +1,230 @@
+// Package importform provides the form used to choose the source format and file of a password import.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package importform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/importer"
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Focusable sections of the import form
+const (
+	formatSection = iota
+	pathSection
+)
+
+// ImportFormModel represents the state of the import form
+type ImportFormModel struct {
+	parsers   []importer.Parser
+	parser    int
+	pathInput textinput.Model
+	focus     int
+	submitted bool
+	cancelled bool
+	options   *types.Options
+}
+
+// Import form styling
+var (
+	importTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	importContainerStyle = lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	importLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Margin(0, 0, 0, 1)
+
+	formatStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	selectedFormatStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#7D56F4")).
+		Padding(0, 1).
+		Bold(true)
+
+	importHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+
+	importErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true).
+		Margin(0, 0, 1, 1)
+)
+
+// NewImportForm creates a new import form listing every registered import format
+func NewImportForm(options *types.Options) ImportFormModel {
+	// Clear screen for clean form display
+	fmt.Print("\033[2J\033[H")
+
+	ti := textinput.New()
+	ti.Placeholder = "Path to the exported file"
+	ti.CharLimit = 300
+	ti.Width = 50
+
+	// Style the textinput
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+
+	return ImportFormModel{
+		parsers:   importer.Parsers,
+		parser:    0,
+		pathInput: ti,
+		focus:     formatSection,
+		options:   options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m ImportFormModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles user input for the import form
+func (m ImportFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.cancelled = true
+			m.options.Quit = false // Don't quit the entire app, just cancel the form
+			return m, tea.Quit
+
+		case "tab", "shift+tab":
+			// Switch focus between the format list and the path input
+			return m.toggleFocus()
+
+		case "enter":
+			if m.focus == formatSection {
+				return m.toggleFocus()
+			}
+			if strings.TrimSpace(m.pathInput.Value()) != "" {
+				m.submitted = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		if m.focus == formatSection {
+			switch msg.String() {
+			case "up", "k":
+				if m.parser > 0 {
+					m.parser--
+				}
+			case "down", "j":
+				if m.parser < len(m.parsers)-1 {
+					m.parser++
+				}
+			}
+			return m, nil
+		}
+
+		if msg.String() == "up" {
+			return m.toggleFocus()
+		}
+	}
+
+	// Update the path input when it has focus
+	var cmd tea.Cmd
+	if m.focus == pathSection {
+		m.pathInput, cmd = m.pathInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// toggleFocus moves focus to the other section of the form
+func (m ImportFormModel) toggleFocus() (tea.Model, tea.Cmd) {
+	if m.focus == formatSection {
+		m.focus = pathSection
+		m.pathInput.Focus()
+		return m, m.pathInput.Cursor.BlinkCmd()
+	}
+	m.focus = formatSection
+	m.pathInput.Blur()
+	return m, nil
+}
+
+// View renders the import form interface
+func (m ImportFormModel) View() string {
+	var content strings.Builder
+
+	// Title
+	title := importTitleStyle.Render("📥 Import Passwords")
+	content.WriteString(title + "\n\n")
+
+	formContent := ""
+
+	// Format list
+	formContent += importLabelStyle.Render("Import From") + "\n"
+	for i, parser := range m.parsers {
+		if i == m.parser {
+			cursor := " "
+			if m.focus == formatSection {
+				cursor = "►"
+			}
+			formContent += "  " + selectedFormatStyle.Render(fmt.Sprintf("%s %s", cursor, parser.Name())) + "\n"
+		} else {
+			formContent += "  " + formatStyle.Render("  "+parser.Name()) + "\n"
+		}
+	}
+	formContent += "\n"
+
+	// Source path
+	formContent += importLabelStyle.Render("File") + "\n"
+	formContent += "  " + m.pathInput.View() + "\n\n"
+
+	if m.focus == pathSection && strings.TrimSpace(m.pathInput.Value()) == "" {
+		formContent += importErrorStyle.Render("❌ 'File' is required") + "\n\n"
+	}
+
+	content.WriteString(importContainerStyle.Render(formContent))
+
+	// Help text
+	help := importHelpStyle.Render("↑↓: Choose Format • Tab: Switch Field • Enter: Next/Preview • Esc: Cancel")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// GetParser returns the selected import format
+func (m ImportFormModel) GetParser() importer.Parser {
+	return m.parsers[m.parser]
+}
+
+// GetPath returns the file path entered by the user
+func (m ImportFormModel) GetPath() string {
+	return strings.TrimSpace(m.pathInput.Value())
+}
+
+// IsSubmitted returns whether the form was successfully submitted
+func (m ImportFormModel) IsSubmitted() bool {
+	return m.submitted
+}
+
+// IsCancelled returns whether the form was cancelled
+func (m ImportFormModel) IsCancelled() bool {
+	return m.cancelled
+}
@@ -1,33 +1,72 @@
-// Package list provides a scrollable list view for displaying password entries.
+// Package list provides a scrollable list view for displaying password entries, optionally
+// navigable as a tree of folders. Each entry shows the icon of its type and its tags, favourites
+// are pinned to the top, and the list can be narrowed to one type of entry or one tag and
+// sorted by name, username, date or how recently each entry was used.
 // It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
 package list
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/templates"
 	"github.com/Fozzyack/password-manager/types"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 // PasswordEntry represents a password entry in the list view
 type PasswordEntry struct {
-	Filename  string    // The actual filename (without .gpg)
-	SiteName  string    // Display name for the site
-	Username  string    // Username for the entry
-	Email     string    // Email for the entry
-	CreatedAt time.Time // When the entry was created
+	Type      encryption.EntryType // Template the entry was created from, empty for older logins
+	Filename  string               // The actual filename (without .gpg)
+	Folder    string               // Store-relative folder holding the entry, empty for the root
+	SiteName  string               // Display name for the site
+	Username  string               // Username for the entry
+	Email     string               // Email for the entry
+	URL       string               // URL for the entry
+	Tags      []string             // Tags of the entry
+	Favourite bool                 // Whether the entry is pinned to the top of the list
+	CreatedAt time.Time            // When the entry was created
+	UpdatedAt time.Time            // When the entry was last changed
+	LastUsed  time.Time            // When the entry was last opened on this machine, zero if never
+	Duplicate bool                 // Whether the entry duplicates an existing one (import preview only)
+}
+
+// Folder is a sub-folder of the store shown in the tree view
+type Folder struct {
+	Path   string // Store-relative path, e.g. "work/aws"
+	Locked bool   // Whether the folder must be unlocked before its entries can be listed
+	Note   string // Short description of the folder's own encryption, empty if it inherits
 }
 
 // ListModel represents the state of the password list
 type ListModel struct {
-	entries       []PasswordEntry
-	cursor        int
-	selected      bool
-	selectedEntry PasswordEntry
-	options       *types.Options
+	entries        []PasswordEntry
+	visible        []int              // Indexes into entries that match the current search, in display order
+	matches        map[int]entryMatch // Matched positions per entry index, for highlighting
+	searching      bool               // Whether the search input has focus
+	searchInput    textinput.Model
+	typeFilter     encryption.EntryType // Only entries of this type are shown, empty for every type
+	tagFilter      string               // Only entries with this tag are shown, empty for every tag
+	sort           SortOrder            // Order of the entries after favourites, empty to keep the order given
+	cursor         int                  // Position within visible
+	selected       bool
+	selectedEntry  PasswordEntry
+	title          string
+	preview        bool // Preview mode confirms the whole list instead of selecting an entry
+	confirmed      bool
+	skipDuplicates bool
+	tree           bool     // Tree mode shows one folder at a time
+	folders        []Folder // Every sub-folder of the store, in tree mode
+	folder         string   // Folder being shown in tree mode, empty for the root
+	subfolders     []Folder // Direct sub-folders of the current folder, listed above its entries
+	unlockFolder   string   // Locked folder the user asked to open
+	settings       bool     // Whether the encryption settings of the current folder were requested
+	options        *types.Options
 }
 
 // List styling
@@ -66,6 +105,27 @@ var (
 		Align(lipgloss.Center).
 		Margin(1, 0)
 
+	matchStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFD700")).
+		Bold(true).
+		Underline(true)
+
+	searchStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		Margin(0, 0, 1, 0)
+
+	folderNoteStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262"))
+
+	tagStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#87CEEB"))
+
+	pathStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FFD700")).
+		Padding(0, 2).
+		Margin(0, 0, 1, 0)
+
 	emptyListStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#626262")).
 		Italic(true).
@@ -78,11 +138,50 @@ func NewPasswordList(entries []PasswordEntry, options *types.Options) ListModel
 	// Clear screen for clean list display
 	fmt.Print("\033[2J\033[H")
 
-	return ListModel{
-		entries: entries,
-		cursor:  0,
-		options: options,
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "search, or user:alice url:github.com"
+	ti.CharLimit = 100
+	ti.Width = 50
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+
+	m := ListModel{
+		entries:     entries,
+		searchInput: ti,
+		sort:        SortName,
+		cursor:      0,
+		title:       "🔐 Password List",
+		options:     options,
 	}
+	m.applyFilter()
+	return m
+}
+
+// NewPreviewList creates a read-only list used to review entries before they are written,
+// such as during an import. Enter confirms the whole list and 's' toggles skipping duplicates.
+func NewPreviewList(entries []PasswordEntry, title string, options *types.Options) ListModel {
+	m := NewPasswordList(entries, options)
+	m.title = title
+	m.preview = true
+	m.skipDuplicates = true
+	m.sort = "" // Entries are reviewed in the order they will be written
+	m.applyFilter()
+	return m
+}
+
+// NewFolderList creates a password list navigable as a tree of folders, opened at folder and
+// sorted in order. Only the entries and sub-folders of one folder are shown at a time;
+// searching looks through every folder and shows the matches as a flat list.
+func NewFolderList(entries []PasswordEntry, folders []Folder, folder string, order SortOrder, options *types.Options) ListModel {
+	m := NewPasswordList(entries, options)
+	m.tree = true
+	m.folders = folders
+	m.folder = folder
+	m.sort = order
+	m.applyFilter()
+	return m
 }
 
 // Init implements the tea.Model interface
@@ -92,18 +191,80 @@ func (m ListModel) Init() tea.Cmd {
 
 // Update handles user input and list navigation
 func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Route key presses to the search input while searching
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.searching {
+		return m.updateSearch(keyMsg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "esc", "q":
+		case "ctrl+c", "q":
 			// Return to main menu
 			return m, tea.Quit
 
+		case "esc":
+			// Clear an active filter first, then return to main menu
+			if m.searchInput.Value() != "" {
+				m.searchInput.SetValue("")
+				m.applyFilter()
+				return m, nil
+			}
+			return m, tea.Quit
+
+		case "/":
+			if !m.preview {
+				m.searching = true
+				m.searchInput.Focus()
+				return m, m.searchInput.Cursor.BlinkCmd()
+			}
+
 		case "enter", " ":
+			if m.preview {
+				// Confirm the whole list
+				if len(m.entries) > 0 {
+					m.confirmed = true
+					return m, tea.Quit
+				}
+				return m, nil
+			}
+
+			// Open the folder under the cursor
+			if m.cursor < len(m.subfolders) {
+				folder := m.subfolders[m.cursor]
+				if folder.Locked {
+					m.unlockFolder = folder.Path
+					return m, tea.Quit
+				}
+				m.folder = folder.Path
+				m.cursor = 0
+				m.applyFilter()
+				return m, nil
+			}
+
 			// Select the current entry
-			if len(m.entries) > 0 && m.cursor < len(m.entries) {
+			if position := m.cursor - len(m.subfolders); position >= 0 && position < len(m.visible) {
 				m.selected = true
-				m.selectedEntry = m.entries[m.cursor]
+				m.selectedEntry = m.entries[m.visible[position]]
+				return m, tea.Quit
+			}
+
+		case "backspace", "left", "h":
+			// Go up to the parent folder, keeping the cursor on the folder just left
+			if m.tree && m.folder != "" && m.searchInput.Value() == "" {
+				child := m.folder
+				m.folder = parentFolder(m.folder)
+				m.applyFilter()
+				for position, folder := range m.subfolders {
+					if folder.Path == child {
+						m.cursor = position
+					}
+				}
+			}
+
+		case "p":
+			if m.tree {
+				m.settings = true
 				return m, tea.Quit
 			}
 
@@ -113,16 +274,39 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "down", "j":
-			if m.cursor < len(m.entries)-1 {
+			if m.cursor < m.rowCount()-1 {
 				m.cursor++
 			}
 
 		case "home":
 			m.cursor = 0
 
+		case "t":
+			// Show the next type of entry in the list, then every type again
+			if !m.preview {
+				m.typeFilter = m.nextType()
+				m.applyFilter()
+			}
+
+		case "#":
+			// Show the entries with the next tag, then every entry again
+			if !m.preview {
+				m.tagFilter = m.nextTag()
+				m.applyFilter()
+			}
+
+		case "s":
+			if m.preview {
+				m.skipDuplicates = !m.skipDuplicates
+			} else {
+				// Sort by the next order, keeping the cursor on the same entry
+				m.sort = m.sort.next()
+				m.applyFilter()
+			}
+
 		case "end":
-			if len(m.entries) > 0 {
-				m.cursor = len(m.entries) - 1
+			if m.rowCount() > 0 {
+				m.cursor = m.rowCount() - 1
 			}
 		}
 	}
@@ -130,16 +314,174 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateSearch handles key presses while the search input has focus.
+// The filter is re-applied after every change so results update live.
+func (m ListModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		// Leave search mode and clear the filter
+		m.searching = false
+		m.searchInput.Blur()
+		m.searchInput.SetValue("")
+		m.applyFilter()
+		return m, nil
+
+	case "enter":
+		// Keep the filter and return to navigating the results
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+
+	case "up", "down":
+		// Allow moving through results without leaving search mode
+		if msg.String() == "up" && m.cursor > 0 {
+			m.cursor--
+		} else if msg.String() == "down" && m.cursor < m.rowCount()-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	previous := m.searchInput.Value()
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if m.searchInput.Value() != previous {
+		m.applyFilter()
+	}
+	return m, cmd
+}
+
+// applyFilter recomputes the visible entries from the current query, keeping the cursor on
+// the same entry when it is still visible and clamping it to the results otherwise.
+// In tree mode without a query only the current folder's sub-folders and entries are shown.
+func (m *ListModel) applyFilter() {
+	current := -1
+	if position := m.cursor - len(m.subfolders); position >= 0 && position < len(m.visible) {
+		current = m.visible[position]
+	}
+
+	terms := parseQuery(m.searchInput.Value())
+	browsing := m.tree && !m.filtered()
+	m.subfolders = nil
+	if browsing {
+		for _, folder := range m.folders {
+			if parentFolder(folder.Path) == m.folder {
+				m.subfolders = append(m.subfolders, folder)
+			}
+		}
+	}
+
+	m.visible = make([]int, 0, len(m.entries))
+	m.matches = make(map[int]entryMatch)
+	for i, entry := range m.entries {
+		if browsing && entry.Folder != m.folder {
+			continue
+		}
+		if m.typeFilter != "" && entryType(entry) != m.typeFilter {
+			continue
+		}
+		if m.tagFilter != "" && !slices.Contains(entry.Tags, m.tagFilter) {
+			continue
+		}
+		match, ok := matchEntry(entry, terms)
+		if !ok {
+			continue
+		}
+		m.visible = append(m.visible, i)
+		m.matches[i] = match
+	}
+
+	// Favourites are pinned to the top, each group in the chosen order
+	slices.SortStableFunc(m.visible, func(a, b int) int {
+		return compareEntries(m.entries[a], m.entries[b], m.sort)
+	})
+
+	m.cursor = 0
+	for position, index := range m.visible {
+		if index == current {
+			m.cursor = len(m.subfolders) + position
+			break
+		}
+	}
+}
+
+// entryType returns the type of an entry, taking entries saved before types existed as logins
+func entryType(entry PasswordEntry) encryption.EntryType {
+	return templates.For(entry.Type).Type
+}
+
+// nextType returns the type the type filter moves on to: the next type that has entries,
+// in the order types are offered, or every type after the last one
+func (m ListModel) nextType() encryption.EntryType {
+	present := make(map[encryption.EntryType]bool)
+	for _, entry := range m.entries {
+		present[entryType(entry)] = true
+	}
+	passed := m.typeFilter == ""
+	for _, template := range templates.All {
+		if passed && present[template.Type] {
+			return template.Type
+		}
+		if template.Type == m.typeFilter {
+			passed = true
+		}
+	}
+	return ""
+}
+
+// nextTag returns the tag the tag filter moves on to: the next tag in alphabetical order,
+// or every tag after the last one
+func (m ListModel) nextTag() string {
+	var tags []string
+	for _, entry := range m.entries {
+		for _, tag := range entry.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+	for _, tag := range tags {
+		if tag > m.tagFilter {
+			return tag
+		}
+	}
+	return ""
+}
+
+// filtered returns whether a search, type or tag filter narrows the list, in which case
+// matching entries from every folder are shown together
+func (m ListModel) filtered() bool {
+	return m.searchInput.Value() != "" || m.typeFilter != "" || m.tagFilter != ""
+}
+
+// rowCount returns the number of rows the cursor can move over
+func (m ListModel) rowCount() int {
+	return len(m.subfolders) + len(m.visible)
+}
+
+// parentFolder returns the folder holding a store-relative path, empty for the root
+func parentFolder(path string) string {
+	index := strings.LastIndex(path, "/")
+	if index < 0 {
+		return ""
+	}
+	return path[:index]
+}
+
 // View renders the password list interface
 func (m ListModel) View() string {
 	var content strings.Builder
 
 	// Title
-	title := listTitleStyle.Render("🔐 Password List")
+	title := listTitleStyle.Render(m.title)
 	content.WriteString(title + "\n\n")
 
 	// Check if list is empty
-	if len(m.entries) == 0 {
+	if len(m.entries) == 0 && len(m.folders) == 0 {
 		emptyMsg := emptyListStyle.Render("No passwords found.\nUse the 'Add New Password' option to create your first entry.")
 		content.WriteString(listContainerStyle.Render(emptyMsg))
 		content.WriteString(listHelpStyle.Render("Press Esc to return to main menu"))
@@ -148,6 +490,58 @@ func (m ListModel) View() string {
 
 	// List content
 	listContent := ""
+
+	// Current folder in tree mode
+	if m.tree {
+		listContent += pathStyle.Render("📁 /" + m.folder)
+		listContent += "\n"
+	}
+
+	// Search bar, shown while searching or when a filter is active
+	if m.searching || m.searchInput.Value() != "" {
+		listContent += searchStyle.Render(fmt.Sprintf("%s  (%d of %d)", m.searchInput.View(), len(m.visible), len(m.entries)))
+		listContent += "\n"
+	}
+
+	// Tag filter, shown while only the entries with one tag are listed
+	if m.tagFilter != "" {
+		filter := fmt.Sprintf("Showing entries tagged %s only  (%d of %d)", tagStyle.Render("#"+m.tagFilter), len(m.visible), len(m.entries))
+		listContent += searchStyle.Render(filter + folderNoteStyle.Render(" • #: next tag"))
+		listContent += "\n"
+	}
+
+	// Type filter, shown while only one type of entry is listed
+	if m.typeFilter != "" {
+		filter := fmt.Sprintf("Showing %s entries only  (%d of %d)", templates.For(m.typeFilter).Title(), len(m.visible), len(m.entries))
+		listContent += searchStyle.Render(filter + folderNoteStyle.Render(" • t: next type"))
+		listContent += "\n"
+	}
+
+	// Sort order, shown wherever the order can be changed
+	if !m.preview {
+		listContent += searchStyle.Render("Sorted by " + m.sort.Label() + folderNoteStyle.Render(" • s: next order"))
+		listContent += "\n"
+	}
+
+	// Summary of duplicates when previewing
+	if m.preview {
+		duplicates := 0
+		for _, entry := range m.entries {
+			if entry.Duplicate {
+				duplicates++
+			}
+		}
+		action := "importing"
+		if m.skipDuplicates {
+			action = "skipping"
+		}
+		summaryStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
+			Padding(0, 2).
+			Margin(0, 0, 1, 0)
+		listContent += summaryStyle.Render(fmt.Sprintf("%d entries • %d duplicates (⚠, %s)", len(m.entries), duplicates, action))
+		listContent += "\n"
+	}
 	
 	// Add header
 	headerStyle := lipgloss.NewStyle().
@@ -156,8 +550,9 @@ func (m ListModel) View() string {
 		Padding(0, 2).
 		Margin(0, 0, 1, 0)
 	
+	dateHeading, _ := m.dateColumn(PasswordEntry{})
 	listContent += headerStyle.Render(fmt.Sprintf("%-25s %-20s %-15s %s", 
-		"Site/Service", "Username", "Email", "Created"))
+		"Site/Service", "Username", "Email", dateHeading))
 	listContent += "\n"
 	
 	// Add separator
@@ -165,41 +560,83 @@ func (m ListModel) View() string {
 		Foreground(lipgloss.Color("#626262"))
 	listContent += separatorStyle.Render(strings.Repeat("─", 70)) + "\n\n"
 
+	// Sub-folders of the current folder
+	for position, folder := range m.subfolders {
+		icon := "📁"
+		if folder.Locked {
+			icon = "🔒"
+		}
+		name := folder.Path[strings.LastIndex(folder.Path, "/")+1:] + "/"
+		folderText := fmt.Sprintf("%s %-23s %s", icon, name, folderNoteStyle.Render(folder.Note))
+		if position == m.cursor {
+			listContent += selectedItemStyle.Render("► " + folderText) + "\n"
+		} else {
+			listContent += listItemStyle.Render("  " + folderText) + "\n"
+		}
+	}
+
 	// List entries
-	for i, entry := range m.entries {
+	for position, index := range m.visible {
+		position += len(m.subfolders)
+		entry := m.entries[index]
+		match := m.matches[index]
+
 		// Format the entry data
 		siteName := entry.SiteName
-		if len(siteName) > 24 {
-			siteName = siteName[:21] + "..."
+		sitePositions := match[fieldSite]
+		if m.tree && m.filtered() && entry.Folder != "" {
+			// Matches from every folder are listed together, so show where each one lives
+			siteName = entry.Folder + "/" + siteName
+			sitePositions = shiftPositions(sitePositions, len([]rune(entry.Folder))+1)
 		}
-		
-		username := entry.Username
-		if len(username) > 19 {
-			username = username[:16] + "..."
+		if entry.Duplicate {
+			siteName = "⚠ " + siteName
+			sitePositions = shiftPositions(sitePositions, 2)
 		}
-		
-		email := entry.Email
-		if len(email) > 14 {
-			email = email[:11] + "..."
+		if entry.Favourite {
+			siteName = "★ " + siteName
+			sitePositions = shiftPositions(sitePositions, 2)
+		}
+
+		_, date := m.dateColumn(entry)
+
+		entryText := templates.For(entry.Type).Icon + " " +
+			highlightColumn(siteName, 22, sitePositions) + " " +
+			highlightColumn(entry.Username, 20, match[fieldUsername]) + " " +
+			highlightColumn(entry.Email, 15, match[fieldEmail]) + " " +
+			date
+
+		// Tags go on a line of their own below the entry, lined up with the site name
+		if len(entry.Tags) > 0 {
+			entryText += "\n     " + tagStyle.Render(highlightColumn(tagText(entry.Tags), 66, match[fieldTags]))
 		}
-		
-		createdAt := entry.CreatedAt.Format("Jan 02, 2006")
-		
-		entryText := fmt.Sprintf("%-25s %-20s %-15s %s", 
-			siteName, username, email, createdAt)
 
 		// Apply styling based on cursor position
-		if i == m.cursor {
+		if position == m.cursor {
 			listContent += selectedItemStyle.Render("► " + entryText) + "\n"
 		} else {
 			listContent += listItemStyle.Render("  " + entryText) + "\n"
 		}
 	}
 
+	if m.rowCount() == 0 && m.searchInput.Value() == "" {
+		listContent += emptyListStyle.Render("This folder is empty.") + "\n"
+	} else if len(m.visible) == 0 && m.searchInput.Value() != "" {
+		listContent += emptyListStyle.Render("No entries match your search.") + "\n"
+	}
+
 	content.WriteString(listContainerStyle.Render(listContent))
 
 	// Help text
-	help := listHelpStyle.Render("↑↓/j/k: Navigate • Enter/Space: View Details • Esc/q: Back to Menu")
+	helpText := "↑↓/j/k: Navigate • /: Search • t: Filter by Type • #: Filter by Tag • s: Sort • Enter/Space: View Details • Esc/q: Back to Menu"
+	if m.searching {
+		helpText = "Type to filter • user:/email:/url:/site:/type:/tag: to search one field • Enter: Done • Esc: Clear"
+	} else if m.preview {
+		helpText = "↑↓/j/k: Navigate • s: Toggle Skipping Duplicates • Enter: Confirm • Esc/q: Cancel"
+	} else if m.tree {
+		helpText = "↑↓/j/k: Navigate • Enter: Open • ←/Backspace: Up • /: Search All • t: Filter by Type • #: Filter by Tag • s: Sort • p: Folder Encryption • Esc/q: Back to Menu"
+	}
+	help := listHelpStyle.Render(helpText)
 	content.WriteString(help)
 
 	return content.String()
@@ -215,6 +652,36 @@ func (m ListModel) GetSelectedEntry() PasswordEntry {
 	return m.selectedEntry
 }
 
+// GetSortOrder returns the order the list was sorted in when it was closed
+func (m ListModel) GetSortOrder() SortOrder {
+	return m.sort
+}
+
+// GetFolder returns the folder shown when the list was closed, so it can be reopened there
+func (m ListModel) GetFolder() string {
+	return m.folder
+}
+
+// GetUnlockFolder returns the locked folder the user asked to open, empty if none
+func (m ListModel) GetUnlockFolder() string {
+	return m.unlockFolder
+}
+
+// IsSettingsRequested returns whether the encryption settings of the current folder were requested
+func (m ListModel) IsSettingsRequested() bool {
+	return m.settings
+}
+
+// IsConfirmed returns whether the preview list was confirmed
+func (m ListModel) IsConfirmed() bool {
+	return m.confirmed
+}
+
+// IsSkippingDuplicates returns whether duplicate entries should be skipped when the preview is confirmed
+func (m ListModel) IsSkippingDuplicates() bool {
+	return m.skipDuplicates
+}
+
 // GetCursor returns the current cursor position
 func (m ListModel) GetCursor() int {
 	return m.cursor
@@ -0,0 +1,176 @@
+package list
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Fozzyack/password-manager/templates"
+)
+
+// Searchable entry fields
+const (
+	fieldSite     = "site"
+	fieldUsername = "username"
+	fieldEmail    = "email"
+	fieldURL      = "url"
+	fieldType     = "type"
+	fieldTags     = "tags"
+)
+
+// fieldAliases maps the prefixes accepted in qualified queries (e.g. "user:alice") to entry fields
+var fieldAliases = map[string]string{
+	"site":     fieldSite,
+	"name":     fieldSite,
+	"user":     fieldUsername,
+	"username": fieldUsername,
+	"email":    fieldEmail,
+	"mail":     fieldEmail,
+	"url":      fieldURL,
+	"host":     fieldURL,
+	"type":     fieldType,
+	"kind":     fieldType,
+	"tag":      fieldTags,
+	"tags":     fieldTags,
+}
+
+// searchTerm is a single whitespace separated part of a query, optionally restricted to one field
+type searchTerm struct {
+	field  string // Empty to match any field
+	value  string
+	negate bool // Whether entries containing the value are left out instead
+}
+
+// entryMatch holds the matched rune positions in each field of an entry, used for highlighting
+type entryMatch map[string][]int
+
+// parseQuery splits a query into terms. A term of the form "field:value" is restricted to that
+// field when the prefix is a known alias; otherwise the whole term matches any field.
+// A leading "-", as in "-github" or "-user:bob", excludes the entries the rest would match.
+func parseQuery(query string) []searchTerm {
+	var terms []searchTerm
+	for _, token := range strings.Fields(query) {
+		negate := false
+		if len(token) > 1 && strings.HasPrefix(token, "-") {
+			negate = true
+			token = token[1:]
+		}
+		term := searchTerm{value: token, negate: negate}
+		if prefix, value, found := strings.Cut(token, ":"); found {
+			if field, ok := fieldAliases[strings.ToLower(prefix)]; ok {
+				term = searchTerm{field: field, value: value, negate: negate}
+			}
+		}
+		if term.value != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// matchEntry reports whether the entry satisfies every term and returns the matched positions.
+// Unqualified terms may match any field but the entry's type, which is only searched with
+// "type:"; all matching fields are highlighted. Negated terms are not fuzzy: they leave out
+// entries whose field contains the value as typed, ignoring case, and highlight nothing.
+func matchEntry(entry PasswordEntry, terms []searchTerm) (entryMatch, bool) {
+	fields := map[string]string{
+		fieldSite:     entry.SiteName,
+		fieldUsername: entry.Username,
+		fieldEmail:    entry.Email,
+		fieldURL:      entry.URL,
+		fieldType:     templates.For(entry.Type).Name,
+		fieldTags:     tagText(entry.Tags),
+	}
+
+	match := entryMatch{}
+	for _, term := range terms {
+		matched := false
+		for field, text := range fields {
+			if term.field != field && (term.field != "" || field == fieldType) {
+				continue
+			}
+			if term.negate {
+				if strings.Contains(strings.ToLower(text), strings.ToLower(term.value)) {
+					return nil, false
+				}
+				continue
+			}
+			if positions, ok := fuzzyMatch(text, term.value); ok {
+				match[field] = append(match[field], positions...)
+				matched = true
+			}
+		}
+		if !matched && !term.negate {
+			return nil, false
+		}
+	}
+	return match, true
+}
+
+// tagText returns the tags of an entry as they are shown and searched, e.g. "#work #finance"
+func tagText(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(tags, " #")
+}
+
+// fuzzyMatch reports whether every rune of pattern appears in text in order, ignoring case.
+// It returns the rune indexes in text that matched.
+func fuzzyMatch(text, pattern string) ([]int, bool) {
+	patternRunes := []rune(strings.ToLower(pattern))
+	if len(patternRunes) == 0 {
+		return nil, true
+	}
+
+	var positions []int
+	p := 0
+	for i, r := range []rune(text) {
+		if unicode.ToLower(r) == patternRunes[p] {
+			positions = append(positions, i)
+			p++
+			if p == len(patternRunes) {
+				return positions, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// highlightColumn truncates text to fit in width columns the same way the list always has,
+// highlights the matched positions and pads the result with spaces to the full width.
+// Columns too narrow for an ellipsis are cut off without one.
+func highlightColumn(text string, width int, positions []int) string {
+	runes := []rune(text)
+	truncated := false
+	if width < 4 {
+		runes = runes[:min(len(runes), max(width, 0))]
+	} else if len(runes) > width-1 {
+		runes = append(runes[:width-4], []rune("...")...)
+		truncated = true
+	}
+
+	highlighted := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		highlighted[position] = true
+	}
+
+	var builder strings.Builder
+	for i, r := range runes {
+		if highlighted[i] && !(truncated && i >= len(runes)-3) {
+			builder.WriteString(matchStyle.Render(string(r)))
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteString(strings.Repeat(" ", max(width-len(runes), 0)))
+	return builder.String()
+}
+
+// shiftPositions offsets matched positions when a prefix is added to the displayed text
+func shiftPositions(positions []int, offset int) []int {
+	shifted := make([]int, len(positions))
+	for i, position := range positions {
+		shifted[i] = position + offset
+	}
+	return shifted
+}
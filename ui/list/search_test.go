@@ -0,0 +1,114 @@
+package list
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Fozzyack/password-manager/encryption"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []searchTerm
+	}{
+		{"", nil},
+		{"  git  hub ", []searchTerm{{value: "git"}, {value: "hub"}}},
+		{"user:alice URL:github.com", []searchTerm{{field: fieldUsername, value: "alice"}, {field: fieldURL, value: "github.com"}}},
+		{"mail:a@b tag:work kind:card", []searchTerm{{field: fieldEmail, value: "a@b"}, {field: fieldTags, value: "work"}, {field: fieldType, value: "card"}}},
+		{"port:22", []searchTerm{{value: "port:22"}}},
+		{"user:", nil},
+		{"-github -user:bob", []searchTerm{{value: "github", negate: true}, {field: fieldUsername, value: "bob", negate: true}}},
+		{"- -", []searchTerm{{value: "-"}, {value: "-"}}},
+	}
+	for _, test := range tests {
+		if got := parseQuery(test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseQuery(%q) = %+v, want %+v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		positions     []int
+		ok            bool
+	}{
+		{"GitHub", "gh", []int{0, 3}, true},
+		{"GitHub", "GITHUB", []int{0, 1, 2, 3, 4, 5}, true},
+		{"GitHub", "hg", nil, false},
+		{"anything", "", nil, true},
+		{"", "a", nil, false},
+		{"Café Ütopia", "éü", []int{3, 5}, true},
+	}
+	for _, test := range tests {
+		positions, ok := fuzzyMatch(test.text, test.pattern)
+		if ok != test.ok || !reflect.DeepEqual(positions, test.positions) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v, want %v, %v", test.text, test.pattern, positions, ok, test.positions, test.ok)
+		}
+	}
+}
+
+func TestMatchEntry(t *testing.T) {
+	entry := PasswordEntry{
+		SiteName: "GitHub",
+		Username: "alice",
+		Email:    "alice@example.com",
+		URL:      "https://github.com",
+		Type:     encryption.EntryType("card"),
+		Tags:     []string{"work"},
+	}
+
+	tests := []struct {
+		query string
+		ok    bool
+		want  entryMatch // Matched positions, checked when not nil
+	}{
+		{"gh", true, entryMatch{fieldSite: {0, 3}, fieldURL: {8, 11}}},
+		{"user:alc", true, entryMatch{fieldUsername: {0, 1, 3}}},
+		{"user:github", false, nil},
+		{"url:github.com site:hub", true, nil},
+		{"tag:wk", true, entryMatch{fieldTags: {1, 4}}},
+		{"card", false, nil},
+		{"type:card", true, nil},
+		{"alice bob", false, nil},
+		{"-gitlab", true, entryMatch{}},
+		{"-GITHUB", false, nil},
+		{"-user:bob", true, nil},
+		{"-user:ali", false, nil},
+		{"-gtb", true, nil}, // Negated terms are not fuzzy
+		{"git -email:example", false, nil},
+	}
+	for _, test := range tests {
+		match, ok := matchEntry(entry, parseQuery(test.query))
+		if ok != test.ok {
+			t.Errorf("matchEntry(%q) = %v, want %v", test.query, ok, test.ok)
+			continue
+		}
+		if test.want != nil && !reflect.DeepEqual(match, test.want) {
+			t.Errorf("matchEntry(%q) matched %v, want %v", test.query, match, test.want)
+		}
+	}
+}
+
+func TestHighlightColumn(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"github", 10, "github    "},
+		{"a long site name", 10, "a long... "},
+		{"github", 3, "git"},
+		{"github", 0, ""},
+		{"github", -1, ""},
+		{"ab", 3, "ab "},
+	}
+	for _, test := range tests {
+		if got := highlightColumn(test.text, test.width, nil); got != test.want {
+			t.Errorf("highlightColumn(%q, %d) = %q, want %q", test.text, test.width, got, test.want)
+		}
+	}
+	// Matches in narrow columns do not panic
+	highlightColumn("github", 2, []int{0, 1, 5})
+}
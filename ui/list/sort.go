@@ -0,0 +1,124 @@
+package list
+
+import (
+	"strings"
+	"time"
+)
+
+// SortOrder is an order the password list can be shown in
+type SortOrder string
+
+const (
+	SortName     SortOrder = "name"     // Site name, A to Z
+	SortUsername SortOrder = "username" // Username, A to Z, entries without one last
+	SortCreated  SortOrder = "created"  // Newest entries first
+	SortUpdated  SortOrder = "updated"  // Most recently changed first
+	SortRecent   SortOrder = "recent"   // Most recently opened first, entries never opened last
+)
+
+// SortOrders lists the orders in the order the sort key cycles through them
+var SortOrders = []SortOrder{SortName, SortUsername, SortCreated, SortUpdated, SortRecent}
+
+// ParseSortOrder returns the order with the given name, such as one saved in the config file.
+// Names this version does not know fall back to sorting by site name.
+func ParseSortOrder(name string) SortOrder {
+	for _, order := range SortOrders {
+		if string(order) == name {
+			return order
+		}
+	}
+	return SortName
+}
+
+// Label returns how the order is described in the list, e.g. "recently used"
+func (o SortOrder) Label() string {
+	switch o {
+	case SortUsername:
+		return "username"
+	case SortCreated:
+		return "newest first"
+	case SortUpdated:
+		return "recently updated"
+	case SortRecent:
+		return "recently used"
+	}
+	return "site name"
+}
+
+// next returns the order the sort key moves on to, back to the first after the last
+func (o SortOrder) next() SortOrder {
+	for i, order := range SortOrders {
+		if order == o {
+			return SortOrders[(i+1)%len(SortOrders)]
+		}
+	}
+	return SortOrders[0]
+}
+
+// compareEntries orders two entries for the list: favourites first, then by order.
+// Entries that are level are ordered by site name, so the list does not depend on file order.
+// An empty order only pins favourites and otherwise keeps the order given.
+func compareEntries(a, b PasswordEntry, order SortOrder) int {
+	if a.Favourite != b.Favourite {
+		if a.Favourite {
+			return -1
+		}
+		return 1
+	}
+	if order == "" {
+		return 0
+	}
+
+	result := 0
+	switch order {
+	case SortUsername:
+		result = compareText(a.Username, b.Username)
+	case SortCreated:
+		result = b.CreatedAt.Compare(a.CreatedAt)
+	case SortUpdated:
+		result = updatedAt(b).Compare(updatedAt(a))
+	case SortRecent:
+		result = b.LastUsed.Compare(a.LastUsed)
+	}
+	if result != 0 {
+		return result
+	}
+	if result = compareText(a.SiteName, b.SiteName); result != 0 {
+		return result
+	}
+	return strings.Compare(a.Filename, b.Filename)
+}
+
+// compareText orders text alphabetically ignoring case, with empty text last
+func compareText(a, b string) int {
+	if (a == "") != (b == "") {
+		if a == "" {
+			return 1
+		}
+		return -1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// updatedAt returns when an entry last changed, taking entries never edited as changed when created
+func updatedAt(entry PasswordEntry) time.Time {
+	if entry.UpdatedAt.IsZero() {
+		return entry.CreatedAt
+	}
+	return entry.UpdatedAt
+}
+
+// dateColumn returns the heading and value of the list's date column, which shows the date
+// the list is sorted by, or when the entry was created
+func (m ListModel) dateColumn(entry PasswordEntry) (string, string) {
+	switch m.sort {
+	case SortUpdated:
+		return "Updated", updatedAt(entry).Format("Jan 02, 2006")
+	case SortRecent:
+		if entry.LastUsed.IsZero() {
+			return "Last Used", "Never"
+		}
+		return "Last Used", entry.LastUsed.Format("Jan 02, 2006")
+	}
+	return "Created", entry.CreatedAt.Format("Jan 02, 2006")
+}
@@ -0,0 +1,86 @@
+package list
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// sortedNames sorts entries in order and returns their site names
+func sortedNames(entries []PasswordEntry, order SortOrder) []string {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b PasswordEntry) int {
+		return compareEntries(a, b, order)
+	})
+	var names []string
+	for _, entry := range sorted {
+		names = append(names, entry.SiteName)
+	}
+	return names
+}
+
+func TestCompareEntries(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC)
+	}
+	entries := []PasswordEntry{
+		{Filename: "github", SiteName: "github", Username: "Octocat", CreatedAt: day(1), UpdatedAt: day(9), LastUsed: day(3)},
+		{Filename: "bank", SiteName: "Bank", CreatedAt: day(5), LastUsed: day(8)},
+		{Filename: "aws", SiteName: "aws", Username: "admin", CreatedAt: day(3), UpdatedAt: day(4)},
+		{Filename: "mail", SiteName: "Mail", Username: "alice", CreatedAt: day(2), UpdatedAt: day(6), Favourite: true},
+	}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortName, []string{"Mail", "aws", "Bank", "github"}},
+		{SortUsername, []string{"Mail", "aws", "github", "Bank"}},
+		{SortCreated, []string{"Mail", "Bank", "aws", "github"}},
+		{SortUpdated, []string{"Mail", "github", "Bank", "aws"}},
+		{SortRecent, []string{"Mail", "Bank", "github", "aws"}},
+		{"", []string{"Mail", "github", "Bank", "aws"}},
+	}
+	for _, test := range tests {
+		if got := sortedNames(entries, test.order); !slices.Equal(got, test.want) {
+			t.Errorf("sorted by %q = %v, want %v", test.order, got, test.want)
+		}
+	}
+}
+
+func TestCompareEntriesTies(t *testing.T) {
+	// Entries with the same site name in different folders are ordered by filename
+	a := PasswordEntry{Filename: "work/github", SiteName: "github"}
+	b := PasswordEntry{Filename: "github", SiteName: "github"}
+	if compareEntries(a, b, SortRecent) <= 0 {
+		t.Error("entries that are level were not ordered by filename")
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	for _, order := range SortOrders {
+		if got := ParseSortOrder(string(order)); got != order {
+			t.Errorf("ParseSortOrder(%q) = %q", order, got)
+		}
+	}
+	for _, name := range []string{"", "Name", "size"} {
+		if got := ParseSortOrder(name); got != SortName {
+			t.Errorf("ParseSortOrder(%q) = %q, want the site name", name, got)
+		}
+	}
+}
+
+func TestNextSortOrder(t *testing.T) {
+	order := SortName
+	var seen []SortOrder
+	for range SortOrders {
+		seen = append(seen, order)
+		order = order.next()
+	}
+	if !slices.Equal(seen, SortOrders) || order != SortName {
+		t.Errorf("the sort key cycled through %v and then %q", seen, order)
+	}
+	if SortOrder("size").next() != SortOrders[0] {
+		t.Error("an unknown order does not move on to the first")
+	}
+}
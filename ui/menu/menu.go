@@ -78,7 +78,7 @@ func InitialMenuModel(options *types.Options) MenuModel {
 			},
 			{
 				Title:       "➕ Add New Password",
-				Description: "Create a new password entry",
+				Description: "Create a login, card, secure note or other entry",
 				Action:      "add",
 			},
 			{
@@ -86,11 +86,61 @@ func InitialMenuModel(options *types.Options) MenuModel {
 				Description: "Update your master password",
 				Action:      "change_master",
 			},
+			{
+				Title:       "📥 Import Passwords",
+				Description: "Import from Bitwarden, KeePass, 1Password or a browser",
+				Action:      "import",
+			},
 			{
 				Title:       "📤 Export Passwords",
 				Description: "Export passwords to file",
 				Action:      "export",
 			},
+			{
+				Title:       "💾 Create Backup",
+				Description: "Write an encrypted backup of the whole store",
+				Action:      "backup",
+			},
+			{
+				Title:       "♻️  Restore From Backup",
+				Description: "Restore entries from an encrypted backup",
+				Action:      "restore",
+			},
+			{
+				Title:       "🏷️  Tags",
+				Description: "Rename or merge tags across all entries",
+				Action:      "tags",
+			},
+			{
+				Title:       "🗑️  Trash",
+				Description: "Restore deleted entries or purge them for good",
+				Action:      "trash",
+			},
+			{
+				Title:       "🔐 Encryption Settings",
+				Description: "Choose the cipher and key derivation, then rekey all entries",
+				Action:      "crypto",
+			},
+			{
+				Title:       "👥 Recipients",
+				Description: "Share the vault by encrypting entries to teammates' public keys",
+				Action:      "recipients",
+			},
+			{
+				Title:       "🔃 Sync",
+				Description: "Keep the vault's history in git and sync it with a remote",
+				Action:      "sync",
+			},
+			{
+				Title:       "🗄️  Switch Vault",
+				Description: "Open another password store or add a new one",
+				Action:      "vault",
+			},
+			{
+				Title:       "🔒 Lock Now",
+				Description: "Lock the vault and return to the login screen",
+				Action:      "lock",
+			},
 			{
 				Title:       "🚪 Quit",
 				Description: "Exit the password manager",
@@ -143,7 +193,14 @@ func (m MenuModel) View() string {
 	var content strings.Builder
 
 	// Title
-	title := titleStyle.Render("🔐 Password Manager - Main Menu")
+	titleText := "🔐 Password Manager - Main Menu"
+	if m.options.Vault != "" {
+		titleText += fmt.Sprintf("\nVault: %s", m.options.Vault)
+	}
+	if m.options.ReadOnly {
+		titleText += "\n🔒 Read-only"
+	}
+	title := titleStyle.Render(titleText)
 	content.WriteString(title + "\n\n")
 
 	// Menu items with consistent width to prevent shifting
@@ -0,0 +1,204 @@
+// Package progress provides a screen that runs a task over a list of items one at a time,
+// showing a progress bar and collecting failures. Each item is processed in a Bubble Tea command
+// so the screen keeps redrawing while the work happens.
+package progress
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// barWidth is the number of cells in the progress bar
+const barWidth = 40
+
+// Failure records an item the task could not process
+type Failure struct {
+	Item string
+	Err  error
+}
+
+// itemDoneMsg reports that the item at index has been processed
+type itemDoneMsg struct {
+	index int
+	err   error
+}
+
+// ProgressModel represents the state of the progress screen
+type ProgressModel struct {
+	title    string
+	items    []string
+	work     func(item string) error
+	done     int
+	failures []Failure
+	finished bool
+	stopped  bool
+	options  *types.Options
+}
+
+// Progress screen styling
+var (
+	progressTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	progressContainerStyle = lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	barFilledStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	barEmptyStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#333333"))
+
+	currentItemStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true)
+
+	successStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#90EE90")).
+		Bold(true)
+
+	failureStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true)
+
+	progressHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewProgress creates a screen that runs work on every item in order
+func NewProgress(title string, items []string, work func(item string) error, options *types.Options) ProgressModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	return ProgressModel{
+		title:    title,
+		items:    items,
+		work:     work,
+		finished: len(items) == 0,
+		options:  options,
+	}
+}
+
+// Init implements the tea.Model interface and starts on the first item
+func (m ProgressModel) Init() tea.Cmd {
+	if m.finished {
+		return nil
+	}
+	return m.process(0)
+}
+
+// process returns a command that runs the task on the item at index
+func (m ProgressModel) process(index int) tea.Cmd {
+	item := m.items[index]
+	work := m.work
+	return func() tea.Msg {
+		return itemDoneMsg{index: index, err: work(item)}
+	}
+}
+
+// Update records finished items and schedules the next one
+func (m ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case itemDoneMsg:
+		m.done++
+		if msg.err != nil {
+			m.failures = append(m.failures, Failure{Item: m.items[msg.index], Err: msg.err})
+		}
+		if m.done == len(m.items) || m.stopped {
+			m.finished = true
+			return m, nil
+		}
+		return m, m.process(msg.index + 1)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			if m.finished {
+				m.options.Quit = false // Don't quit the entire app, just close the screen
+				return m, tea.Quit
+			}
+			// Stop after the item being processed; every item is handled on its own
+			m.stopped = true
+		case "enter":
+			if m.finished {
+				return m, tea.Quit
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the progress screen
+func (m ProgressModel) View() string {
+	var content strings.Builder
+
+	// Title
+	title := progressTitleStyle.Render(m.title)
+	content.WriteString(title + "\n\n")
+
+	body := ""
+	filled := barWidth
+	if len(m.items) > 0 {
+		filled = m.done * barWidth / len(m.items)
+	}
+	body += barFilledStyle.Render(strings.Repeat("█", filled)) + barEmptyStyle.Render(strings.Repeat("░", barWidth-filled))
+	body += fmt.Sprintf(" %d/%d\n\n", m.done, len(m.items))
+
+	switch {
+	case !m.finished:
+		body += currentItemStyle.Render("Processing "+m.items[m.done]) + "\n"
+		if m.stopped {
+			body += currentItemStyle.Render("Stopping after this item...") + "\n"
+		}
+	case len(m.failures) == 0 && m.done == len(m.items):
+		body += successStyle.Render(fmt.Sprintf("✅ All %d items done", m.done)) + "\n"
+	default:
+		body += successStyle.Render(fmt.Sprintf("✅ %d of %d items done", m.done-len(m.failures), len(m.items))) + "\n"
+		for _, failure := range m.failures {
+			body += failureStyle.Render(fmt.Sprintf("❌ %s: %v", failure.Item, failure.Err)) + "\n"
+		}
+	}
+
+	content.WriteString(progressContainerStyle.Render(body))
+
+	// Help text
+	help := "Esc: Stop"
+	if m.finished {
+		help = "Enter: Continue"
+	}
+	content.WriteString(progressHelpStyle.Render(help))
+
+	return content.String()
+}
+
+// GetFailures returns the items that could not be processed
+func (m ProgressModel) GetFailures() []Failure {
+	return m.failures
+}
+
+// Completed returns how many items were processed, successfully or not
+func (m ProgressModel) Completed() int {
+	return m.done
+}
+
+// IsFinished returns whether every item was processed or the run was stopped
+func (m ProgressModel) IsFinished() bool {
+	return m.finished
+}
@@ -0,0 +1,295 @@
+// Package recipients provides the screen used to review, add and remove the public keys a vault or folder is shared with.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package recipients
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Recipient is a public key shown in the list
+type Recipient struct {
+	Name        string // Primary user ID of the key
+	Fingerprint string // Hex fingerprint of the key
+}
+
+// RecipientsModel represents the state of the recipients screen
+type RecipientsModel struct {
+	recipients   []Recipient
+	own          string // Fingerprint of the local private key, empty if there is none
+	folder       string // Sub-folder being configured, empty for the whole vault
+	status       string // How the folder is currently encrypted
+	cursor       int
+	adding       bool
+	input        textinput.Model
+	errorMessage string
+	addPath      string
+	removed      string
+	passphrase   bool // Whether protecting the folder with a passphrase was requested
+	inherit      bool // Whether removing the folder's own encryption was requested
+	cancelled    bool
+	options      *types.Options
+}
+
+// Recipients screen styling
+var (
+	recipientsTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	recipientsContainerStyle = lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(80).
+		Align(lipgloss.Left)
+
+	recipientsLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Margin(0, 0, 0, 1)
+
+	recipientItemStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	selectedRecipientStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#7D56F4")).
+		Padding(0, 1).
+		Bold(true)
+
+	fingerprintStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Padding(0, 1)
+
+	recipientsNoteStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Margin(0, 0, 0, 1)
+
+	recipientsHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+
+	recipientsErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true).
+		Margin(0, 0, 1, 1)
+)
+
+// NewRecipientsScreen creates the screen listing the vault's recipients. own is the fingerprint
+// of the local private key, which is marked in the list and cannot be removed.
+func NewRecipientsScreen(recipients []Recipient, own string, options *types.Options) RecipientsModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	ti := textinput.New()
+	ti.Placeholder = "~/keys/teammate.asc"
+	ti.CharLimit = 300
+	ti.Width = 50
+
+	// Style the textinput
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+
+	return RecipientsModel{
+		recipients: recipients,
+		own:        own,
+		input:      ti,
+		options:    options,
+	}
+}
+
+// NewFolderScreen creates the screen for the encryption of a sub-folder. Besides managing
+// recipients it lets the user protect the folder with a passphrase or make it inherit the
+// encryption of its parent. status describes how the folder is encrypted now.
+func NewFolderScreen(folder, status string, recipients []Recipient, own string, options *types.Options) RecipientsModel {
+	m := NewRecipientsScreen(recipients, own, options)
+	m.folder = folder
+	m.status = status
+	return m
+}
+
+// Init implements the tea.Model interface
+func (m RecipientsModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles user input for the recipients screen
+func (m RecipientsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.adding {
+		return m.updateAdding(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc":
+		m.cancelled = true
+		m.options.Quit = false // Don't quit the entire app, just go back to the menu
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.recipients)-1 {
+			m.cursor++
+		}
+
+	case "a":
+		m.adding = true
+		m.errorMessage = ""
+		m.input.Focus()
+		return m, m.input.Cursor.BlinkCmd()
+
+	case "d", "delete":
+		if len(m.recipients) == 0 {
+			return m, nil
+		}
+		selected := m.recipients[m.cursor]
+		if selected.Fingerprint == m.own {
+			m.errorMessage = "You cannot remove your own key"
+			return m, nil
+		}
+		m.removed = selected.Fingerprint
+		return m, tea.Quit
+
+	case "p":
+		if m.folder != "" {
+			m.passphrase = true
+			return m, tea.Quit
+		}
+
+	case "i":
+		if m.folder != "" {
+			m.inherit = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// updateAdding handles input while the add recipient form is shown
+func (m RecipientsModel) updateAdding(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		// Leave the form and return to the list
+		m.adding = false
+		m.errorMessage = ""
+		m.input.Blur()
+		m.input.SetValue("")
+		return m, nil
+
+	case "enter":
+		path := strings.TrimSpace(m.input.Value())
+		if path == "" {
+			m.errorMessage = "Path to a public key file is required"
+			return m, nil
+		}
+		m.addPath = path
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// View renders the recipients screen
+func (m RecipientsModel) View() string {
+	var content strings.Builder
+
+	// Title
+	title := recipientsTitleStyle.Render("👥 Recipients")
+	if m.folder != "" {
+		title = recipientsTitleStyle.Render("📁 " + m.folder)
+	}
+	content.WriteString(title + "\n\n")
+
+	body := ""
+	if m.folder != "" && !m.adding {
+		body += recipientsLabelStyle.Render(m.status) + "\n\n"
+	}
+	if m.adding {
+		body += recipientsLabelStyle.Render("Public Key File") + "\n"
+		body += "  " + m.input.View() + "\n\n"
+		body += recipientsNoteStyle.Render("Export it with: gpg --export --armor <email> > key.asc") + "\n\n"
+	} else if len(m.recipients) == 0 && m.folder != "" {
+		body += recipientsNoteStyle.Render("Add a recipient to share only the entries in this folder,\nstarting with your own key, or press p to protect it with\nits own passphrase.") + "\n\n"
+	} else if len(m.recipients) == 0 {
+		body += recipientsNoteStyle.Render("This vault is encrypted with its master password only.\n\nAdding a recipient encrypts every entry to public keys instead,\nstarting with your own. From then on the vault is opened with\nyour private key passphrase.") + "\n\n"
+	} else {
+		for i, r := range m.recipients {
+			name := r.Name
+			if r.Fingerprint == m.own {
+				name += " (you)"
+			}
+			if i == m.cursor {
+				body += "  " + selectedRecipientStyle.Render("► "+name) + "\n"
+			} else {
+				body += "  " + recipientItemStyle.Render("  "+name) + "\n"
+			}
+			body += "    " + fingerprintStyle.Render(r.Fingerprint) + "\n"
+		}
+		body += "\n"
+	}
+	if m.errorMessage != "" {
+		body += recipientsErrorStyle.Render("❌ " + m.errorMessage)
+	}
+
+	content.WriteString(recipientsContainerStyle.Render(body))
+
+	// Help text
+	help := "↑↓: Navigate • a: Add Recipient • d: Remove • Esc: Back"
+	if m.folder != "" {
+		help = "↑↓: Navigate • a: Add Recipient • d: Remove • p: Passphrase • i: Inherit • Esc: Back"
+	}
+	if m.adding {
+		help = "Enter: Add • Esc: Back to List"
+	}
+	content.WriteString(recipientsHelpStyle.Render(help))
+
+	return content.String()
+}
+
+// GetAddPath returns the public key file entered in the add form, empty if none was added
+func (m RecipientsModel) GetAddPath() string {
+	return m.addPath
+}
+
+// GetRemoved returns the fingerprint of the recipient chosen for removal, empty if none was
+func (m RecipientsModel) GetRemoved() string {
+	return m.removed
+}
+
+// IsPassphraseRequested returns whether the user chose to protect the folder with a passphrase
+func (m RecipientsModel) IsPassphraseRequested() bool {
+	return m.passphrase
+}
+
+// IsInheritRequested returns whether the user chose to remove the folder's own encryption
+func (m RecipientsModel) IsInheritRequested() bool {
+	return m.inherit
+}
+
+// IsCancelled returns whether the screen was closed without a change
+func (m RecipientsModel) IsCancelled() bool {
+	return m.cancelled
+}
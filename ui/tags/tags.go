@@ -0,0 +1,180 @@
+// Package tags provides the view listing the tags used across the store with how many entries
+// have each. A tag can be renamed, or merged into another, on every entry at once.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package tags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Tag is a tag with the number of entries that have it
+type Tag struct {
+	Name  string
+	Count int
+}
+
+// Action is what the user asked to do with the selected tag
+type Action int
+
+const (
+	None   Action = iota // Closed the view
+	Rename               // Rename the selected tag
+	Merge                // Merge the selected tag into another
+)
+
+// TagsModel represents the state of the tag management view
+type TagsModel struct {
+	tags    []Tag
+	cursor  int
+	action  Action
+	options *types.Options
+}
+
+// Tag view styling
+var (
+	tagsTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	tagsContainerStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	tagsItemStyle = lipgloss.NewStyle().
+		Padding(0, 2)
+
+	tagsSelectedStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	tagsNoteStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Margin(0, 0, 1, 1)
+
+	tagsHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(4).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewTagsView creates the tag management view listing tags in the order given
+func NewTagsView(tags []Tag, options *types.Options) TagsModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	return TagsModel{
+		tags:    tags,
+		options: options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m TagsModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles user input for the tag management view
+func (m TagsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc", "q":
+		m.options.Quit = false // Don't quit the entire app, just go back to the menu
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.tags)-1 {
+			m.cursor++
+		}
+
+	case "enter", "r":
+		return m.request(Rename)
+
+	case "m":
+		// Merging needs another tag to merge into
+		if len(m.tags) > 1 {
+			return m.request(Merge)
+		}
+	}
+	return m, nil
+}
+
+// request closes the view with an action, if there is a tag to apply it to
+func (m TagsModel) request(action Action) (tea.Model, tea.Cmd) {
+	if len(m.tags) == 0 {
+		return m, nil
+	}
+	m.action = action
+	return m, tea.Quit
+}
+
+// View renders the tag management view
+func (m TagsModel) View() string {
+	var content strings.Builder
+
+	// Title
+	content.WriteString(tagsTitleStyle.Render("🏷️  Tags") + "\n\n")
+
+	body := ""
+	if len(m.tags) == 0 {
+		body += tagsNoteStyle.Render("No entries have tags yet.\nAdd tags to an entry in the Tags field when adding or editing it.") + "\n"
+	} else {
+		body += tagsNoteStyle.Render("Renaming or merging changes every entry with the tag at once.\nRenaming a tag to one that already exists merges them.") + "\n"
+	}
+
+	for i, tag := range m.tags {
+		entries := "entries"
+		if tag.Count == 1 {
+			entries = "entry"
+		}
+		line := fmt.Sprintf("#%-40s %d %s", tag.Name, tag.Count, entries)
+		if i == m.cursor {
+			body += tagsSelectedStyle.Render("► "+line) + "\n"
+		} else {
+			body += tagsItemStyle.Render("  "+line) + "\n"
+		}
+	}
+
+	content.WriteString(tagsContainerStyle.Render(body))
+
+	// Help text
+	help := tagsHelpStyle.Render("↑↓: Navigate • Enter/r: Rename • m: Merge Into Another Tag • Esc: Back")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// GetAction returns what the user asked to do, None if the view was closed
+func (m TagsModel) GetAction() Action {
+	return m.action
+}
+
+// GetSelectedIndex returns the position of the selected tag in the list given to NewTagsView
+func (m TagsModel) GetSelectedIndex() int {
+	return m.cursor
+}
@@ -0,0 +1,189 @@
+// Package trash provides the view listing deleted password entries.
+// Entries in the trash can be restored to the store or purged for good.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package trash
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Fozzyack/password-manager/encryption"
+	"github.com/Fozzyack/password-manager/fileio"
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/Fozzyack/password-manager/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Action is what the user asked to do with the trash
+type Action int
+
+const (
+	None    Action = iota // Closed the view
+	Restore               // Restore the selected entry
+	Purge                 // Purge the selected entry
+	Empty                 // Purge every entry
+)
+
+// TrashModel represents the state of the trash view
+type TrashModel struct {
+	entries   []fileio.TrashedEntry
+	retention time.Duration // How long entries are kept, 0 if until purged by hand
+	cursor    int
+	action    Action
+	options   *types.Options
+}
+
+// Trash view styling
+var (
+	trashTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	trashContainerStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(80).
+		Align(lipgloss.Left)
+
+	trashItemStyle = lipgloss.NewStyle().
+		Padding(0, 2)
+
+	trashSelectedStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		Background(lipgloss.Color("#7D56F4")).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true)
+
+	trashDetailStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(6)
+
+	trashNoteStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Margin(0, 0, 1, 1)
+
+	trashHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		PaddingLeft(4).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+)
+
+// NewTrashView creates the trash view. entries are listed in the order given, and each shows
+// when it will be purged if retention is set.
+func NewTrashView(entries []fileio.TrashedEntry, retention time.Duration, options *types.Options) TrashModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	return TrashModel{
+		entries:   entries,
+		retention: retention,
+		options:   options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m TrashModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles user input for the trash view
+func (m TrashModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc", "q":
+		m.options.Quit = false // Don't quit the entire app, just go back to the menu
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+
+	case "enter", "r":
+		return m.request(Restore)
+
+	case "d", "delete":
+		return m.request(Purge)
+
+	case "D":
+		return m.request(Empty)
+	}
+	return m, nil
+}
+
+// request closes the view with an action, if there is anything in the trash to apply it to
+func (m TrashModel) request(action Action) (tea.Model, tea.Cmd) {
+	if len(m.entries) == 0 {
+		return m, nil
+	}
+	m.action = action
+	return m, tea.Quit
+}
+
+// View renders the trash view
+func (m TrashModel) View() string {
+	var content strings.Builder
+
+	// Title
+	content.WriteString(trashTitleStyle.Render("🗑️  Trash") + "\n\n")
+
+	body := ""
+	if len(m.entries) == 0 {
+		body += trashNoteStyle.Render("The trash is empty.\nDeleted entries are kept here so they can be restored.") + "\n"
+	} else if m.retention > 0 {
+		body += trashNoteStyle.Render(fmt.Sprintf("Entries are purged %d days after they were deleted.", int(m.retention.Hours()/24))) + "\n"
+	}
+
+	for i, entry := range m.entries {
+		line := fmt.Sprintf("%-40s Deleted %s", utils.TruncateString(utils.ParseFilenameToSiteName(entry.Filename), 39), utils.FormatTimestampForDisplay(entry.DeletedAt))
+		if i != m.cursor {
+			body += trashItemStyle.Render("  "+line) + "\n"
+			continue
+		}
+		body += trashSelectedStyle.Render("► "+line) + "\n"
+		if folder := encryption.ParentFolder(entry.Filename); folder != "" {
+			body += trashDetailStyle.Render("Folder: "+folder) + "\n"
+		}
+		body += trashDetailStyle.Render("File:   "+entry.Filename+".gpg") + "\n"
+		if m.retention > 0 {
+			body += trashDetailStyle.Render("Purged: "+utils.FormatTimestampForDisplay(entry.DeletedAt.Add(m.retention))) + "\n"
+		}
+	}
+
+	content.WriteString(trashContainerStyle.Render(body))
+
+	// Help text
+	help := trashHelpStyle.Render("↑↓: Navigate • Enter/r: Restore • d: Purge • D: Empty Trash • Esc: Back")
+	content.WriteString(help)
+
+	return content.String()
+}
+
+// GetAction returns what the user asked to do, None if the view was closed
+func (m TrashModel) GetAction() Action {
+	return m.action
+}
+
+// GetSelectedIndex returns the position of the selected entry in the list given to NewTrashView
+func (m TrashModel) GetSelectedIndex() int {
+	return m.cursor
+}
@@ -0,0 +1,289 @@
+// Package vault provides the screen used to switch between named password stores and register new ones.
+// It uses Bubble Tea for TUI functionality and maintains consistent styling with the rest of the application.
+package vault
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fozzyack/password-manager/types"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Inputs of the add vault form
+const (
+	nameField = iota
+	locationField
+)
+
+// Vault is a named store shown in the picker
+type Vault struct {
+	Name     string // Name used in the configuration file and with --vault
+	Location string // Path of the store directory
+}
+
+// VaultModel represents the state of the vault picker
+type VaultModel struct {
+	vaults       []Vault
+	current      string
+	cursor       int
+	adding       bool
+	inputs       []textinput.Model
+	focus        int
+	errorMessage string
+	selected     string
+	added        bool
+	cancelled    bool
+	options      *types.Options
+}
+
+// Vault picker styling
+var (
+	vaultTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Align(lipgloss.Center)
+
+	vaultContainerStyle = lipgloss.NewStyle().
+		Padding(2, 4).
+		Margin(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Width(70).
+		Align(lipgloss.Left)
+
+	vaultLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		Margin(0, 0, 0, 1)
+
+	vaultItemStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	selectedVaultStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(lipgloss.Color("#7D56F4")).
+		Padding(0, 1).
+		Bold(true)
+
+	vaultLocationStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Padding(0, 1)
+
+	vaultHelpStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		Italic(true).
+		Align(lipgloss.Center).
+		Margin(1, 0)
+
+	vaultErrorStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FF5F87")).
+		Bold(true).
+		Margin(0, 0, 1, 1)
+)
+
+// NewVaultPicker creates a picker listing the given vaults with the cursor on the current one
+func NewVaultPicker(vaults []Vault, current string, options *types.Options) VaultModel {
+	// Clear screen for clean display
+	fmt.Print("\033[2J\033[H")
+
+	inputs := make([]textinput.Model, 2)
+	for i := range inputs {
+		ti := textinput.New()
+		ti.CharLimit = 300
+		ti.Width = 50
+
+		// Style the textinput
+		ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+		ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+		ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Italic(true)
+		inputs[i] = ti
+	}
+	inputs[nameField].Placeholder = "work"
+	inputs[locationField].Placeholder = "~/vaults/work"
+
+	cursor := 0
+	for i, v := range vaults {
+		if v.Name == current {
+			cursor = i
+		}
+	}
+
+	return VaultModel{
+		vaults:  vaults,
+		current: current,
+		cursor:  cursor,
+		inputs:  inputs,
+		options: options,
+	}
+}
+
+// Init implements the tea.Model interface
+func (m VaultModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles user input for the vault picker
+func (m VaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.adding {
+		return m.updateAdding(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c", "esc":
+		m.cancelled = true
+		m.options.Quit = false // Don't quit the entire app, just go back to the menu
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case "down", "j":
+		if m.cursor < len(m.vaults)-1 {
+			m.cursor++
+		}
+
+	case "enter", " ":
+		m.selected = m.vaults[m.cursor].Name
+		return m, tea.Quit
+
+	case "a":
+		m.adding = true
+		m.focus = nameField
+		m.errorMessage = ""
+		m.inputs[nameField].Focus()
+		return m, m.inputs[nameField].Cursor.BlinkCmd()
+	}
+	return m, nil
+}
+
+// updateAdding handles input while the add vault form is shown
+func (m VaultModel) updateAdding(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		// Leave the form and return to the list
+		m.adding = false
+		m.errorMessage = ""
+		for i := range m.inputs {
+			m.inputs[i].Blur()
+			m.inputs[i].SetValue("")
+		}
+		return m, nil
+
+	case "tab", "shift+tab", "up", "down":
+		return m.switchField()
+
+	case "enter":
+		if m.focus == nameField {
+			return m.switchField()
+		}
+		name, location := m.GetNewVault()
+		switch {
+		case name == "" || location == "":
+			m.errorMessage = "Name and location are both required"
+		case m.hasVault(name):
+			m.errorMessage = fmt.Sprintf("A vault named '%s' already exists", name)
+		default:
+			m.added = true
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+// switchField moves focus to the other input of the add vault form
+func (m VaultModel) switchField() (tea.Model, tea.Cmd) {
+	m.inputs[m.focus].Blur()
+	m.focus = (m.focus + 1) % len(m.inputs)
+	m.inputs[m.focus].Focus()
+	return m, m.inputs[m.focus].Cursor.BlinkCmd()
+}
+
+// hasVault reports whether a vault with the given name is already listed
+func (m VaultModel) hasVault(name string) bool {
+	for _, v := range m.vaults {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// View renders the vault picker interface
+func (m VaultModel) View() string {
+	var content strings.Builder
+
+	// Title
+	title := vaultTitleStyle.Render("🗄️  Switch Vault")
+	content.WriteString(title + "\n\n")
+
+	body := ""
+	if m.adding {
+		body += vaultLabelStyle.Render("Vault Name") + "\n"
+		body += "  " + m.inputs[nameField].View() + "\n\n"
+		body += vaultLabelStyle.Render("Store Location") + "\n"
+		body += "  " + m.inputs[locationField].View() + "\n\n"
+		if m.errorMessage != "" {
+			body += vaultErrorStyle.Render("❌ "+m.errorMessage) + "\n"
+		}
+	} else {
+		for i, v := range m.vaults {
+			name := v.Name
+			if v.Name == m.current {
+				name += " (current)"
+			}
+			if i == m.cursor {
+				body += "  " + selectedVaultStyle.Render("► "+name) + "\n"
+			} else {
+				body += "  " + vaultItemStyle.Render("  "+name) + "\n"
+			}
+			body += "    " + vaultLocationStyle.Render(v.Location) + "\n"
+		}
+	}
+
+	content.WriteString(vaultContainerStyle.Render(body))
+
+	// Help text
+	help := "↑↓: Navigate • Enter: Switch • a: Add Vault • Esc: Back"
+	if m.adding {
+		help = "Tab: Switch Field • Enter: Next/Save • Esc: Back to List"
+	}
+	content.WriteString(vaultHelpStyle.Render(help))
+
+	return content.String()
+}
+
+// GetSelected returns the name of the vault chosen by the user
+func (m VaultModel) GetSelected() string {
+	return m.selected
+}
+
+// GetNewVault returns the name and location entered in the add vault form
+func (m VaultModel) GetNewVault() (string, string) {
+	return strings.TrimSpace(m.inputs[nameField].Value()), strings.TrimSpace(m.inputs[locationField].Value())
+}
+
+// IsAdded returns whether the user submitted a new vault
+func (m VaultModel) IsAdded() bool {
+	return m.added
+}
+
+// IsCancelled returns whether the picker was closed without a choice
+func (m VaultModel) IsCancelled() bool {
+	return m.cancelled
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/big"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
@@ -209,14 +210,7 @@ func EvaluatePasswordStrength(password string) (int, string) {
 // GenerateFilename creates a unique filename for storing password entries
 func GenerateFilename(siteName string) string {
 	// Clean the site name for use as filename
-	reg := regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
-	cleanName := reg.ReplaceAllString(siteName, "_")
-	cleanName = strings.ToLower(cleanName)
-	
-	// Limit length
-	if len(cleanName) > 20 {
-		cleanName = cleanName[:20]
-	}
+	cleanName := CleanSiteName(siteName)
 	
 	// Add timestamp for uniqueness
 	timestamp := time.Now().Format("20060102_150405")
@@ -227,6 +221,30 @@ func GenerateFilename(siteName string) string {
 	return filename
 }
 
+// CleanSiteName converts a site name into the filename-safe prefix used by GenerateFilename
+func CleanSiteName(siteName string) string {
+	reg := regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
+	cleanName := reg.ReplaceAllString(siteName, "_")
+	cleanName = strings.ToLower(cleanName)
+	
+	// Limit length
+	if len(cleanName) > 20 {
+		cleanName = cleanName[:20]
+	}
+	return cleanName
+}
+
+// filenameTimestampRegex matches the _YYYYMMDD_HHMMSS suffix added by GenerateFilename,
+// including the optional _N counter used when several entries share the same second
+var filenameTimestampRegex = regexp.MustCompile(`_\d{8}_\d{6}(_\d+)?$`)
+
+// StripFilenameTimestamp removes the .gpg extension and timestamp suffix from a filename,
+// returning the cleaned site name prefix
+func StripFilenameTimestamp(filename string) string {
+	filename = strings.TrimSuffix(filename, ".gpg")
+	return filenameTimestampRegex.ReplaceAllString(filename, "")
+}
+
 // SanitizeInput removes potentially dangerous characters from user input
 func SanitizeInput(input string) string {
 	// Remove control characters and normalize whitespace
@@ -239,30 +257,57 @@ func SanitizeInput(input string) string {
 	return sanitized
 }
 
-// ParseFilenameToSiteName converts a filename back to a readable site name
-func ParseFilenameToSiteName(filename string) string {
-	// Remove .gpg extension if present
-	if strings.HasSuffix(filename, ".gpg") {
-		filename = strings.TrimSuffix(filename, ".gpg")
-	}
-	
-	// Find the last underscore (timestamp separator)
-	lastUnderscore := strings.LastIndex(filename, "_")
-	if lastUnderscore == -1 {
-		// No timestamp found, return as is with underscores replaced by spaces
-		return strings.ReplaceAll(filename, "_", " ")
+// CleanFolderPath converts a folder such as "Work/AWS prod/" into the store-relative path
+// entries are kept under, "work/aws_prod". Empty, "." and ".." segments are dropped.
+func CleanFolderPath(folder string) string {
+	var segments []string
+	for _, segment := range strings.Split(folder, "/") {
+		segment = strings.TrimSpace(segment)
+		if segment == "" || segment == "." || segment == ".." {
+			continue
+		}
+		segments = append(segments, CleanSiteName(segment))
 	}
-	
-	// Check if what follows the last underscore looks like a timestamp
-	potentialTimestamp := filename[lastUnderscore+1:]
-	if len(potentialTimestamp) == 15 && strings.Contains(potentialTimestamp, "_") {
-		// This looks like our timestamp format (YYYYMMDD_HHMMSS)
-		siteName := filename[:lastUnderscore]
-		return strings.ReplaceAll(siteName, "_", " ")
+	return strings.Join(segments, "/")
+}
+
+// ParseTags splits a comma separated list such as "Work, #Finance, home office" into the tags
+// stored with an entry: lower case, without a leading '#', with spaces turned into dashes and
+// each tag once, in the order given
+func ParseTags(input string) []string {
+	var tags []string
+	for _, tag := range strings.Split(input, ",") {
+		tag = CleanTag(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
 	}
+	return tags
+}
+
+// CleanTag converts a single tag as typed into the form it is stored in, e.g. "#Home office"
+// becomes "home-office"
+func CleanTag(tag string) string {
+	tag = strings.TrimLeft(SanitizeInput(tag), "#")
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
+
+// FormatTags joins tags for editing in a form, the way ParseTags reads them back
+func FormatTags(tags []string) string {
+	return strings.Join(tags, ", ")
+}
+
+// ParseFilenameToSiteName converts a filename back to a readable site name.
+// Entries in sub-folders are named after the file alone, without the folder.
+func ParseFilenameToSiteName(filename string) string {
+	// Drop the folder, if any
+	filename = filename[strings.LastIndex(filename, "/")+1:]
+
+	// Remove .gpg extension and our timestamp format (YYYYMMDD_HHMMSS) if present
+	siteName := StripFilenameTimestamp(filename)
 	
-	// No valid timestamp found, return as is with underscores replaced
-	return strings.ReplaceAll(filename, "_", " ")
+	// Replace underscores with spaces for display
+	return strings.ReplaceAll(siteName, "_", " ")
 }
 
 // FormatTimestampForDisplay formats a time.Time for user-friendly display
@@ -303,4 +348,12 @@ func TruncateString(s string, maxLen int) string {
 		return s[:maxLen]
 	}
 	return s[:maxLen-3] + "..."
-}
\ No newline at end of file
+}
+
+// FormatSize formats a size in bytes for display, e.g. "512 B" or "12.5 KB"
+func FormatSize(size int) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f KB", float64(size)/1024)
+}